@@ -0,0 +1,211 @@
+// Package analysis turns analysis schemas into Bland analysis requests and
+// checks the answers Bland returns against the type of each question.
+//
+// Bland answers every question with free text. Parse converts each answer
+// to the type its question expects: booleans accept yes/no and true/false,
+// optionally followed by an explanation; numbers accept a single number with
+// currency symbols, thousands separators or a percent sign; enum answers
+// must name exactly one of the options. Answers that cannot be converted are
+// reported as issues and left null.
+package analysis
+
+import (
+	"bland/model"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Issue codes reported by Parse.
+const (
+	IssueMissing        = "missing"
+	IssueInvalidBoolean = "invalid_boolean"
+	IssueInvalidNumber  = "invalid_number"
+	IssueInvalidOption  = "invalid_option"
+)
+
+var (
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	numberRe  = regexp.MustCompile(`-?\d[\d,]*(\.\d+)?|-?\.\d+`)
+	wordRe    = regexp.MustCompile(`[A-Za-z0-9]+`)
+)
+
+// ValidateSchema checks the questions of a schema and returns a FieldError for each problem.
+func ValidateSchema(questions []model.AnalysisQuestion) []model.FieldError {
+	var fields []model.FieldError
+	add := func(i int, field, code, message string) {
+		fields = append(fields, model.FieldError{Field: fmt.Sprintf("questions[%d].%s", i, field), Code: code, Message: message})
+	}
+
+	seen := make(map[string]bool, len(questions))
+	for i, q := range questions {
+		switch {
+		case q.Name == "":
+			add(i, "name", "required", "name is required")
+		case !validName.MatchString(q.Name):
+			add(i, "name", "invalid_name", "name must start with a letter or underscore and contain only letters, digits and underscores")
+		case seen[q.Name]:
+			add(i, "name", "duplicate", fmt.Sprintf("name %q is used by another question", q.Name))
+		}
+		seen[q.Name] = true
+
+		if strings.TrimSpace(q.Question) == "" {
+			add(i, "question", "required", "question is required")
+		}
+
+		switch q.Type {
+		case model.AnalysisTypeBoolean, model.AnalysisTypeNumber, model.AnalysisTypeString:
+			if len(q.Options) > 0 {
+				add(i, "options", "not_allowed", "options are only allowed for enum questions")
+			}
+		case model.AnalysisTypeEnum:
+			if len(q.Options) < 2 {
+				add(i, "options", "min", "an enum question needs at least 2 options")
+			}
+			options := make(map[string]bool, len(q.Options))
+			for _, option := range q.Options {
+				key := strings.ToLower(strings.TrimSpace(option))
+				if key == "" {
+					add(i, "options", "required", "options must not be empty")
+				} else if options[key] {
+					add(i, "options", "duplicate", fmt.Sprintf("option %q is listed twice", option))
+				}
+				options[key] = true
+			}
+		case "":
+			add(i, "type", "required", "type is required")
+		default:
+			add(i, "type", "oneof", "type must be one of: boolean number enum string")
+		}
+	}
+	return fields
+}
+
+// Request builds the Bland analysis request for a schema. Each question is
+// sent with a description of the expected answer.
+func Request(schema model.AnalysisSchema) model.AnalyzeCallRequest {
+	questions := make([][]string, 0, len(schema.Questions))
+	for _, q := range schema.Questions {
+		questions = append(questions, []string{q.Question, expectation(q)})
+	}
+	return model.AnalyzeCallRequest{Goal: schema.Goal, Questions: questions}
+}
+
+// expectation describes the answer expected for a question, in the words Bland is given.
+func expectation(q model.AnalysisQuestion) string {
+	var expected string
+	switch q.Type {
+	case model.AnalysisTypeBoolean:
+		expected = "boolean (answer yes or no)"
+	case model.AnalysisTypeNumber:
+		expected = "number (answer with digits only)"
+	case model.AnalysisTypeEnum:
+		expected = "one of: " + strings.Join(q.Options, ", ")
+	default:
+		expected = "string"
+	}
+	if q.Description != "" {
+		expected += " - " + q.Description
+	}
+	return expected
+}
+
+// Parse pairs the answers returned by Bland with the questions of a schema,
+// in order, and converts each to its question's type. Invalid and missing
+// answers are reported as issues and their typed value is nil.
+func Parse(schema model.AnalysisSchema, answers []string) (typed map[string]interface{}, raw map[string]string, issues []model.AnalysisIssue) {
+	typed = make(map[string]interface{}, len(schema.Questions))
+	raw = make(map[string]string, len(schema.Questions))
+	for i, q := range schema.Questions {
+		if i >= len(answers) {
+			typed[q.Name] = nil
+			issues = append(issues, model.AnalysisIssue{Question: q.Name, Code: IssueMissing, Message: "Bland returned no answer"})
+			continue
+		}
+
+		answer := strings.TrimSpace(answers[i])
+		raw[q.Name] = answers[i]
+		value, issue := convert(q, answer)
+		typed[q.Name] = value
+		if issue != nil {
+			issue.Question = q.Name
+			issue.Answer = answers[i]
+			issues = append(issues, *issue)
+		}
+	}
+	return typed, raw, issues
+}
+
+// convert returns the answer as the type of its question, or an issue.
+func convert(q model.AnalysisQuestion, answer string) (interface{}, *model.AnalysisIssue) {
+	switch q.Type {
+	case model.AnalysisTypeBoolean:
+		if value, ok := parseBool(answer); ok {
+			return value, nil
+		}
+		return nil, &model.AnalysisIssue{Code: IssueInvalidBoolean, Message: "answer is not yes or no"}
+	case model.AnalysisTypeNumber:
+		if value, ok := parseNumber(answer); ok {
+			return value, nil
+		}
+		return nil, &model.AnalysisIssue{Code: IssueInvalidNumber, Message: "answer is not a number"}
+	case model.AnalysisTypeEnum:
+		if value, ok := matchOption(q.Options, answer); ok {
+			return value, nil
+		}
+		return nil, &model.AnalysisIssue{Code: IssueInvalidOption, Message: "answer is not one of: " + strings.Join(q.Options, ", ")}
+	default:
+		return answer, nil
+	}
+}
+
+// parseBool reads a yes/no answer from its first word, e.g. "Yes, they agreed".
+func parseBool(answer string) (bool, bool) {
+	first := strings.ToLower(wordRe.FindString(answer))
+	switch first {
+	case "yes", "true", "y":
+		return true, true
+	case "no", "false", "n":
+		return false, true
+	}
+	return false, false
+}
+
+// parseNumber reads the only number in an answer, e.g. "$5,000" or "about 20%".
+func parseNumber(answer string) (float64, bool) {
+	matches := numberRe.FindAllString(answer, -1)
+	if len(matches) != 1 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(matches[0], ",", ""), 64)
+	return value, err == nil
+}
+
+// matchOption returns the option an answer names: the answer itself, or
+// the only option that appears in it as whole words.
+func matchOption(options []string, answer string) (string, bool) {
+	normalized := normalizeWords(answer)
+	for _, option := range options {
+		if normalizeWords(option) == normalized {
+			return option, true
+		}
+	}
+
+	var found []string
+	padded := " " + normalized + " "
+	for _, option := range options {
+		if strings.Contains(padded, " "+normalizeWords(option)+" ") {
+			found = append(found, option)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true
+	}
+	return "", false
+}
+
+// normalizeWords lowercases s and keeps only its words, separated by single spaces.
+func normalizeWords(s string) string {
+	return strings.ToLower(strings.Join(wordRe.FindAllString(s, -1), " "))
+}
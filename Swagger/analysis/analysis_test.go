@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"bland/model"
+	"reflect"
+	"testing"
+)
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		questions []model.AnalysisQuestion
+		want      []string
+	}{
+		{"valid", []model.AnalysisQuestion{
+			{Name: "interested", Question: "Interested?", Type: model.AnalysisTypeBoolean},
+			{Name: "slot", Question: "Which slot?", Type: model.AnalysisTypeEnum, Options: []string{"morning", "evening"}},
+		}, nil},
+		{"bad name and missing question", []model.AnalysisQuestion{{Name: "1st", Type: model.AnalysisTypeString}}, []string{"questions[0].name", "questions[0].question"}},
+		{"duplicate name", []model.AnalysisQuestion{
+			{Name: "a", Question: "A?", Type: model.AnalysisTypeString},
+			{Name: "a", Question: "B?", Type: model.AnalysisTypeString},
+		}, []string{"questions[1].name"}},
+		{"unknown type", []model.AnalysisQuestion{{Name: "a", Question: "A?", Type: "date"}}, []string{"questions[0].type"}},
+		{"options on a number", []model.AnalysisQuestion{{Name: "a", Question: "A?", Type: model.AnalysisTypeNumber, Options: []string{"1"}}}, []string{"questions[0].options"}},
+		{"enum with one option", []model.AnalysisQuestion{{Name: "a", Question: "A?", Type: model.AnalysisTypeEnum, Options: []string{"x"}}}, []string{"questions[0].options"}},
+		{"duplicate option", []model.AnalysisQuestion{{Name: "a", Question: "A?", Type: model.AnalysisTypeEnum, Options: []string{"x", " X"}}}, []string{"questions[0].options"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, field := range ValidateSchema(tt.questions) {
+				got = append(got, field.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequest(t *testing.T) {
+	schema := model.AnalysisSchema{Goal: "Qualify", Questions: []model.AnalysisQuestion{
+		{Name: "slot", Question: "Which slot?", Type: model.AnalysisTypeEnum, Options: []string{"morning", "evening"}, Description: "As agreed"},
+	}}
+	want := model.AnalyzeCallRequest{Goal: "Qualify", Questions: [][]string{{"Which slot?", "one of: morning, evening - As agreed"}}}
+	if got := Request(schema); !reflect.DeepEqual(got, want) {
+		t.Errorf("Request = %+v, want %+v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	schema := model.AnalysisSchema{Questions: []model.AnalysisQuestion{
+		{Name: "interested", Type: model.AnalysisTypeBoolean},
+		{Name: "budget", Type: model.AnalysisTypeNumber},
+		{Name: "slot", Type: model.AnalysisTypeEnum, Options: []string{"morning", "late evening"}},
+		{Name: "notes", Type: model.AnalysisTypeString},
+		{Name: "missing", Type: model.AnalysisTypeString},
+	}}
+	tests := []struct {
+		name    string
+		answers []string
+		typed   map[string]interface{}
+		issues  []string
+	}{
+		{
+			"valid answers",
+			[]string{"Yes, they agreed", "$5,000", "In the late evening please", " Call back "},
+			map[string]interface{}{"interested": true, "budget": 5000.0, "slot": "late evening", "notes": "Call back", "missing": nil},
+			[]string{IssueMissing},
+		},
+		{
+			"invalid answers",
+			[]string{"Maybe", "between 10 and 20", "morning or late evening", "", ""},
+			map[string]interface{}{"interested": nil, "budget": nil, "slot": nil, "notes": "", "missing": ""},
+			[]string{IssueInvalidBoolean, IssueInvalidNumber, IssueInvalidOption},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typed, raw, issues := Parse(schema, tt.answers)
+			if !reflect.DeepEqual(typed, tt.typed) {
+				t.Errorf("typed = %v, want %v", typed, tt.typed)
+			}
+			if raw["interested"] != tt.answers[0] {
+				t.Errorf("raw answer = %q, want %q", raw["interested"], tt.answers[0])
+			}
+			var codes []string
+			for _, issue := range issues {
+				codes = append(codes, issue.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.issues) {
+				t.Errorf("issues = %v, want %v", codes, tt.issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package blandclient
+
+import (
+	"bland/model"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SendCall dispatches an outbound call using a pathway.
+func (c *Client) SendCall(ctx context.Context, request model.SendCall) (*model.CallResponse, error) {
+	var response model.CallResponse
+	if _, _, err := c.do(ctx, http.MethodPost, c.Endpoints.Calls+"/v1/calls", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AnalyzeCall runs an AI analysis of a completed call.
+func (c *Client) AnalyzeCall(ctx context.Context, callID string, request model.AnalyzeCallRequest) (*model.AnalyzeCallResponse, error) {
+	var response model.AnalyzeCallResponse
+	endpoint := c.Endpoints.Calls + "/v1/calls/" + url.PathEscape(callID) + "/analyze"
+	if _, _, err := c.do(ctx, http.MethodPost, endpoint, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StopCall ends an active call.
+func (c *Client) StopCall(ctx context.Context, callID string) (*model.StopCallResponse, error) {
+	var response model.StopCallResponse
+	endpoint := c.Endpoints.Calls + "/v1/calls/" + url.PathEscape(callID) + "/stop"
+	raw, status, err := c.do(ctx, http.MethodPost, endpoint, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status != "success" {
+		return nil, &APIError{StatusCode: status, Message: response.Message, Body: raw}
+	}
+	return &response, nil
+}
+
+// GetCall retrieves the details, metadata and transcripts of a call.
+func (c *Client) GetCall(ctx context.Context, callID string) (*model.CallDetail, error) {
+	var detail model.CallDetail
+	endpoint := c.Endpoints.Calls + "/v1/calls/" + url.PathEscape(callID)
+	if _, _, err := c.do(ctx, http.MethodGet, endpoint, nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// Recording is an open download of a call recording. The caller must close Body.
+type Recording struct {
+	StatusCode    int    // 200, or 206 when a range was requested
+	ContentType   string // Media type of the audio, e.g. audio/mpeg
+	ContentLength int64  // Length of Body, or -1 when unknown
+	ContentRange  string // Content-Range of a partial response
+	AcceptRanges  string // Accept-Ranges announced by the recording host
+	ETag          string
+	LastModified  string
+	Body          io.ReadCloser
+}
+
+// GetRecording downloads the audio at recordingURL, usually CallDetail.RecordingURL.
+// rangeHeader, when set, is sent as the Range header to request part of the recording.
+// The Authorization token is only sent when recordingURL is on the calls API host,
+// so it never reaches the storage service behind pre-signed recording URLs.
+func (c *Client) GetRecording(ctx context.Context, recordingURL, rangeHeader string) (*Recording, error) {
+	target, err := url.Parse(recordingURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return nil, fmt.Errorf("blandclient: invalid recording URL %q", recordingURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, recordingURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("blandclient: create request: %w", err)
+	}
+	if calls, err := url.Parse(c.Endpoints.Calls); err == nil && strings.EqualFold(calls.Host, target.Host) {
+		if c.Token == "" {
+			return nil, ErrMissingToken
+		}
+		req.Header.Set("Authorization", c.Token)
+	}
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("blandclient: GET %s: %w", recordingURL, err)
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		defer res.Body.Close()
+		raw, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+		return nil, &APIError{StatusCode: res.StatusCode, Message: errorMessage(res.StatusCode, raw), Body: raw}
+	}
+
+	return &Recording{
+		StatusCode:    res.StatusCode,
+		ContentType:   res.Header.Get("Content-Type"),
+		ContentLength: res.ContentLength,
+		ContentRange:  res.Header.Get("Content-Range"),
+		AcceptRanges:  res.Header.Get("Accept-Ranges"),
+		ETag:          res.Header.Get("ETag"),
+		LastModified:  res.Header.Get("Last-Modified"),
+		Body:          res.Body,
+	}, nil
+}
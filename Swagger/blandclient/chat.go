@@ -0,0 +1,35 @@
+package blandclient
+
+import (
+	"bland/model"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CreateChat creates a chat instance for testing a pathway.
+func (c *Client) CreateChat(ctx context.Context, request model.CreateChatRequest) (*model.CreateChatResponse, error) {
+	var response model.CreateChatResponse
+	raw, status, err := c.do(ctx, http.MethodPost, c.Endpoints.ChatCreate+"/v1/pathway/chat/create", request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil {
+		return nil, &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return &response, nil
+}
+
+// SendChatMessage sends a user message to a pathway chat and returns the assistant's reply.
+func (c *Client) SendChatMessage(ctx context.Context, chatID string, request model.SendMessageRequest) (*model.SendMessageResponse, error) {
+	var response model.SendMessageResponse
+	endpoint := c.Endpoints.Chat + "/v1/pathway/chat/" + url.PathEscape(chatID)
+	raw, status, err := c.do(ctx, http.MethodPost, endpoint, request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil {
+		return nil, &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return &response, nil
+}
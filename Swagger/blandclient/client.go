@@ -0,0 +1,151 @@
+// Package blandclient is a typed Go client for the Bland AI REST API.
+//
+// It is used by the Gin proxy in this repository but has no dependency on
+// Gin, so other services can talk to Bland directly with the same request
+// and response types defined in the model package.
+package blandclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultBaseURL is the Bland API host used for calls, pathways and chat messages.
+	DefaultBaseURL = "https://api.bland.ai"
+	// DefaultRegionalBaseURL is the Bland API host used for folders and chat creation.
+	DefaultRegionalBaseURL = "https://us.api.bland.ai"
+)
+
+// Endpoints holds the upstream base URL for each Bland API family.
+// Base URLs have no trailing slash and no /v1 suffix.
+type Endpoints struct {
+	Calls      string `json:"calls" yaml:"calls"`             // /v1/calls
+	Pathways   string `json:"pathways" yaml:"pathways"`       // /v1/convo_pathway
+	Folders    string `json:"folders" yaml:"folders"`         // /v1/pathway/folders
+	Chat       string `json:"chat" yaml:"chat"`               // /v1/pathway/chat/{chat_id}
+	ChatCreate string `json:"chat_create" yaml:"chat_create"` // /v1/pathway/chat/create, served on the regional host
+}
+
+// DefaultEndpoints returns the public Bland API hosts.
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		Calls:      DefaultBaseURL,
+		Pathways:   DefaultBaseURL,
+		Folders:    DefaultRegionalBaseURL,
+		Chat:       DefaultBaseURL,
+		ChatCreate: DefaultRegionalBaseURL,
+	}
+}
+
+// ErrMissingToken is returned when a request is attempted without an Authorization token.
+var ErrMissingToken = errors.New("blandclient: authorization token is required")
+
+// APIError is returned when Bland answers with a non-2xx status code, or with
+// a 2xx status code whose body reports a failure.
+type APIError struct {
+	StatusCode int    // HTTP status code returned by Bland
+	Message    string // Short description of the failure
+	Body       []byte // Raw response body
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("blandclient: %s (status %d): %s", e.Message, e.StatusCode, strings.TrimSpace(string(e.Body)))
+}
+
+// Client sends requests to the Bland API on behalf of a single account.
+type Client struct {
+	// Token is sent verbatim as the Authorization header.
+	Token string
+	// Endpoints are the base URLs requests are sent to.
+	Endpoints Endpoints
+	// HTTPClient is used to send requests; http.DefaultClient when nil.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client for the given Authorization token using the default Bland hosts.
+func NewClient(token string) *Client {
+	return &Client{
+		Token:     token,
+		Endpoints: DefaultEndpoints(),
+	}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response into out.
+// It returns the raw response body so callers can build an APIError from it.
+func (c *Client) do(ctx context.Context, method, url string, in, out interface{}) ([]byte, int, error) {
+	if c.Token == "" {
+		return nil, 0, ErrMissingToken
+	}
+
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return nil, 0, fmt.Errorf("blandclient: marshal request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("blandclient: create request: %w", err)
+	}
+	req.Header.Set("Authorization", c.Token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("blandclient: %s %s: %w", method, url, err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, res.StatusCode, fmt.Errorf("blandclient: read response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return raw, res.StatusCode, &APIError{StatusCode: res.StatusCode, Message: errorMessage(res.StatusCode, raw), Body: raw}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return raw, res.StatusCode, fmt.Errorf("blandclient: decode response: %w", err)
+		}
+	}
+	return raw, res.StatusCode, nil
+}
+
+// errorMessage extracts a human-readable message from an error response body,
+// falling back to the HTTP status text.
+func errorMessage(status int, raw []byte) string {
+	var body struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Errors  string `json:"errors"`
+	}
+	if json.Unmarshal(raw, &body) == nil {
+		for _, message := range []string{body.Message, body.Error, body.Errors} {
+			if message != "" {
+				return message
+			}
+		}
+	}
+	return http.StatusText(status)
+}
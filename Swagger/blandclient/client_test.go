@@ -0,0 +1,163 @@
+package blandclient
+
+import (
+	"bland/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client whose endpoints are all served by handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Client{Token: "token", Endpoints: Endpoints{Calls: srv.URL, Pathways: srv.URL, Folders: srv.URL, Chat: srv.URL}}
+}
+
+func TestSendCall(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/calls" {
+			t.Errorf("got %s %s, want POST /v1/calls", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "token" {
+			t.Errorf("Authorization = %q, want token", got)
+		}
+		var call model.SendCall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil || call.PhoneNumber != "+14155552671" {
+			t.Errorf("body = %+v (%v), want the call to +14155552671", call, err)
+		}
+		w.Write([]byte(`{"status":"success","call_id":"call-1"}`))
+	})
+
+	response, err := client.SendCall(context.Background(), model.SendCall{PhoneNumber: "+14155552671", PathwayID: "pathway-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.CallID != "call-1" {
+		t.Errorf("call ID = %q, want call-1", response.CallID)
+	}
+}
+
+func TestMissingToken(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent without a token")
+	})
+	client.Token = ""
+	if _, err := client.GetCall(context.Background(), "call-1"); !errors.Is(err, ErrMissingToken) {
+		t.Errorf("err = %v, want ErrMissingToken", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		send   func(*Client) error
+	}{
+		{"non-2xx status", http.StatusInternalServerError, `{"message":"down"}`, func(c *Client) error {
+			_, err := c.GetCall(context.Background(), "call-1")
+			return err
+		}},
+		{"pathway creation without success", http.StatusOK, `{"status":"error"}`, func(c *Client) error {
+			_, err := c.CreatePathway(context.Background(), model.CreatePathwayRequest{Name: "Support"})
+			return err
+		}},
+		{"folder errors field", http.StatusOK, `{"errors":"Folder not found"}`, func(c *Client) error {
+			_, err := c.CreateFolder(context.Background(), model.CreateFolderRequest{Name: "Sales"})
+			return err
+		}},
+		{"folder deletion errors field", http.StatusOK, `{"errors":"Folder not found"}`, func(c *Client) error {
+			return c.DeleteFolder(context.Background(), "folder-1")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			var apiErr *APIError
+			if err := tt.send(client); !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want an *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || string(apiErr.Body) != tt.body {
+				t.Errorf("got status %d and body %s, want %d and %s", apiErr.StatusCode, apiErr.Body, tt.status, tt.body)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"message":"Invalid node"}`, "Invalid node"},
+		{`{"error":"Rate limited"}`, "Rate limited"},
+		{`{"errors":"Folder not found"}`, "Folder not found"},
+		{`{"status":"error"}`, "Bad Request"},
+		{"not json", "Bad Request"},
+	}
+	for _, tt := range tests {
+		if got := errorMessage(http.StatusBadRequest, []byte(tt.body)); got != tt.want {
+			t.Errorf("errorMessage(%s) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestWaitForCall(t *testing.T) {
+	var polls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch polls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"down"}`))
+		case 2:
+			w.Write([]byte(`{"call_id":"call-1","status":"in-progress"}`))
+		default:
+			w.Write([]byte(`{"call_id":"call-1","status":"completed","completed":true}`))
+		}
+	})
+
+	detail, err := client.WaitForCall(context.Background(), "call-1", WaitOptions{InitialInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !detail.Completed || polls.Load() != 3 {
+		t.Errorf("got %+v after %d polls, want the completed call after 3", detail, polls.Load())
+	}
+}
+
+func TestWaitForCallStops(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   func(error) bool
+	}{
+		{"timeout", http.StatusOK, `{"call_id":"call-1","status":"in-progress"}`, func(err error) bool { return errors.Is(err, ErrWaitTimeout) }},
+		{"unknown call", http.StatusNotFound, `{"message":"Call not found"}`, func(err error) bool {
+			var apiErr *APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			_, err := client.WaitForCall(context.Background(), "call-1", WaitOptions{Timeout: 20 * time.Millisecond, InitialInterval: time.Millisecond})
+			if !tt.want(err) {
+				t.Errorf("err = %v", err)
+			}
+		})
+	}
+}
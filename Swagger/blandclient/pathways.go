@@ -0,0 +1,122 @@
+package blandclient
+
+import (
+	"bland/model"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CreateFolder creates a pathway folder.
+func (c *Client) CreateFolder(ctx context.Context, request model.CreateFolderRequest) (*model.CreateFolderResponse, error) {
+	var response model.CreateFolderResponse
+	raw, status, err := c.do(ctx, http.MethodPost, c.Endpoints.Folders+"/v1/pathway/folders", request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil {
+		return nil, &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return &response, nil
+}
+
+// DeleteFolder deletes a pathway folder.
+func (c *Client) DeleteFolder(ctx context.Context, folderID string) error {
+	var response model.DeleteFolderResponse
+	endpoint := c.Endpoints.Folders + "/v1/pathway/folders/" + url.PathEscape(folderID)
+	raw, status, err := c.do(ctx, http.MethodDelete, endpoint, nil, &response)
+	if err != nil {
+		return err
+	}
+	if response.Errors != nil {
+		return &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return nil
+}
+
+// ListFolders returns every pathway folder of the account. Nested folders name their parent.
+func (c *Client) ListFolders(ctx context.Context) ([]model.CreateFolderData, error) {
+	var response model.ListFoldersResponse
+	raw, status, err := c.do(ctx, http.MethodGet, c.Endpoints.Folders+"/v1/pathway/folders", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil {
+		return nil, &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return response.Data, nil
+}
+
+// ListFolderPathways returns the pathways directly inside a folder.
+func (c *Client) ListFolderPathways(ctx context.Context, folderID string) ([]model.FolderPathway, error) {
+	var response model.FolderPathwaysResponse
+	endpoint := c.Endpoints.Folders + "/v1/pathway/folders/" + url.PathEscape(folderID) + "/pathways"
+	raw, status, err := c.do(ctx, http.MethodGet, endpoint, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil {
+		return nil, &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return response.Data, nil
+}
+
+// CreatePathway creates an empty conversational pathway.
+func (c *Client) CreatePathway(ctx context.Context, request model.CreatePathwayRequest) (*model.CreatePathwayResponse, error) {
+	var response model.CreatePathwayResponse
+	raw, status, err := c.do(ctx, http.MethodPost, c.Endpoints.Pathways+"/v1/convo_pathway/create", request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status != "success" {
+		return nil, &APIError{StatusCode: status, Message: "pathway creation failed", Body: raw}
+	}
+	return &response, nil
+}
+
+// MovePathway moves a pathway into a folder, or to the root when FolderID is empty.
+func (c *Client) MovePathway(ctx context.Context, request model.MovePathwayRequest) (*model.MovePathwayResponse, error) {
+	var response model.MovePathwayResponse
+	raw, status, err := c.do(ctx, http.MethodPost, c.Endpoints.Folders+"/v1/pathway/folders/move", request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil {
+		return nil, &APIError{StatusCode: status, Message: *response.Errors, Body: raw}
+	}
+	return &response, nil
+}
+
+// GetPathway returns a pathway including its nodes and edges.
+func (c *Client) GetPathway(ctx context.Context, pathwayID string) (*model.GetPathwayResponse, error) {
+	var response model.GetPathwayResponse
+	endpoint := c.Endpoints.Pathways + "/v1/convo_pathway/" + url.PathEscape(pathwayID)
+	if _, _, err := c.do(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdatePathway replaces the name, description, nodes and edges of a pathway.
+func (c *Client) UpdatePathway(ctx context.Context, pathwayID string, request model.UpdatePathwayRequest) (*model.UpdatePathwayResponse, error) {
+	var response model.UpdatePathwayResponse
+	endpoint := c.Endpoints.Pathways + "/v1/convo_pathway/" + url.PathEscape(pathwayID)
+	raw, status, err := c.do(ctx, http.MethodPost, endpoint, request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status != "success" {
+		return nil, &APIError{StatusCode: status, Message: response.Message, Body: raw}
+	}
+	return &response, nil
+}
+
+// DeletePathway deletes a pathway.
+func (c *Client) DeletePathway(ctx context.Context, pathwayID string) (*model.DeletePathwayResponse, error) {
+	var response model.DeletePathwayResponse
+	endpoint := c.Endpoints.Pathways + "/v1/convo_pathway/" + url.PathEscape(pathwayID)
+	if _, _, err := c.do(ctx, http.MethodDelete, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,79 @@
+package blandclient
+
+import (
+	"bland/model"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrWaitTimeout is returned by WaitForCall when the call has not completed within the timeout.
+var ErrWaitTimeout = errors.New("blandclient: call did not complete before the wait timeout")
+
+// WaitOptions controls how WaitForCall polls.
+type WaitOptions struct {
+	Timeout         time.Duration // Give up after this long; no limit other than ctx when zero
+	InitialInterval time.Duration // Wait before the second poll; 2s when zero
+	MaxInterval     time.Duration // Upper bound of the wait between polls; 15s when zero
+}
+
+// WaitForCall polls GetCall until the call is completed, waiting longer
+// between each poll, and returns the final details.
+//
+// When the timeout elapses it returns ErrWaitTimeout together with the last
+// details retrieved, if any. Transient failures (5xx answers, unreachable
+// upstream) are retried; other errors, such as an unknown call, are returned
+// at once. Cancelling ctx stops polling and returns ctx.Err().
+func (c *Client) WaitForCall(ctx context.Context, callID string, opts WaitOptions) (*model.CallDetail, error) {
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = 2 * time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 15 * time.Second
+	}
+	var deadline <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var last *model.CallDetail
+	interval := opts.InitialInterval
+	for {
+		detail, err := c.GetCall(ctx, callID)
+		switch {
+		case err == nil:
+			last = detail
+			if detail.Completed {
+				return detail, nil
+			}
+		case ctx.Err() != nil:
+			return last, ctx.Err()
+		case !transient(err):
+			return last, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-deadline:
+			return last, ErrWaitTimeout
+		case <-time.After(interval):
+		}
+		interval = interval * 3 / 2
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// transient reports whether a failed request is worth retrying.
+func transient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, ErrMissingToken)
+}
@@ -0,0 +1,186 @@
+package blandtest
+
+import (
+	"bland/model"
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AddCall stores a call as if it had been dispatched, replacing any call with the same ID.
+func (s *Server) AddCall(detail model.CallDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := detail
+	s.calls[d.CallID] = &d
+}
+
+// Call returns the stored state of a call.
+func (s *Server) Call(callID string) (model.CallDetail, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.calls[callID]
+	if !ok {
+		return model.CallDetail{}, false
+	}
+	return *d, true
+}
+
+// CompleteCall marks a call as completed with the given transcript.
+func (s *Server) CompleteCall(callID string, transcripts ...model.Transcript) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.calls[callID]
+	if !ok {
+		return fmt.Errorf("blandtest: call %s not found", callID)
+	}
+
+	d.AnsweredBy = "human"
+	s.end(d, "USER")
+	d.Transcripts = transcripts
+	d.ConcatenatedTranscript = ""
+	for _, t := range transcripts {
+		d.ConcatenatedTranscript += t.User + ": " + t.Text + " \n"
+	}
+	return nil
+}
+
+// end marks a call as completed, ended by endedBy. The caller must hold s.mu.
+func (s *Server) end(d *model.CallDetail, endedBy string) {
+	now := time.Now().UTC()
+	d.Completed = true
+	d.Status = "completed"
+	d.QueueStatus = "complete"
+	d.CallEndedBy = endedBy
+	d.EndAt = now.Format(time.RFC3339)
+	if d.StartedAt == "" {
+		d.StartedAt = d.CreatedAt
+	}
+	if started, err := time.Parse(time.RFC3339, d.StartedAt); err == nil {
+		d.CallLength = now.Sub(started).Minutes()
+	}
+	if d.Record {
+		recordingURL := s.URL + "/v1/recordings/" + d.CallID
+		d.RecordingURL = &recordingURL
+	}
+}
+
+func (s *Server) sendCall(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.SendCall
+	if !decode(w, body, &request) {
+		return
+	}
+	if request.PhoneNumber == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"status": "error", "message": "Missing phone_number"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC().Format(time.RFC3339)
+	callID := s.newID("call")
+	detail := &model.CallDetail{
+		CallID:      callID,
+		To:          request.PhoneNumber,
+		From:        request.From,
+		RequestData: model.RequestData{PhoneNumber: request.PhoneNumber, Wait: request.WaitForGreeting, Language: request.Language},
+		CreatedAt:   now,
+		StartedAt:   now,
+		QueueStatus: "queued",
+		Status:      "queued",
+		EndpointURL: request.Webhook,
+		MaxDuration: float64(request.MaxDuration),
+		Record:      request.Record,
+		Variables:   stringMap(request.RequestData),
+		Metadata:    stringMap(request.Metadata),
+	}
+	if detail.From == "" {
+		detail.From = "+15555550100"
+	}
+	if request.PathwayID != "" {
+		pathwayID := request.PathwayID
+		detail.PathwayID = &pathwayID
+	}
+	s.calls[callID] = detail
+	writeJSON(w, http.StatusOK, model.CallResponse{Status: "success", CallID: callID})
+}
+
+func (s *Server) getCall(w http.ResponseWriter, r *http.Request, body []byte) {
+	detail, ok := s.Call(r.PathValue("call_id"))
+	if !ok {
+		notFound(w, "Call")
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+func (s *Server) analyzeCall(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.AnalyzeCallRequest
+	if !decode(w, body, &request) {
+		return
+	}
+	if _, ok := s.Call(r.PathValue("call_id")); !ok {
+		notFound(w, "Call")
+		return
+	}
+
+	s.mu.Lock()
+	answers := append([]string(nil), s.AnalysisAnswers...)
+	s.mu.Unlock()
+	if answers == nil {
+		for i := range request.Questions {
+			answers = append(answers, fmt.Sprintf("answer %d", i+1))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, model.AnalyzeCallResponse{
+		Status:      "success",
+		Message:     "Call analyzed successfully",
+		Answers:     answers,
+		CreditsUsed: 0.1 * float64(len(request.Questions)),
+	})
+}
+
+func (s *Server) stopCall(w http.ResponseWriter, r *http.Request, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.calls[r.PathValue("call_id")]
+	if !ok {
+		notFound(w, "Call")
+		return
+	}
+	if d.Completed {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"status": "error", "message": "Call has already ended"})
+		return
+	}
+	s.end(d, "API")
+	writeJSON(w, http.StatusOK, model.StopCallResponse{Status: "success", Message: "Call ended successfully."})
+}
+
+func (s *Server) getRecording(w http.ResponseWriter, r *http.Request, body []byte) {
+	callID := r.PathValue("call_id")
+	detail, ok := s.Call(callID)
+	if !ok || detail.RecordingURL == nil {
+		notFound(w, "Recording")
+		return
+	}
+
+	s.mu.Lock()
+	audio := s.Recording
+	s.mu.Unlock()
+	if audio == nil {
+		audio = []byte("ID3 fake recording of " + callID)
+	}
+
+	w.Header().Set("Content-Type", "audio/mpeg")
+	http.ServeContent(w, r, callID+".mp3", time.Time{}, bytes.NewReader(audio))
+}
+
+func stringMap(values map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package blandtest
+
+import (
+	"bland/model"
+	"net/http"
+)
+
+func (s *Server) createChat(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.CreateChatRequest
+	if !decode(w, body, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pathways[request.PathwayID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, model.CreateChatResponse{Errors: strPtr("Pathway not found")})
+		return
+	}
+
+	chat := &model.SendMessageResponseData{
+		ChatID:        s.newID("chat"),
+		CurrentNodeID: request.StartNodeID,
+		ChatHistory:   []model.ChatHistoryEntry{},
+		PathwayID:     request.PathwayID,
+		Variables:     map[string]string{},
+	}
+	for _, node := range p.Nodes {
+		if node.ID == request.StartNodeID {
+			chat.CurrentNodeName = node.Data.Name
+		}
+	}
+	s.chats[chat.ChatID] = chat
+	writeJSON(w, http.StatusOK, model.CreateChatResponse{Data: model.CreateChatResponseData{ChatID: chat.ChatID, Message: "Chat instance created successfully"}})
+}
+
+func (s *Server) sendChatMessage(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.SendMessageRequest
+	if !decode(w, body, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chat, ok := s.chats[r.PathValue("chat_id")]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, model.SendMessageResponse{Errors: strPtr("Chat not found")})
+		return
+	}
+
+	chat.AssistantResponse = "You said: " + request.Message
+	chat.ChatHistory = append(chat.ChatHistory,
+		model.ChatHistoryEntry{Role: "user", Content: request.Message},
+		model.ChatHistoryEntry{Role: "assistant", Content: chat.AssistantResponse},
+	)
+	response := *chat
+	response.ChatHistory = append([]model.ChatHistoryEntry(nil), chat.ChatHistory...)
+	writeJSON(w, http.StatusOK, model.SendMessageResponse{Data: response})
+}
@@ -0,0 +1,209 @@
+package blandtest
+
+import (
+	"bland/model"
+	"net/http"
+	"sort"
+)
+
+// AddPathway stores a pathway and returns its generated ID.
+func (s *Server) AddPathway(p model.GetPathwayResponse) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newID("pathway")
+	s.pathways[id] = &pathway{GetPathwayResponse: p}
+	return id
+}
+
+// Pathway returns the stored state of a pathway.
+func (s *Server) Pathway(pathwayID string) (model.GetPathwayResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pathways[pathwayID]
+	if !ok {
+		return model.GetPathwayResponse{}, false
+	}
+	return p.GetPathwayResponse, true
+}
+
+// PathwayFolder returns the folder a pathway was moved into, or "" for the root.
+func (s *Server) PathwayFolder(pathwayID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.pathways[pathwayID]; ok && p.FolderID != nil {
+		return *p.FolderID
+	}
+	return ""
+}
+
+// Folders returns the stored folders, ordered by ID.
+func (s *Server) Folders() []model.CreateFolderData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folders := make([]model.CreateFolderData, 0, len(s.folders))
+	for _, folder := range s.folders {
+		folders = append(folders, folder)
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].FolderID < folders[j].FolderID })
+	return folders
+}
+
+func (s *Server) createPathway(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.CreatePathwayRequest
+	if !decode(w, body, &request) {
+		return
+	}
+
+	p := model.GetPathwayResponse{Name: request.Name, Nodes: []model.Node{}, Edges: []model.Edge{}}
+	if request.Description != "" {
+		description := request.Description
+		p.Description = &description
+	}
+	writeJSON(w, http.StatusOK, model.CreatePathwayResponse{Status: "success", PathwayID: s.AddPathway(p)})
+}
+
+func (s *Server) getPathway(w http.ResponseWriter, r *http.Request, body []byte) {
+	p, ok := s.Pathway(r.PathValue("pathway_id"))
+	if !ok {
+		notFound(w, "Pathway")
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) updatePathway(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.UpdatePathwayRequest
+	if !decode(w, body, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pathways[r.PathValue("pathway_id")]
+	if !ok {
+		writeJSON(w, http.StatusOK, model.UpdatePathwayResponse{Status: "error", Message: "Pathway not found"})
+		return
+	}
+	if request.Name != "" {
+		p.Name = request.Name
+	}
+	if request.Description != "" {
+		description := request.Description
+		p.Description = &description
+	}
+	if request.Nodes != nil {
+		p.Nodes = request.Nodes
+	}
+	if request.Edges != nil {
+		p.Edges = request.Edges
+	}
+
+	data := model.PathwayData{Name: p.Name, Nodes: p.Nodes, Edges: p.Edges}
+	if p.Description != nil {
+		data.Description = *p.Description
+	}
+	writeJSON(w, http.StatusOK, model.UpdatePathwayResponse{Status: "success", Message: "Pathway updated successfully", PathwayData: data})
+}
+
+func (s *Server) deletePathway(w http.ResponseWriter, r *http.Request, body []byte) {
+	pathwayID := r.PathValue("pathway_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pathways[pathwayID]; !ok {
+		notFound(w, "Pathway")
+		return
+	}
+	delete(s.pathways, pathwayID)
+	writeJSON(w, http.StatusOK, model.DeletePathwayResponse{Status: "success", Message: "Pathway deleted successfully", PathwayID: pathwayID})
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.CreateFolderRequest
+	if !decode(w, body, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folder := model.CreateFolderData{FolderID: s.newID("folder"), Name: request.Name}
+	if request.ParentFolderID != "" {
+		if _, ok := s.folders[request.ParentFolderID]; !ok {
+			writeJSON(w, http.StatusNotFound, model.CreateFolderResponse{Errors: strPtr("Parent folder not found")})
+			return
+		}
+		parent := request.ParentFolderID
+		folder.ParentFolderID = &parent
+	}
+	s.folders[folder.FolderID] = folder
+	writeJSON(w, http.StatusOK, model.CreateFolderResponse{Data: folder})
+}
+
+func (s *Server) listFolders(w http.ResponseWriter, r *http.Request, body []byte) {
+	writeJSON(w, http.StatusOK, model.ListFoldersResponse{Data: s.Folders()})
+}
+
+func (s *Server) deleteFolder(w http.ResponseWriter, r *http.Request, body []byte) {
+	folderID := r.PathValue("folder_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folder, ok := s.folders[folderID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, model.DeleteFolderResponse{Errors: strPtr("Folder not found")})
+		return
+	}
+	delete(s.folders, folderID)
+	writeJSON(w, http.StatusOK, model.DeleteFolderResponse{Data: folder})
+}
+
+func (s *Server) folderPathways(w http.ResponseWriter, r *http.Request, body []byte) {
+	folderID := r.PathValue("folder_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.folders[folderID]; !ok {
+		writeJSON(w, http.StatusNotFound, model.FolderPathwaysResponse{Errors: strPtr("Folder not found")})
+		return
+	}
+	pathways := []model.FolderPathway{}
+	for id, p := range s.pathways {
+		if p.FolderID != nil && *p.FolderID == folderID {
+			pathways = append(pathways, model.FolderPathway{PathwayID: id, Name: p.Name})
+		}
+	}
+	sort.Slice(pathways, func(i, j int) bool { return pathways[i].PathwayID < pathways[j].PathwayID })
+	writeJSON(w, http.StatusOK, model.FolderPathwaysResponse{Data: pathways})
+}
+
+func (s *Server) movePathway(w http.ResponseWriter, r *http.Request, body []byte) {
+	var request model.MovePathwayRequest
+	if !decode(w, body, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pathways[request.PathwayID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, model.MovePathwayResponse{Errors: strPtr("Pathway not found")})
+		return
+	}
+
+	var newFolderID *string
+	if request.FolderID != "" {
+		if _, ok := s.folders[request.FolderID]; !ok {
+			writeJSON(w, http.StatusNotFound, model.MovePathwayResponse{Errors: strPtr("Folder not found")})
+			return
+		}
+		newFolderID = strPtr(request.FolderID)
+	}
+
+	data := model.MovePathwayData{PathwayID: request.PathwayID, OldFolderID: p.FolderID, NewFolderID: newFolderID}
+	p.FolderID = newFolderID
+	writeJSON(w, http.StatusOK, model.MovePathwayResponse{Data: data})
+}
+
+func strPtr(s string) *string {
+	return &s
+}
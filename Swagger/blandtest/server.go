@@ -0,0 +1,232 @@
+// Package blandtest provides an in-process fake of the Bland API for tests.
+//
+// The fake keeps calls, pathways, folders and chats in memory and serves the
+// endpoints used by the controller, so the proxy can be exercised end to end
+// without network access:
+//
+//	srv := blandtest.NewServer()
+//	defer srv.Close()
+//	ctl, err := controller.New(srv.Config())
+//
+// Failures are scripted per route with Fail, using the same method and path
+// patterns the fake registers, e.g. "POST /v1/calls".
+package blandtest
+
+import (
+	"bland/blandclient"
+	"bland/config"
+	"bland/model"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Route patterns served by the fake, usable as keys for Fail.
+const (
+	RouteSendCall        = "POST /v1/calls"
+	RouteGetCall         = "GET /v1/calls/{call_id}"
+	RouteAnalyzeCall     = "POST /v1/calls/{call_id}/analyze"
+	RouteStopCall        = "POST /v1/calls/{call_id}/stop"
+	RouteGetRecording    = "GET /v1/recordings/{call_id}"
+	RouteCreatePathway   = "POST /v1/convo_pathway/create"
+	RouteGetPathway      = "GET /v1/convo_pathway/{pathway_id}"
+	RouteUpdatePathway   = "POST /v1/convo_pathway/{pathway_id}"
+	RouteDeletePathway   = "DELETE /v1/convo_pathway/{pathway_id}"
+	RouteCreateFolder    = "POST /v1/pathway/folders"
+	RouteListFolders     = "GET /v1/pathway/folders"
+	RouteDeleteFolder    = "DELETE /v1/pathway/folders/{folder_id}"
+	RouteFolderPathways  = "GET /v1/pathway/folders/{folder_id}/pathways"
+	RouteMovePathway     = "POST /v1/pathway/folders/move"
+	RouteCreateChat      = "POST /v1/pathway/chat/create"
+	RouteSendChatMessage = "POST /v1/pathway/chat/{chat_id}"
+)
+
+// Failure describes a scripted failure for a route.
+type Failure struct {
+	Status int           // HTTP status code to return
+	Body   string        // Raw response body
+	Times  int           // Number of requests to fail; 0 fails every request until cleared
+	Delay  time.Duration // Optional delay before responding
+}
+
+// Request is a record of a request received by the fake.
+type Request struct {
+	Route         string
+	Path          string
+	Authorization string
+	Body          []byte
+}
+
+// Server is a fake Bland API backed by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// Token, when set, is the only Authorization header value accepted.
+	Token string
+	// AnalysisAnswers, when set, are returned by the analyze endpoint instead of
+	// one placeholder answer per question.
+	AnalysisAnswers []string
+	// Recording, when set, is the audio served for every recorded call instead of a placeholder.
+	Recording []byte
+
+	mu       sync.Mutex
+	nextID   int
+	calls    map[string]*model.CallDetail
+	pathways map[string]*pathway
+	folders  map[string]model.CreateFolderData
+	chats    map[string]*model.SendMessageResponseData
+	failures map[string]*Failure
+	requests []Request
+}
+
+type pathway struct {
+	model.GetPathwayResponse
+	FolderID *string
+}
+
+// NewServer starts a fake Bland API. The caller must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		calls:    make(map[string]*model.CallDetail),
+		pathways: make(map[string]*pathway),
+		folders:  make(map[string]model.CreateFolderData),
+		chats:    make(map[string]*model.SendMessageResponseData),
+		failures: make(map[string]*Failure),
+	}
+
+	mux := http.NewServeMux()
+	s.handle(mux, RouteSendCall, s.sendCall)
+	s.handle(mux, RouteGetCall, s.getCall)
+	s.handle(mux, RouteAnalyzeCall, s.analyzeCall)
+	s.handle(mux, RouteStopCall, s.stopCall)
+	s.handle(mux, RouteGetRecording, s.getRecording)
+	s.handle(mux, RouteCreatePathway, s.createPathway)
+	s.handle(mux, RouteGetPathway, s.getPathway)
+	s.handle(mux, RouteUpdatePathway, s.updatePathway)
+	s.handle(mux, RouteDeletePathway, s.deletePathway)
+	s.handle(mux, RouteCreateFolder, s.createFolder)
+	s.handle(mux, RouteListFolders, s.listFolders)
+	s.handle(mux, RouteDeleteFolder, s.deleteFolder)
+	s.handle(mux, RouteFolderPathways, s.folderPathways)
+	s.handle(mux, RouteMovePathway, s.movePathway)
+	s.handle(mux, RouteCreateChat, s.createChat)
+	s.handle(mux, RouteSendChatMessage, s.sendChatMessage)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Endpoints returns client endpoints that point every API family at the fake.
+func (s *Server) Endpoints() blandclient.Endpoints {
+	return blandclient.Endpoints{Calls: s.URL, Pathways: s.URL, Folders: s.URL, Chat: s.URL, ChatCreate: s.URL}
+}
+
+// Config returns a default configuration whose upstream is the fake.
+func (s *Server) Config() config.Config {
+	cfg := config.Default()
+	cfg.Upstream = s.Endpoints()
+	cfg.DataDir = ""
+	cfg.SecretKeyFile = ""
+	return cfg
+}
+
+// Fail scripts a failure for route, replacing any previous one.
+func (s *Server) Fail(route string, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := failure
+	s.failures[route] = &f
+}
+
+// ClearFailures removes all scripted failures.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = make(map[string]*Failure)
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// handle registers fn for route, wrapping it with request recording, the
+// Authorization check and scripted failures.
+func (s *Server) handle(mux *http.ServeMux, route string, fn func(w http.ResponseWriter, r *http.Request, body []byte)) {
+	mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Route: route, Path: r.URL.Path, Authorization: r.Header.Get("Authorization"), Body: body})
+		failure := s.takeFailure(route)
+		token := s.Token
+		s.mu.Unlock()
+
+		if failure != nil {
+			if failure.Delay > 0 {
+				select {
+				case <-time.After(failure.Delay):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			w.WriteHeader(failure.Status)
+			fmt.Fprint(w, failure.Body)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if auth == "" || (token != "" && auth != token) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"status": "error", "message": "Invalid API key"})
+			return
+		}
+
+		fn(w, r, body)
+	})
+}
+
+// takeFailure returns the scripted failure for route, if any, consuming one use.
+// The caller must hold s.mu.
+func (s *Server) takeFailure(route string) *Failure {
+	failure, ok := s.failures[route]
+	if !ok {
+		return nil
+	}
+	if failure.Times > 0 {
+		failure.Times--
+		if failure.Times == 0 {
+			delete(s.failures, route)
+		}
+	}
+	return failure
+}
+
+// newID returns a unique identifier with the given prefix. The caller must hold s.mu.
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%06d", prefix, s.nextID)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func decode(w http.ResponseWriter, body []byte, v interface{}) bool {
+	if err := json.Unmarshal(body, v); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"status": "error", "message": "Invalid JSON body: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func notFound(w http.ResponseWriter, what string) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"status": "error", "message": what + " not found"})
+}
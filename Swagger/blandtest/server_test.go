@@ -0,0 +1,111 @@
+package blandtest
+
+import (
+	"bland/blandclient"
+	"bland/model"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPathwayLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := &blandclient.Client{Token: "token", Endpoints: srv.Endpoints()}
+	ctx := context.Background()
+
+	created, err := client.CreatePathway(ctx, model.CreatePathwayRequest{Name: "Support"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	folder, err := client.CreateFolder(ctx, model.CreateFolderRequest{Name: "Sales"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.MovePathway(ctx, model.MovePathwayRequest{PathwayID: created.PathwayID, FolderID: folder.Data.FolderID}); err != nil {
+		t.Fatal(err)
+	}
+
+	prompt := "Hi"
+	nodes := []model.Node{{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}}}
+	if _, err := client.UpdatePathway(ctx, created.PathwayID, model.UpdatePathwayRequest{Name: "Support v2", Nodes: nodes}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetPathway(ctx, created.PathwayID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Support v2" || len(got.Nodes) != 1 || *got.Nodes[0].Data.Prompt != "Hi" {
+		t.Errorf("pathway = %+v, want the update", got)
+	}
+
+	if _, err := client.DeletePathway(ctx, created.PathwayID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Pathway(created.PathwayID); ok {
+		t.Error("pathway still stored after delete")
+	}
+	if err := client.DeleteFolder(ctx, folder.Data.FolderID); err != nil {
+		t.Fatal(err)
+	}
+	if folders := srv.Folders(); len(folders) != 0 {
+		t.Errorf("folders = %+v after delete, want none", folders)
+	}
+}
+
+func TestFail(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := &blandclient.Client{Token: "token", Endpoints: srv.Endpoints()}
+	call, err := client.SendCall(context.Background(), model.SendCall{PhoneNumber: "+14155552671", PathwayID: "pathway-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Fail(RouteGetCall, Failure{Status: http.StatusServiceUnavailable, Body: `{"message":"down"}`, Times: 2})
+	for i := 0; i < 2; i++ {
+		var apiErr *blandclient.APIError
+		if _, err := client.GetCall(context.Background(), call.CallID); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("request %d: err = %v, want the scripted 503", i+1, err)
+		}
+	}
+	if _, err := client.GetCall(context.Background(), call.CallID); err != nil {
+		t.Errorf("request after the scripted failures: %v", err)
+	}
+
+	var routes []string
+	for _, req := range srv.Requests() {
+		routes = append(routes, req.Route)
+	}
+	if len(routes) != 4 || routes[0] != RouteSendCall || routes[3] != RouteGetCall {
+		t.Errorf("recorded routes = %v, want a send and three gets", routes)
+	}
+}
+
+func TestToken(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Token = "right"
+
+	tests := []struct {
+		token  string
+		status int
+	}{
+		{"right", 0},
+		{"wrong", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			client := &blandclient.Client{Token: tt.token, Endpoints: srv.Endpoints()}
+			_, err := client.SendCall(context.Background(), model.SendCall{PhoneNumber: "+14155552671", PathwayID: "pathway-1"})
+			var apiErr *blandclient.APIError
+			switch {
+			case tt.status == 0 && err != nil:
+				t.Errorf("err = %v, want none", err)
+			case tt.status != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.status):
+				t.Errorf("err = %v, want status %d", err, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Package config loads the service configuration from an optional YAML or
+// JSON file and from environment variables.
+//
+// Values are applied in order, each overriding the previous one:
+// built-in defaults, the file named by BLAND_CONFIG_FILE, BLAND_BASE_URL
+// (all API families) and finally the individual BLAND_* variables.
+package config
+
+import (
+	"bland/blandclient"
+	"bland/model"
+	"bland/phone"
+	"bland/scheduler"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by Load.
+const (
+	EnvConfigFile      = "BLAND_CONFIG_FILE"
+	EnvBaseURL         = "BLAND_BASE_URL"
+	EnvCallsBaseURL    = "BLAND_CALLS_BASE_URL"
+	EnvPathwaysBaseURL = "BLAND_PATHWAYS_BASE_URL"
+	EnvFoldersBaseURL  = "BLAND_FOLDERS_BASE_URL"
+	EnvChatBaseURL     = "BLAND_CHAT_BASE_URL"
+	EnvChatCreateURL   = "BLAND_CHAT_CREATE_BASE_URL"
+	EnvPhoneRegion     = "BLAND_PHONE_DEFAULT_REGION"
+	EnvBatchWorkers    = "BLAND_BATCH_CONCURRENCY"
+	EnvAnalysisWorkers = "BLAND_ANALYSIS_CONCURRENCY"
+	EnvAnalysisRate    = "BLAND_ANALYSIS_REQUESTS_PER_MINUTE"
+	EnvDataDir         = "BLAND_DATA_DIR"
+	EnvSecretKey       = "BLAND_SECRET_KEY"
+	EnvSecretKeyFile   = "BLAND_SECRET_KEY_FILE"
+	EnvTimezone        = "BLAND_SCHEDULER_DEFAULT_TIMEZONE"
+	EnvWebhookSecret   = "BLAND_WEBHOOK_SECRET"
+	EnvRecordingsDir   = "BLAND_RECORDINGS_DIR"
+	EnvRecordingsToken = "BLAND_RECORDINGS_TOKEN"
+	EnvRecordingsDays  = "BLAND_RECORDINGS_RETENTION_DAYS"
+	EnvRetentionDays   = "BLAND_RETENTION_DAYS"
+	EnvSnapshotLimit   = "BLAND_SNAPSHOTS_PER_PATHWAY"
+)
+
+// Config is the complete service configuration.
+type Config struct {
+	// Upstream holds the Bland base URL for each API family.
+	Upstream blandclient.Endpoints `json:"upstream" yaml:"upstream"`
+	// Phone holds phone number validation settings.
+	Phone Phone `json:"phone" yaml:"phone"`
+	// Batch holds bulk call dispatch settings.
+	Batch Batch `json:"batch" yaml:"batch"`
+	// Analysis holds bulk analysis settings.
+	Analysis Analysis `json:"analysis" yaml:"analysis"`
+	// Scheduler holds scheduled call settings.
+	Scheduler Scheduler `json:"scheduler" yaml:"scheduler"`
+	// Webhooks holds the settings of callbacks received from Bland and of events delivered to subscribers.
+	Webhooks Webhooks `json:"webhooks" yaml:"webhooks"`
+	// Recordings holds the settings of the local recording archive.
+	Recordings Recordings `json:"recordings" yaml:"recordings"`
+	// Retention holds how long the history kept in DataDir is retained.
+	Retention Retention `json:"retention" yaml:"retention"`
+	// DataDir is the directory where local state is persisted, data by default.
+	// Setting it to empty in the config file keeps state in memory only.
+	DataDir string `json:"data_dir" yaml:"data_dir"`
+	// SecretKey encrypts the Authorization tokens kept in DataDir, as 64 hex digits.
+	// Empty uses the key in SecretKeyFile.
+	SecretKey string `json:"secret_key" yaml:"secret_key"`
+	// SecretKeyFile holds the key used when SecretKey is empty, generated the first time.
+	// It must be outside DataDir, so a copy of the data does not include the key.
+	SecretKeyFile string `json:"secret_key_file" yaml:"secret_key_file"`
+}
+
+// Phone holds phone number validation settings.
+type Phone struct {
+	// DefaultRegion is the ISO 3166-1 alpha-2 country used for numbers without a country code.
+	DefaultRegion string `json:"default_region" yaml:"default_region"`
+}
+
+// Batch holds bulk call dispatch settings.
+type Batch struct {
+	// Concurrency is the number of calls of a batch sent to Bland at the same time.
+	Concurrency int `json:"concurrency" yaml:"concurrency"`
+	// MaxContacts is the largest number of contacts accepted in one batch.
+	MaxContacts int `json:"max_contacts" yaml:"max_contacts"`
+}
+
+// Analysis holds bulk analysis settings.
+type Analysis struct {
+	// Concurrency is the number of analyses of a job sent to Bland at the same time.
+	Concurrency int `json:"concurrency" yaml:"concurrency"`
+	// RequestsPerMinute is the most analyses a job sends to Bland per minute.
+	RequestsPerMinute int `json:"requests_per_minute" yaml:"requests_per_minute"`
+	// MaxCalls is the largest number of calls accepted in one job.
+	MaxCalls int `json:"max_calls" yaml:"max_calls"`
+}
+
+// Scheduler holds scheduled call settings.
+type Scheduler struct {
+	// PollIntervalSeconds is how often the scheduler looks for due calls.
+	PollIntervalSeconds int `json:"poll_interval_seconds" yaml:"poll_interval_seconds"`
+	// DefaultTimezone is the IANA time zone used for contacts without one.
+	DefaultTimezone string `json:"default_timezone" yaml:"default_timezone"`
+	// Window is the calling window used when a scheduled call does not set its own.
+	Window model.CallingWindow `json:"window" yaml:"window"`
+}
+
+// PollInterval returns PollIntervalSeconds as a duration.
+func (s Scheduler) PollInterval() time.Duration {
+	return time.Duration(s.PollIntervalSeconds) * time.Second
+}
+
+// Webhooks holds the settings of callbacks received from Bland and of events delivered to subscribers.
+type Webhooks struct {
+	// BlandSecret is the secret Bland signs its callbacks with. Callbacks are refused while it is empty.
+	BlandSecret string `json:"bland_secret" yaml:"bland_secret"`
+	// MaxAttempts is the number of attempts before a delivery becomes a dead letter.
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+	// RetryBaseSeconds is the wait after the first failed attempt, doubled after each further one.
+	RetryBaseSeconds int `json:"retry_base_seconds" yaml:"retry_base_seconds"`
+	// TimeoutSeconds is the timeout of a single delivery attempt.
+	TimeoutSeconds int `json:"timeout_seconds" yaml:"timeout_seconds"`
+	// AllowPrivateTargets lets subscriptions use http URLs and private, loopback and link-local addresses.
+	// By default only https URLs of public addresses are accepted.
+	AllowPrivateTargets bool `json:"allow_private_targets" yaml:"allow_private_targets"`
+}
+
+// Recordings holds the settings of the local recording archive.
+type Recordings struct {
+	// ArchiveDir is where recordings of completed calls are downloaded. Empty disables archival.
+	ArchiveDir string `json:"archive_dir" yaml:"archive_dir"`
+	// ArchiveToken is the Bland API key used to download recordings served by the Bland API.
+	// Pre-signed recording URLs are downloaded without it.
+	ArchiveToken string `json:"archive_token" yaml:"archive_token"`
+	// RetentionDays is how long archived recordings are kept. 0 keeps them forever.
+	RetentionDays int `json:"retention_days" yaml:"retention_days"`
+	// MaxSizeMB caps the size of the archive by removing the oldest recordings. 0 means no cap.
+	MaxSizeMB int `json:"max_size_mb" yaml:"max_size_mb"`
+}
+
+// Retention holds how long the history kept in DataDir is retained.
+type Retention struct {
+	// Days is how long calls of the call history and webhook dead letters are kept
+	// after their last change, and batches and analysis jobs after they finish.
+	// 0 keeps them forever.
+	Days int `json:"days" yaml:"days"`
+	// SnapshotsPerPathway is the number of snapshots kept for each pathway; older
+	// ones are removed when a new one is taken. 0 keeps every snapshot.
+	SnapshotsPerPathway int `json:"snapshots_per_pathway" yaml:"snapshots_per_pathway"`
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		Upstream: blandclient.DefaultEndpoints(),
+		Phone:    Phone{DefaultRegion: "US"},
+		Batch:    Batch{Concurrency: 5, MaxContacts: 1000},
+		Analysis: Analysis{Concurrency: 3, RequestsPerMinute: 60, MaxCalls: 1000},
+		Scheduler: Scheduler{
+			PollIntervalSeconds: 30,
+			DefaultTimezone:     "America/New_York",
+			Window: model.CallingWindow{
+				Start: "09:00",
+				End:   "20:00",
+				Days:  []string{"mon", "tue", "wed", "thu", "fri", "sat"},
+			},
+		},
+		Webhooks:      Webhooks{MaxAttempts: 8, RetryBaseSeconds: 10, TimeoutSeconds: 10},
+		Recordings:    Recordings{RetentionDays: 90},
+		Retention:     Retention{Days: 90, SnapshotsPerPathway: 50},
+		DataDir:       "data",
+		SecretKeyFile: "secret.key",
+	}
+}
+
+// Load builds the configuration from defaults, the optional config file and the environment.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return Config{}, err
+	}
+	cfg.normalize()
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that every upstream base URL is set and the settings are supported.
+func (cfg Config) Validate() error {
+	if !phone.SupportedRegion(cfg.Phone.DefaultRegion) {
+		return fmt.Errorf("config: phone.default_region %q is not supported", cfg.Phone.DefaultRegion)
+	}
+	if cfg.Batch.Concurrency < 1 || cfg.Batch.MaxContacts < 1 {
+		return fmt.Errorf("config: batch.concurrency and batch.max_contacts must be positive")
+	}
+	if cfg.Analysis.Concurrency < 1 || cfg.Analysis.RequestsPerMinute < 1 || cfg.Analysis.MaxCalls < 1 {
+		return fmt.Errorf("config: analysis.concurrency, analysis.requests_per_minute and analysis.max_calls must be positive")
+	}
+	if cfg.Scheduler.PollIntervalSeconds < 1 {
+		return fmt.Errorf("config: scheduler.poll_interval_seconds must be positive")
+	}
+	if cfg.Webhooks.MaxAttempts < 1 || cfg.Webhooks.RetryBaseSeconds < 1 || cfg.Webhooks.TimeoutSeconds < 1 {
+		return fmt.Errorf("config: webhooks.max_attempts, webhooks.retry_base_seconds and webhooks.timeout_seconds must be positive")
+	}
+	if cfg.Recordings.RetentionDays < 0 || cfg.Recordings.MaxSizeMB < 0 {
+		return fmt.Errorf("config: recordings.retention_days and recordings.max_size_mb must not be negative")
+	}
+	if cfg.Retention.Days < 0 || cfg.Retention.SnapshotsPerPathway < 0 {
+		return fmt.Errorf("config: retention.days and retention.snapshots_per_pathway must not be negative")
+	}
+	if _, err := time.LoadLocation(cfg.Scheduler.DefaultTimezone); err != nil {
+		return fmt.Errorf("config: scheduler.default_timezone %q is not a known time zone", cfg.Scheduler.DefaultTimezone)
+	}
+	if err := scheduler.ValidateWindow(cfg.Scheduler.Window); err != nil {
+		return fmt.Errorf("config: scheduler.%w", err)
+	}
+	if cfg.DataDir != "" && cfg.SecretKey == "" {
+		if cfg.SecretKeyFile == "" {
+			return fmt.Errorf("config: secret_key or secret_key_file is required when data_dir is set")
+		}
+		if within(cfg.DataDir, cfg.SecretKeyFile) {
+			return fmt.Errorf("config: secret_key_file %q must be outside data_dir %q", cfg.SecretKeyFile, cfg.DataDir)
+		}
+	}
+	for name, value := range map[string]string{
+		"calls":       cfg.Upstream.Calls,
+		"pathways":    cfg.Upstream.Pathways,
+		"folders":     cfg.Upstream.Folders,
+		"chat":        cfg.Upstream.Chat,
+		"chat_create": cfg.Upstream.ChatCreate,
+	} {
+		if value == "" {
+			return fmt.Errorf("config: upstream.%s base URL is empty", name)
+		}
+	}
+	return nil
+}
+
+// within reports whether path is inside dir.
+func within(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// loadFile decodes a YAML or JSON file, chosen by extension, on top of cfg.
+// Fields missing from the file keep their current values.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("config: unsupported file extension for %s (want .json, .yaml or .yml)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides cfg with any environment variables that are set.
+func applyEnv(cfg *Config) error {
+	if base := os.Getenv(EnvBaseURL); base != "" {
+		cfg.Upstream = blandclient.Endpoints{Calls: base, Pathways: base, Folders: base, Chat: base, ChatCreate: base}
+	}
+	setFromEnv(&cfg.Upstream.Calls, EnvCallsBaseURL)
+	setFromEnv(&cfg.Upstream.Pathways, EnvPathwaysBaseURL)
+	setFromEnv(&cfg.Upstream.Folders, EnvFoldersBaseURL)
+	setFromEnv(&cfg.Upstream.Chat, EnvChatBaseURL)
+	setFromEnv(&cfg.Upstream.ChatCreate, EnvChatCreateURL)
+	setFromEnv(&cfg.Phone.DefaultRegion, EnvPhoneRegion)
+	setFromEnv(&cfg.Scheduler.DefaultTimezone, EnvTimezone)
+	setFromEnv(&cfg.DataDir, EnvDataDir)
+	setFromEnv(&cfg.SecretKey, EnvSecretKey)
+	setFromEnv(&cfg.SecretKeyFile, EnvSecretKeyFile)
+	setFromEnv(&cfg.Webhooks.BlandSecret, EnvWebhookSecret)
+	setFromEnv(&cfg.Recordings.ArchiveDir, EnvRecordingsDir)
+	setFromEnv(&cfg.Recordings.ArchiveToken, EnvRecordingsToken)
+	if err := setIntFromEnv(&cfg.Recordings.RetentionDays, EnvRecordingsDays); err != nil {
+		return err
+	}
+	if err := setIntFromEnv(&cfg.Retention.Days, EnvRetentionDays); err != nil {
+		return err
+	}
+	if err := setIntFromEnv(&cfg.Retention.SnapshotsPerPathway, EnvSnapshotLimit); err != nil {
+		return err
+	}
+	if err := setIntFromEnv(&cfg.Analysis.Concurrency, EnvAnalysisWorkers); err != nil {
+		return err
+	}
+	if err := setIntFromEnv(&cfg.Analysis.RequestsPerMinute, EnvAnalysisRate); err != nil {
+		return err
+	}
+	return setIntFromEnv(&cfg.Batch.Concurrency, EnvBatchWorkers)
+}
+
+func setFromEnv(field *string, name string) {
+	if value := os.Getenv(name); value != "" {
+		*field = value
+	}
+}
+
+func setIntFromEnv(field *int, name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("config: %s must be an integer: %w", name, err)
+	}
+	*field = n
+	return nil
+}
+
+// normalize strips trailing slashes so paths can be appended to base URLs.
+func (cfg *Config) normalize() {
+	for _, field := range []*string{&cfg.Upstream.Calls, &cfg.Upstream.Pathways, &cfg.Upstream.Folders, &cfg.Upstream.Chat, &cfg.Upstream.ChatCreate} {
+		*field = strings.TrimRight(*field, "/")
+	}
+	cfg.Phone.DefaultRegion = strings.ToUpper(cfg.Phone.DefaultRegion)
+}
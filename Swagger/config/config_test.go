@@ -0,0 +1,118 @@
+package config
+
+import (
+	"bland/blandclient"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string // Name of the config file, written with content
+		content string
+		env     map[string]string
+		want    blandclient.Endpoints // Only the non-empty fields are compared
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: blandclient.Endpoints{Calls: blandclient.DefaultBaseURL, Pathways: blandclient.DefaultBaseURL, Folders: blandclient.DefaultRegionalBaseURL},
+		},
+		{
+			name:    "YAML file",
+			file:    "config.yaml",
+			content: "upstream:\n  calls: http://staging.example/\n",
+			want:    blandclient.Endpoints{Calls: "http://staging.example", Pathways: blandclient.DefaultBaseURL},
+		},
+		{
+			name:    "JSON file",
+			file:    "config.json",
+			content: `{"upstream":{"folders":"http://folders.example"}}`,
+			want:    blandclient.Endpoints{Calls: blandclient.DefaultBaseURL, Folders: "http://folders.example"},
+		},
+		{
+			name: "base URL with a per-family override",
+			env:  map[string]string{EnvBaseURL: "http://local:8081", EnvChatBaseURL: "http://chat.example/"},
+			want: blandclient.Endpoints{Calls: "http://local:8081", Pathways: "http://local:8081", Folders: "http://local:8081", Chat: "http://chat.example"},
+		},
+		{
+			name:    "environment overrides the file",
+			file:    "config.yml",
+			content: "upstream:\n  calls: http://file.example\n",
+			env:     map[string]string{EnvCallsBaseURL: "http://env.example"},
+			want:    blandclient.Endpoints{Calls: "http://env.example"},
+		},
+		{
+			name:    "unsupported extension",
+			file:    "config.toml",
+			content: "calls = 'x'",
+			wantErr: true,
+		},
+		{
+			name:    "empty base URL",
+			file:    "config.yaml",
+			content: "upstream:\n  pathways: \"\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvConfigFile, EnvBaseURL, EnvCallsBaseURL, EnvPathwaysBaseURL, EnvFoldersBaseURL, EnvChatBaseURL} {
+				t.Setenv(name, tt.env[name])
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), tt.file)
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv(EnvConfigFile, path)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Load() = %+v, want an error", cfg.Upstream)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range []struct{ name, got, want string }{
+				{"calls", cfg.Upstream.Calls, tt.want.Calls},
+				{"pathways", cfg.Upstream.Pathways, tt.want.Pathways},
+				{"folders", cfg.Upstream.Folders, tt.want.Folders},
+				{"chat", cfg.Upstream.Chat, tt.want.Chat},
+			} {
+				if f.want != "" && f.got != f.want {
+					t.Errorf("%s = %q, want %q", f.name, f.got, f.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSecretKeyFile(t *testing.T) {
+	tests := []struct {
+		dataDir, key, keyFile string
+		wantErr               bool
+	}{
+		{dataDir: "data", keyFile: "secret.key"},
+		{dataDir: "data", keyFile: "data/secret.key", wantErr: true},
+		{dataDir: "data", keyFile: "./data/keys/secret.key", wantErr: true},
+		{dataDir: "data", keyFile: "data-keys/secret.key"},
+		{dataDir: "data", key: "set", keyFile: "data/secret.key"},
+		{dataDir: "data", wantErr: true},
+		{dataDir: ""},
+	}
+	for _, tt := range tests {
+		cfg := Default()
+		cfg.DataDir, cfg.SecretKey, cfg.SecretKeyFile = tt.dataDir, tt.key, tt.keyFile
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with data_dir %q and secret_key_file %q = %v, want an error: %v", tt.dataDir, tt.keyFile, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package controller
+
+import (
+	"bland/analysis"
+	"bland/events"
+	"bland/model"
+	"bland/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// analysisSchema is the stored form of an AnalysisSchema, with the owner of the
+// token that created it. Schemas are only used by and returned to that token.
+type analysisSchema struct {
+	model.AnalysisSchema
+	Owner string `json:"owner"`
+}
+
+// CreateAnalysisSchema godoc
+// @Summary      Create an analysis schema
+// @Description  Stores a named, reusable set of analysis questions. Each question has a name, which keys its answer
+// @Description  in the result, and an expected type: boolean, number, enum (with options) or string. The schema belongs
+// @Description  to the Authorization token and can only be read, changed and used with it.
+// @Tags         AnalyzeCall
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.AnalysisSchemaRequest  true  "Schema"
+// @Success      201  {object}  model.AnalysisSchema  "Schema created"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /analysis/schemas [post]
+func (ctl *Controller) CreateAnalysisSchema(c *gin.Context) {
+	// Step 1: Bind and validate the request body
+	request, ok := bindAnalysisSchema(c)
+	if !ok {
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Store the schema
+	now := time.Now().UTC().Format(time.RFC3339)
+	schema := analysisSchema{Owner: ownerOf(bearerToken), AnalysisSchema: model.AnalysisSchema{
+		ID:          newID("schema"),
+		Name:        request.Name,
+		Description: request.Description,
+		Goal:        request.Goal,
+		Questions:   request.Questions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}}
+	if err := ctl.analysisSchemas.Put(schema.ID, schema); err != nil {
+		log.Printf("Error saving analysis schema: %v", err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the analysis schema")
+		return
+	}
+	log.Printf("Analysis schema %s created", schema.ID)
+
+	c.JSON(http.StatusCreated, schema.AnalysisSchema)
+}
+
+// ListAnalysisSchemas godoc
+// @Summary      List analysis schemas
+// @Description  Returns the analysis schemas of the Authorization token, sorted by name
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Success      200  {array}  model.AnalysisSchema  "Schemas"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /analysis/schemas [get]
+func (ctl *Controller) ListAnalysisSchemas(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the schemas of the token, sorted by name
+	owner := ownerOf(bearerToken)
+	schemas := []model.AnalysisSchema{}
+	for _, schema := range ctl.analysisSchemas.List(func(schema analysisSchema) bool { return schema.Owner == owner }) {
+		schemas = append(schemas, schema.AnalysisSchema)
+	}
+	sort.SliceStable(schemas, func(i, j int) bool {
+		return strings.ToLower(schemas[i].Name) < strings.ToLower(schemas[j].Name)
+	})
+	c.JSON(http.StatusOK, schemas)
+}
+
+// GetAnalysisSchema godoc
+// @Summary      Get an analysis schema
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        schema_id  path  string  true  "Schema ID"
+// @Success      200  {object}  model.AnalysisSchema  "Schema"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Schema not found"
+// @Security     bearerToken
+// @Router       /analysis/schemas/{schema_id} [get]
+func (ctl *Controller) GetAnalysisSchema(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the schema if it belongs to the token
+	schema, ok := ctl.analysisSchema(ownerOf(bearerToken), c.Param("schema_id"))
+	if !ok {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Analysis schema not found")
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// UpdateAnalysisSchema godoc
+// @Summary      Replace an analysis schema
+// @Description  Replaces the name, description, goal and questions of a schema. Results of earlier analyses are unchanged.
+// @Tags         AnalyzeCall
+// @Accept       json
+// @Produce      json
+// @Param        schema_id  path  string                       true  "Schema ID"
+// @Param        request    body  model.AnalysisSchemaRequest  true  "Schema"
+// @Success      200  {object}  model.AnalysisSchema  "Schema updated"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Schema not found"
+// @Security     bearerToken
+// @Router       /analysis/schemas/{schema_id} [put]
+func (ctl *Controller) UpdateAnalysisSchema(c *gin.Context) {
+	// Step 1: Bind and validate the request body
+	request, ok := bindAnalysisSchema(c)
+	if !ok {
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Replace the stored schema if it belongs to the token
+	owner := ownerOf(bearerToken)
+	schema, err := ctl.analysisSchemas.Update(c.Param("schema_id"), func(schema *analysisSchema) error {
+		if schema.Owner != owner {
+			return storage.ErrNotFound
+		}
+		schema.Name = request.Name
+		schema.Description = request.Description
+		schema.Goal = request.Goal
+		schema.Questions = request.Questions
+		schema.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		return nil
+	})
+	if err != nil {
+		respondErr(c, analysisSchemaError(err))
+		return
+	}
+	log.Printf("Analysis schema %s updated", schema.ID)
+
+	c.JSON(http.StatusOK, schema.AnalysisSchema)
+}
+
+// DeleteAnalysisSchema godoc
+// @Summary      Delete an analysis schema
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        schema_id  path  string  true  "Schema ID"
+// @Success      200  {object}  model.AnalysisSchema  "Schema deleted"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Schema not found"
+// @Failure      409  {object}  model.ErrorResponse  "The schema is used by an automatic analysis rule"
+// @Security     bearerToken
+// @Router       /analysis/schemas/{schema_id} [delete]
+func (ctl *Controller) DeleteAnalysisSchema(c *gin.Context) {
+	schemaID := c.Param("schema_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Delete the schema if it belongs to the token and no rule uses it
+	schema, ok := ctl.analysisSchema(ownerOf(bearerToken), schemaID)
+	if !ok {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Analysis schema not found")
+		return
+	}
+	if rules := ctl.analysisRules.List(func(rule analysisRule) bool { return rule.SchemaID == schemaID }); len(rules) > 0 {
+		respondError(c, http.StatusConflict, model.ErrCodeConflict, fmt.Sprintf("Analysis schema is used by automatic analysis rule %s", rules[0].ID))
+		return
+	}
+	if _, err := ctl.analysisSchemas.Delete(schemaID); err != nil {
+		log.Printf("Error deleting analysis schema %s: %v", schemaID, err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to delete the analysis schema")
+		return
+	}
+	log.Printf("Analysis schema %s deleted", schemaID)
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// AnalyzeCallWithSchema godoc
+// @Summary      Analyze a call with a schema
+// @Description  Asks Bland the questions of a stored analysis schema about a call and returns the answers keyed by
+// @Description  question name, converted to each question's type. Answers that do not match their type are null and
+// @Description  listed in issues, and valid is false.
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        call_id    path  string  true  "Call ID"
+// @Param        schema_id  path  string  true  "Schema ID"
+// @Success      200  {object}  model.AnalysisResult  "Typed answers"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Call or schema not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /call/{call_id}/analyze/{schema_id} [post]
+func (ctl *Controller) AnalyzeCallWithSchema(c *gin.Context) {
+	callID := c.Param("call_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Look up the schema of the token
+	schema, ok := ctl.analysisSchema(ownerOf(bearerToken), c.Param("schema_id"))
+	if !ok {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Analysis schema not found")
+		return
+	}
+
+	// Step 3: Analyze the call and type the answers
+	_, result, err := ctl.analyze(c.Request.Context(), bearerToken, callID, analysis.Request(schema), &schema)
+	if err != nil {
+		log.Printf("Error analyzing call %s with schema %s: %v", callID, schema.ID, err)
+		respondErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// analyze runs an analysis on a call and publishes analysis.completed. When schema
+// is set the request must have been built from it, and the answers are typed.
+func (ctl *Controller) analyze(ctx context.Context, bearerToken, callID string, request model.AnalyzeCallRequest, schema *model.AnalysisSchema) (*model.AnalyzeCallResponse, *model.AnalysisResult, error) {
+	response, err := ctl.client(bearerToken).AnalyzeCall(ctx, callID, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result *model.AnalysisResult
+	if schema != nil {
+		answers, rawAnswers, issues := analysis.Parse(*schema, response.Answers)
+		result = &model.AnalysisResult{
+			CallID:      callID,
+			SchemaID:    schema.ID,
+			Answers:     answers,
+			RawAnswers:  rawAnswers,
+			Valid:       len(issues) == 0,
+			Issues:      issues,
+			CreditsUsed: response.CreditsUsed,
+			AnalyzedAt:  time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	ctl.publish(ownerOf(bearerToken), events.AnalysisCompleted, model.AnalysisEvent{CallID: callID, Request: request, Response: *response, Result: result})
+	return response, result, nil
+}
+
+// analysisSchema returns the stored schema with the given ID if it belongs to owner.
+func (ctl *Controller) analysisSchema(owner, schemaID string) (model.AnalysisSchema, bool) {
+	schema, ok := ctl.analysisSchemas.Get(schemaID)
+	if !ok || schema.Owner != owner {
+		return model.AnalysisSchema{}, false
+	}
+	return schema.AnalysisSchema, true
+}
+
+// bindAnalysisSchema binds and validates a schema request body, writing a 400 when it is invalid.
+func bindAnalysisSchema(c *gin.Context) (model.AnalysisSchemaRequest, bool) {
+	var request model.AnalysisSchemaRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return request, false
+	}
+	if fields := analysis.ValidateSchema(request.Questions); len(fields) > 0 {
+		respondErr(c, validationError(fields))
+		return request, false
+	}
+	return request, true
+}
+
+// analysisSchemaError maps a storage error of the schema collection to an API error.
+func analysisSchemaError(err error) *model.ErrorResponse {
+	if errors.Is(err, storage.ErrNotFound) {
+		return &model.ErrorResponse{Code: model.ErrCodeNotFound, Message: "Analysis schema not found"}
+	}
+	log.Printf("Error saving analysis schema: %v", err)
+	return &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "Failed to save the analysis schema"}
+}
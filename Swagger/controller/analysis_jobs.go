@@ -0,0 +1,453 @@
+package controller
+
+import (
+	"bland/analysis"
+	"bland/model"
+	"bland/storage"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// analysisJobRecord is the stored form of an AnalysisJob, with the owner of
+// the token that started it (see ownerOf).
+type analysisJobRecord struct {
+	model.AnalysisJob
+	Owner string `json:"owner"`
+}
+
+// analysisJobStore keeps analysis jobs in the data directory so their progress
+// and results can be queried after a restart.
+type analysisJobStore struct {
+	jobs *storage.Collection[analysisJobRecord]
+}
+
+// openAnalysisJobStore opens the analysis jobs kept in dir. Jobs that were
+// running when the service stopped are completed, with the calls not analyzed
+// yet reported as failed.
+func openAnalysisJobStore(dir string) (*analysisJobStore, error) {
+	jobs, err := storage.Open[analysisJobRecord](dir, "analysis_jobs")
+	if err != nil {
+		return nil, err
+	}
+	s := &analysisJobStore{jobs: jobs}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, record := range jobs.List(func(record analysisJobRecord) bool { return record.Status == model.AnalysisJobRunning }) {
+		s.update(record.JobID, func(j *model.AnalysisJob) {
+			for i := range j.Results {
+				if j.Results[i].Status == model.AnalysisJobCallPending {
+					j.Results[i].Status = model.AnalysisJobCallFailed
+					j.Results[i].Error = &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "The service stopped before the call was analyzed"}
+					j.Failed++
+				}
+			}
+			j.Status, j.CompletedAt = model.AnalysisJobCompleted, now
+		})
+		log.Printf("Analysis job %s was interrupted by a restart and is completed", record.JobID)
+	}
+	return s, nil
+}
+
+// get returns a copy of a job that is safe to serialize while the job runs:
+// update never changes the results of a job in place.
+func (s *analysisJobStore) get(jobID string) (model.AnalysisJob, bool) {
+	record, ok := s.jobs.Get(jobID)
+	return record.AnalysisJob, ok
+}
+
+func (s *analysisJobStore) put(j model.AnalysisJob, owner string) error {
+	return s.jobs.Put(j.JobID, analysisJobRecord{AnalysisJob: j, Owner: owner})
+}
+
+// ownedBy reports whether a job exists and was started by owner.
+func (s *analysisJobStore) ownedBy(jobID, owner string) bool {
+	record, ok := s.jobs.Get(jobID)
+	return ok && record.Owner == owner
+}
+
+// update applies fn to a copy of a job and stores the result. Errors saving it
+// are logged and leave the job unchanged.
+func (s *analysisJobStore) update(jobID string, fn func(j *model.AnalysisJob)) {
+	_, err := s.jobs.Update(jobID, func(record *analysisJobRecord) error {
+		record.Results = append([]model.AnalysisJobCallResult(nil), record.Results...)
+		fn(&record.AnalysisJob)
+		return nil
+	})
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Error saving analysis job %s: %v", jobID, err)
+	}
+}
+
+// prune removes jobs that completed before cutoff and returns how many.
+func (s *analysisJobStore) prune(cutoff time.Time) (int, error) {
+	return s.jobs.DeleteWhere(func(record analysisJobRecord) bool {
+		completedAt, err := time.Parse(time.RFC3339, record.CompletedAt)
+		return record.Status == model.AnalysisJobCompleted && err == nil && completedAt.Before(cutoff)
+	})
+}
+
+// StartAnalysisJob godoc
+// @Summary      Analyze many calls
+// @Description  Runs the same analysis on a list of call IDs and/or the completed calls of the local history that
+// @Description  match a filter, among the calls sent or fetched with the Authorization token. The job is only returned
+// @Description  to that token. The analysis uses a stored schema, or a goal and questions. Calls are analyzed in the
+// @Description  background with bounded concurrency and at most requests_per_minute requests per minute; query
+// @Description  GET /analysis/jobs/{job_id} for progress and the total credits used.
+// @Tags         AnalyzeCall
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.AnalysisJobRequest  true  "Calls and analysis"
+// @Success      202  {object}  model.AnalysisJob  "Job accepted"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input, unknown schema or no matching calls"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      500  {object}  model.ErrorResponse  "The job could not be saved"
+// @Security     bearerToken
+// @Router       /analysis/jobs [post]
+func (ctl *Controller) StartAnalysisJob(c *gin.Context) {
+	// Step 1: Bind the request body to the AnalysisJobRequest struct
+	var request model.AnalysisJobRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Build the analysis request from the schema of the token or the goal and questions
+	var schema *model.AnalysisSchema
+	analyzeRequest := model.AnalyzeCallRequest{Goal: request.Goal, Questions: request.Questions}
+	if request.SchemaID != "" {
+		stored, ok := ctl.analysisSchema(ownerOf(bearerToken), request.SchemaID)
+		if !ok {
+			respondErr(c, validationError([]model.FieldError{{Field: "schema_id", Code: "not_found", Message: "schema_id is not a known analysis schema"}}))
+			return
+		}
+		schema = &stored
+		analyzeRequest = analysis.Request(stored)
+	}
+
+	// Step 4: Select the calls
+	callIDs, fields := ctl.analysisJobCalls(ownerOf(bearerToken), request)
+	if len(fields) > 0 {
+		respondErr(c, validationError(fields))
+		return
+	}
+	if len(callIDs) > ctl.cfg.Analysis.MaxCalls {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest,
+			fmt.Sprintf("A job can analyze at most %d calls, %d were selected", ctl.cfg.Analysis.MaxCalls, len(callIDs)))
+		return
+	}
+
+	// Step 5: Register the job and run it in the background
+	rate := ctl.cfg.Analysis.RequestsPerMinute
+	if request.RequestsPerMinute > 0 && request.RequestsPerMinute < rate {
+		rate = request.RequestsPerMinute
+	}
+	job := model.AnalysisJob{
+		JobID:     newID("ajob"),
+		Request:   analyzeRequest,
+		SchemaID:  request.SchemaID,
+		Questions: questionNames(schema),
+		Status:    model.AnalysisJobRunning,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Total:     len(callIDs),
+		Results:   make([]model.AnalysisJobCallResult, len(callIDs)),
+	}
+	for i, callID := range callIDs {
+		job.Results[i] = model.AnalysisJobCallResult{CallID: callID, Status: model.AnalysisJobCallPending}
+	}
+	if err := ctl.analysisJobs.put(job, ownerOf(bearerToken)); err != nil {
+		log.Printf("Error saving analysis job %s: %v", job.JobID, err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the analysis job")
+		return
+	}
+	ctl.background.Add(1)
+	go func() {
+		defer ctl.background.Done()
+		ctl.runAnalysisJob(bearerToken, job.JobID, callIDs, analyzeRequest, schema, rate)
+	}()
+	log.Printf("Analysis job %s accepted with %d calls", job.JobID, job.Total)
+
+	// Step 6: Return the job with every call pending
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetAnalysisJob godoc
+// @Summary      Get an analysis job
+// @Description  Returns the progress of an analysis job, the credits used so far and the outcome for each call
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        job_id  path  string  true  "Job ID"
+// @Success      200  {object}  model.AnalysisJob  "Job"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Job not found"
+// @Security     bearerToken
+// @Router       /analysis/jobs/{job_id} [get]
+func (ctl *Controller) GetAnalysisJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the job if it was started with the token
+	job, ok := ctl.analysisJobs.get(jobID)
+	if !ok || !ctl.analysisJobs.ownedBy(jobID, ownerOf(bearerToken)) {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Analysis job not found")
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// GetAnalysisJobResults godoc
+// @Summary      Export the results of an analysis job
+// @Description  Returns the outcome for each call of a job as JSON or CSV, chosen by the format query parameter or,
+// @Description  without it, by the Accept header; JSON is the default. The CSV has one row per call and one column per
+// @Description  question: the question name with typed answers for schema jobs, the question text otherwise.
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Produce      text/csv
+// @Param        job_id  path   string  true   "Job ID"
+// @Param        format  query  string  false  "Output format, overrides the Accept header"  Enums(json, csv)
+// @Success      200  {array}   model.AnalysisJobCallResult  "Results"
+// @Failure      400  {object}  model.ErrorResponse  "Unknown format"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Job not found"
+// @Security     bearerToken
+// @Router       /analysis/jobs/{job_id}/results [get]
+func (ctl *Controller) GetAnalysisJobResults(c *gin.Context) {
+	// Step 1: Choose the output format
+	format := c.Query("format")
+	switch format {
+	case "":
+		if strings.Contains(c.GetHeader("Accept"), "text/csv") {
+			format = "csv"
+		}
+	case "json", "csv":
+	default:
+		respondErr(c, validationError([]model.FieldError{{Field: "format", Code: "oneof", Message: "format must be one of json or csv"}}))
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Look up the job if it was started with the token
+	jobID := c.Param("job_id")
+	job, ok := ctl.analysisJobs.get(jobID)
+	if !ok || !ctl.analysisJobs.ownedBy(jobID, ownerOf(bearerToken)) {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Analysis job not found")
+		return
+	}
+
+	// Step 4: Render the results
+	if format != "csv" {
+		c.JSON(http.StatusOK, job.Results)
+		return
+	}
+	var buf bytes.Buffer
+	if err := writeAnalysisJobCSV(&buf, job, analysisJobColumns(job)); err != nil {
+		log.Printf("Error rendering results of analysis job %s: %v", job.JobID, err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to render the results")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.JobID+".csv"))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// analysisJobCalls returns the IDs of the calls a job request of owner selects,
+// without duplicates, or the FieldErrors that explain why none can be selected.
+// A filter only selects calls of the owner's call history.
+func (ctl *Controller) analysisJobCalls(owner string, request model.AnalysisJobRequest) ([]string, []model.FieldError) {
+	if len(request.CallIDs) == 0 && request.Filter == nil {
+		return nil, []model.FieldError{{Field: "call_ids", Code: "required_without", Message: "call_ids is required when filter is not set"}}
+	}
+
+	seen := make(map[string]bool)
+	var callIDs []string
+	for i, callID := range request.CallIDs {
+		if callID == "" {
+			return nil, []model.FieldError{{Field: fmt.Sprintf("call_ids[%d]", i), Code: "required", Message: "call ID must not be empty"}}
+		}
+		if !seen[callID] {
+			seen[callID] = true
+			callIDs = append(callIDs, callID)
+		}
+	}
+	if request.Filter == nil {
+		return callIDs, nil
+	}
+
+	var fields []model.FieldError
+	start, err := parseDateParam(request.Filter.StartDate, false)
+	if err != nil {
+		fields = append(fields, model.FieldError{Field: "filter.start_date", Code: "datetime", Message: err.Error()})
+	}
+	end, err := parseDateParam(request.Filter.EndDate, true)
+	if err != nil {
+		fields = append(fields, model.FieldError{Field: "filter.end_date", Code: "datetime", Message: err.Error()})
+	}
+	if len(fields) > 0 {
+		return nil, fields
+	}
+
+	filter := callFilter{pathwayID: request.Filter.PathwayID, batchID: request.Filter.BatchID, start: start, end: end}
+	calls := ctl.calls.List(func(call callRecord) bool {
+		return call.Owner == owner && call.Completed && filter.match(call.CallRecord)
+	})
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].CreatedAt < calls[j].CreatedAt })
+	for _, call := range calls {
+		if !seen[call.CallID] {
+			seen[call.CallID] = true
+			callIDs = append(callIDs, call.CallID)
+		}
+	}
+	if len(callIDs) == 0 {
+		return nil, []model.FieldError{{Field: "filter", Code: "no_match", Message: "no completed call in the call history matches the filter"}}
+	}
+	return callIDs, nil
+}
+
+// questionNames returns the names of the questions of schema, or nil without a schema.
+func questionNames(schema *model.AnalysisSchema) []string {
+	if schema == nil {
+		return nil
+	}
+	names := make([]string, len(schema.Questions))
+	for i, q := range schema.Questions {
+		names[i] = q.Name
+	}
+	return names
+}
+
+// runAnalysisJob analyzes the calls of a job with at most cfg.Analysis.Concurrency
+// in flight and at most rate requests per minute, recording each outcome.
+func (ctl *Controller) runAnalysisJob(bearerToken, jobID string, callIDs []string, request model.AnalyzeCallRequest, schema *model.AnalysisSchema, rate int) {
+	sem := make(chan struct{}, ctl.cfg.Analysis.Concurrency)
+	var wg sync.WaitGroup
+	interval := time.Minute / time.Duration(rate)
+	next := time.Now()
+
+	for i, callID := range callIDs {
+		if wait := time.Until(next); wait > 0 {
+			time.Sleep(wait)
+		}
+		next = next.Add(interval)
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, callID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			response, result, err := ctl.analyze(context.Background(), bearerToken, callID, request, schema)
+			ctl.analysisJobs.update(jobID, func(j *model.AnalysisJob) {
+				outcome := &j.Results[i]
+				if err != nil {
+					outcome.Status = model.AnalysisJobCallFailed
+					outcome.Error = toErrorResponse(err)
+					j.Failed++
+					return
+				}
+				outcome.Status = model.AnalysisJobCallAnalyzed
+				outcome.Response = response
+				outcome.Result = result
+				j.Analyzed++
+				j.CreditsUsed += response.CreditsUsed
+			})
+		}(i, callID)
+	}
+
+	wg.Wait()
+	ctl.analysisJobs.update(jobID, func(j *model.AnalysisJob) {
+		j.Status = model.AnalysisJobCompleted
+		j.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	})
+	log.Printf("Analysis job %s completed", jobID)
+}
+
+// analysisJobColumns returns the CSV answer column of each question of a job:
+// the question names of its schema, or the question texts.
+func analysisJobColumns(job model.AnalysisJob) []string {
+	if len(job.Questions) > 0 {
+		return job.Questions
+	}
+	columns := make([]string, len(job.Request.Questions))
+	for i, q := range job.Request.Questions {
+		if len(q) > 0 {
+			columns[i] = q[0]
+		}
+	}
+	return columns
+}
+
+// writeAnalysisJobCSV writes one row per call of a job. Typed answers are used
+// for schema jobs, the answers returned by Bland otherwise.
+func writeAnalysisJobCSV(buf *bytes.Buffer, job model.AnalysisJob, columns []string) error {
+	w := csv.NewWriter(buf)
+	header := append([]string{"call_id", "status", "credits_used", "error"}, columns...)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, outcome := range job.Results {
+		row := make([]string, 4, len(header))
+		row[0], row[1] = outcome.CallID, outcome.Status
+		if outcome.Response != nil {
+			row[2] = strconv.FormatFloat(outcome.Response.CreditsUsed, 'f', -1, 64)
+		}
+		if outcome.Error != nil {
+			row[3] = outcome.Error.Message
+		}
+		for i, column := range columns {
+			var value string
+			switch {
+			case outcome.Result != nil:
+				value = formatAnswer(outcome.Result.Answers[column])
+			case outcome.Response != nil && i < len(outcome.Response.Answers):
+				value = outcome.Response.Answers[i]
+			}
+			row = append(row, value)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// formatAnswer formats a typed answer for a CSV cell; null answers are left empty.
+func formatAnswer(answer interface{}) string {
+	switch v := answer.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"bland/model"
+	"testing"
+	"time"
+)
+
+func TestOpenAnalysisJobStoreCompletesInterruptedJobs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openAnalysisJobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	running := model.AnalysisJob{JobID: "ajob-1", Status: model.AnalysisJobRunning, Total: 2, Analyzed: 1, Results: []model.AnalysisJobCallResult{
+		{CallID: "call-1", Status: model.AnalysisJobCallAnalyzed},
+		{CallID: "call-2", Status: model.AnalysisJobCallPending},
+	}}
+	if err := store.put(running, "owner"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := openAnalysisJobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := reopened.get("ajob-1")
+	if !ok || !reopened.ownedBy("ajob-1", "owner") {
+		t.Fatalf("ajob-1 = %+v, %v after reopening, want it kept with its owner", got, ok)
+	}
+	if got.Status != model.AnalysisJobCompleted || got.CompletedAt == "" || got.Analyzed != 1 || got.Failed != 1 {
+		t.Errorf("job = %+v, want it completed with the pending call failed", got)
+	}
+	if got.Results[1].Status != model.AnalysisJobCallFailed || got.Results[1].Error == nil {
+		t.Errorf("pending call = %+v, want it failed with an error", got.Results[1])
+	}
+}
+
+func TestAnalysisJobStorePrune(t *testing.T) {
+	store, err := openAnalysisJobStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	for _, j := range []model.AnalysisJob{
+		{JobID: "old", Status: model.AnalysisJobCompleted, CompletedAt: now.Add(-48 * time.Hour).Format(time.RFC3339)},
+		{JobID: "recent", Status: model.AnalysisJobCompleted, CompletedAt: now.Format(time.RFC3339)},
+		{JobID: "running", Status: model.AnalysisJobRunning},
+	} {
+		if err := store.put(j, "owner"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if removed, err := store.prune(now.Add(-24 * time.Hour)); err != nil || removed != 1 {
+		t.Fatalf("prune = %d, %v, want 1 removed", removed, err)
+	}
+	for id, want := range map[string]bool{"old": false, "recent": true, "running": true} {
+		if _, ok := store.get(id); ok != want {
+			t.Errorf("job %s kept = %v, want %v", id, ok, want)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package controller
+
+import (
+	"bland/analysis"
+	"bland/model"
+	"bland/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	autoAnalysisTimeout      = 2 * time.Minute  // Time allowed for one automatic analysis
+	autoAnalysisPollInterval = time.Minute      // How often calls of pathways with rules are checked for completion
+	autoAnalysisPollWindow   = 24 * time.Hour   // Calls older than this are no longer checked
+	autoAnalysisPollTimeout  = 30 * time.Second // Time allowed for checking one call
+)
+
+// analysisRule is the stored form of an AutoAnalysisRule. The sealed token is
+// the Authorization header of the request that last saved the rule, encrypted;
+// analyses triggered by webhooks have no request of their own to take it from.
+// Owner is the owner of that token, the only one the rule is returned to.
+type analysisRule struct {
+	model.AutoAnalysisRule
+	Owner       string `json:"owner"`
+	SealedToken string `json:"sealed_token"`
+}
+
+// CreateAutoAnalysisRule godoc
+// @Summary      Create an automatic analysis rule
+// @Description  Analyzes every call of a pathway sent or fetched with the Authorization token of this request as soon
+// @Description  as the proxy sees it complete, by polling or by webhook. The analysis uses a stored schema, or a goal
+// @Description  and questions. Its outcome is stored with the call and returned in the analyses field of
+// @Description  GET /calls/{call_id}. The Authorization token of this request is kept, encrypted, to run the analyses,
+// @Description  and the rule is only returned to and changed with that token.
+// @Description  Calls of the pathway sent in the last 24 hours are checked for completion every minute, so calls that
+// @Description  are neither fetched nor reported by a Bland callback are analyzed too.
+// @Tags         AnalyzeCall
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.AutoAnalysisRuleRequest  true  "Rule"
+// @Success      201  {object}  model.AutoAnalysisRule  "Rule created"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input or unknown schema"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /analysis/rules [post]
+func (ctl *Controller) CreateAutoAnalysisRule(c *gin.Context) {
+	// Step 1: Bind and validate the request body
+	request, bearerToken, ok := ctl.bindAutoAnalysisRule(c)
+	if !ok {
+		return
+	}
+
+	// Step 2: Store the rule with the encrypted token
+	sealed, err := ctl.sealer.Seal(bearerToken)
+	if err != nil {
+		log.Printf("Error sealing the token of an automatic analysis rule: %v", err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the automatic analysis rule")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	rule := analysisRule{Owner: ownerOf(bearerToken), SealedToken: sealed, AutoAnalysisRule: model.AutoAnalysisRule{ID: newID("rule"), CreatedAt: now}}
+	applyAutoAnalysisRule(&rule, request, now)
+	if err := ctl.analysisRules.Put(rule.ID, rule); err != nil {
+		log.Printf("Error saving automatic analysis rule: %v", err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the automatic analysis rule")
+		return
+	}
+	log.Printf("Automatic analysis rule %s created for pathway %s", rule.ID, rule.PathwayID)
+
+	c.JSON(http.StatusCreated, rule.AutoAnalysisRule)
+}
+
+// ListAutoAnalysisRules godoc
+// @Summary      List automatic analysis rules
+// @Description  Returns the automatic analysis rules of the Authorization token, oldest first, optionally only those of a pathway
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        pathway_id  query  string  false  "Only rules of this pathway"
+// @Success      200  {array}  model.AutoAnalysisRule  "Rules"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /analysis/rules [get]
+func (ctl *Controller) ListAutoAnalysisRules(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the rules of the token
+	owner, pathwayID := ownerOf(bearerToken), c.Query("pathway_id")
+	c.JSON(http.StatusOK, ctl.autoAnalysisRules(func(rule analysisRule) bool {
+		return rule.Owner == owner && (pathwayID == "" || rule.PathwayID == pathwayID)
+	}))
+}
+
+// GetAutoAnalysisRule godoc
+// @Summary      Get an automatic analysis rule
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        rule_id  path  string  true  "Rule ID"
+// @Success      200  {object}  model.AutoAnalysisRule  "Rule"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Rule not found"
+// @Security     bearerToken
+// @Router       /analysis/rules/{rule_id} [get]
+func (ctl *Controller) GetAutoAnalysisRule(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the rule if it belongs to the token
+	rule, ok := ctl.analysisRules.Get(c.Param("rule_id"))
+	if !ok || rule.Owner != ownerOf(bearerToken) {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Automatic analysis rule not found")
+		return
+	}
+	c.JSON(http.StatusOK, rule.AutoAnalysisRule)
+}
+
+// UpdateAutoAnalysisRule godoc
+// @Summary      Replace an automatic analysis rule
+// @Description  Replaces a rule of the Authorization token. The token of this request replaces the one kept to run the
+// @Description  analyses.
+// @Tags         AnalyzeCall
+// @Accept       json
+// @Produce      json
+// @Param        rule_id  path  string                         true  "Rule ID"
+// @Param        request  body  model.AutoAnalysisRuleRequest  true  "Rule"
+// @Success      200  {object}  model.AutoAnalysisRule  "Rule updated"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input or unknown schema"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Rule not found"
+// @Security     bearerToken
+// @Router       /analysis/rules/{rule_id} [put]
+func (ctl *Controller) UpdateAutoAnalysisRule(c *gin.Context) {
+	// Step 1: Bind and validate the request body
+	request, bearerToken, ok := ctl.bindAutoAnalysisRule(c)
+	if !ok {
+		return
+	}
+
+	// Step 2: Replace the stored rule if it belongs to the token
+	sealed, err := ctl.sealer.Seal(bearerToken)
+	if err != nil {
+		respondErr(c, analysisRuleError(err))
+		return
+	}
+	owner := ownerOf(bearerToken)
+	rule, err := ctl.analysisRules.Update(c.Param("rule_id"), func(rule *analysisRule) error {
+		if rule.Owner != owner {
+			return storage.ErrNotFound
+		}
+		rule.SealedToken = sealed
+		applyAutoAnalysisRule(rule, request, time.Now().UTC().Format(time.RFC3339Nano))
+		return nil
+	})
+	if err != nil {
+		respondErr(c, analysisRuleError(err))
+		return
+	}
+	log.Printf("Automatic analysis rule %s updated", rule.ID)
+
+	c.JSON(http.StatusOK, rule.AutoAnalysisRule)
+}
+
+// DeleteAutoAnalysisRule godoc
+// @Summary      Delete an automatic analysis rule
+// @Description  Stops analyzing the calls of the rule's pathway. Analyses already stored with calls are kept.
+// @Tags         AnalyzeCall
+// @Produce      json
+// @Param        rule_id  path  string  true  "Rule ID"
+// @Success      200  {object}  model.AutoAnalysisRule  "Rule deleted"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Rule not found"
+// @Security     bearerToken
+// @Router       /analysis/rules/{rule_id} [delete]
+func (ctl *Controller) DeleteAutoAnalysisRule(c *gin.Context) {
+	ruleID := c.Param("rule_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Delete the rule if it belongs to the token
+	rule, ok := ctl.analysisRules.Get(ruleID)
+	if !ok || rule.Owner != ownerOf(bearerToken) {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Automatic analysis rule not found")
+		return
+	}
+	if _, err := ctl.analysisRules.Delete(ruleID); err != nil {
+		log.Printf("Error deleting automatic analysis rule %s: %v", ruleID, err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to delete the automatic analysis rule")
+		return
+	}
+	log.Printf("Automatic analysis rule %s deleted", ruleID)
+
+	c.JSON(http.StatusOK, rule.AutoAnalysisRule)
+}
+
+// bindAutoAnalysisRule binds and validates a rule request body and reads the
+// bearer token, writing the error response when either is invalid.
+func (ctl *Controller) bindAutoAnalysisRule(c *gin.Context) (model.AutoAnalysisRuleRequest, string, bool) {
+	var request model.AutoAnalysisRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return request, "", false
+	}
+
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return request, "", false
+	}
+
+	if request.SchemaID != "" {
+		if _, ok := ctl.analysisSchema(ownerOf(bearerToken), request.SchemaID); !ok {
+			respondErr(c, validationError([]model.FieldError{{Field: "schema_id", Code: "not_found", Message: "schema_id is not a known analysis schema"}}))
+			return request, "", false
+		}
+	}
+	return request, bearerToken, true
+}
+
+// analysisRuleError maps a storage error of the rule collection to an API error.
+func analysisRuleError(err error) *model.ErrorResponse {
+	if errors.Is(err, storage.ErrNotFound) {
+		return &model.ErrorResponse{Code: model.ErrCodeNotFound, Message: "Automatic analysis rule not found"}
+	}
+	log.Printf("Error saving automatic analysis rule: %v", err)
+	return &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "Failed to save the automatic analysis rule"}
+}
+
+// applyAutoAnalysisRule copies a rule request onto rule. A schema takes the place of the goal and questions.
+func applyAutoAnalysisRule(rule *analysisRule, request model.AutoAnalysisRuleRequest, now string) {
+	rule.PathwayID = request.PathwayID
+	rule.SchemaID = request.SchemaID
+	rule.Goal, rule.Questions = "", nil
+	if request.SchemaID == "" {
+		rule.Goal, rule.Questions = request.Goal, request.Questions
+	}
+	rule.Enabled = request.Enabled == nil || *request.Enabled
+	rule.UpdatedAt = now
+}
+
+// autoAnalysisRules returns the rules kept by keep, oldest first.
+func (ctl *Controller) autoAnalysisRules(keep func(rule analysisRule) bool) []model.AutoAnalysisRule {
+	stored := ctl.analysisRules.List(keep)
+	createdAt := func(rule analysisRule) time.Time {
+		t, _ := time.Parse(time.RFC3339Nano, rule.CreatedAt)
+		return t
+	}
+	sort.SliceStable(stored, func(i, j int) bool { return createdAt(stored[i]).Before(createdAt(stored[j])) })
+	rules := make([]model.AutoAnalysisRule, 0, len(stored))
+	for _, rule := range stored {
+		rules = append(rules, rule.AutoAnalysisRule)
+	}
+	return rules
+}
+
+// runAutoAnalyses is subscribed to call.completed. It runs the enabled rules
+// the owner of the call saved for its pathway in the background and stores
+// their outcome with the call.
+func (ctl *Controller) runAutoAnalyses(event model.Event) {
+	callDetail, ok := event.Data.(model.CallDetail)
+	if !ok {
+		return
+	}
+	record, ok := ctl.calls.Get(callDetail.CallID)
+	if !ok || record.PathwayID == "" {
+		return
+	}
+	rules := ctl.analysisRules.List(func(rule analysisRule) bool {
+		return rule.Enabled && rule.PathwayID == record.PathwayID && rule.Owner == record.Owner
+	})
+	for _, rule := range rules {
+		ctl.background.Add(1)
+		go func(rule analysisRule) {
+			defer ctl.background.Done()
+			ctl.runAutoAnalysis(rule, callDetail.CallID)
+		}(rule)
+	}
+}
+
+// runAutoAnalysis runs one rule on a call and stores the outcome with the call,
+// replacing any earlier outcome of the same rule.
+func (ctl *Controller) runAutoAnalysis(rule analysisRule, callID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), autoAnalysisTimeout)
+	defer cancel()
+
+	outcome := model.CallAnalysis{RuleID: rule.ID}
+	bearerToken, err := ctl.sealer.Open(rule.SealedToken)
+	var schema *model.AnalysisSchema
+	if err != nil {
+		outcome.Error = &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "The stored Authorization token cannot be decrypted; was the secret key changed?"}
+	} else if rule.SchemaID != "" {
+		stored, ok := ctl.analysisSchema(rule.Owner, rule.SchemaID)
+		if !ok {
+			outcome.Error = &model.ErrorResponse{Code: model.ErrCodeNotFound, Message: fmt.Sprintf("Analysis schema %s not found", rule.SchemaID)}
+		} else {
+			schema = &stored
+			outcome.Request = analysis.Request(stored)
+		}
+	} else {
+		outcome.Request = model.AnalyzeCallRequest{Goal: rule.Goal, Questions: rule.Questions}
+	}
+
+	if outcome.Error == nil {
+		response, result, err := ctl.analyze(ctx, bearerToken, callID, outcome.Request, schema)
+		if err != nil {
+			log.Printf("Error running automatic analysis rule %s on call %s: %v", rule.ID, callID, err)
+			outcome.Error = toErrorResponse(err)
+		}
+		outcome.Response, outcome.Result = response, result
+	}
+	outcome.AnalyzedAt = time.Now().UTC().Format(time.RFC3339)
+
+	_, err = ctl.calls.Update(callID, func(record *callRecord) error {
+		analyses := record.Analyses[:0:0]
+		for _, existing := range record.Analyses {
+			if existing.RuleID != rule.ID {
+				analyses = append(analyses, existing)
+			}
+		}
+		record.Analyses = append(analyses, outcome)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error storing automatic analysis of call %s: %v", callID, err)
+	}
+}
+
+// rulePathway identifies the rules an owner saved for a pathway.
+type rulePathway struct {
+	Owner     string
+	PathwayID string
+}
+
+// pollAutoAnalysisCalls checks the calls of pathways with enabled rules for
+// completion every interval until ctx is cancelled, so rules also run for calls
+// nobody fetches and Bland does not report with a callback. Calls are fetched
+// with the token of a rule of their pathway saved by the owner of the call, and
+// only while they are younger than autoAnalysisPollWindow.
+func (ctl *Controller) pollAutoAnalysisCalls(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tokens := make(map[rulePathway]string)
+		for _, rule := range ctl.analysisRules.List(func(rule analysisRule) bool { return rule.Enabled }) {
+			key := rulePathway{Owner: rule.Owner, PathwayID: rule.PathwayID}
+			if _, ok := tokens[key]; ok {
+				continue
+			}
+			if bearerToken, err := ctl.sealer.Open(rule.SealedToken); err == nil {
+				tokens[key] = bearerToken
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		cutoff := time.Now().Add(-autoAnalysisPollWindow)
+		for _, call := range ctl.calls.List(func(call callRecord) bool {
+			createdAt, err := time.Parse(time.RFC3339, call.CreatedAt)
+			return !call.Completed && call.StoppedAt == "" && tokens[rulePathway{call.Owner, call.PathwayID}] != "" && err == nil && createdAt.After(cutoff)
+		}) {
+			if ctx.Err() != nil {
+				return
+			}
+			callCtx, cancel := context.WithTimeout(ctx, autoAnalysisPollTimeout)
+			callDetail, err := ctl.client(tokens[rulePathway{call.Owner, call.PathwayID}]).GetCall(callCtx, call.CallID)
+			cancel()
+			if err != nil {
+				log.Printf("Error checking call %s for automatic analysis: %v", call.CallID, err)
+				continue
+			}
+			ctl.cacheCallDetail(*callDetail, "")
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// batchRecord is the stored form of a Batch, with the owner of the token that
+// started it (see ownerOf).
+type batchRecord struct {
+	model.Batch
+	Owner string `json:"owner"`
+}
+
+// batchStore keeps batches in the data directory so their results can be
+// queried after dispatch and after a restart.
+type batchStore struct {
+	batches *storage.Collection[batchRecord]
+}
+
+// openBatchStore opens the batches kept in dir. Batches that were running when
+// the service stopped are marked stopped: their pending contacts are cancelled
+// and contacts that were being dispatched are reported as failed, since
+// whether Bland accepted them is unknown.
+func openBatchStore(dir string) (*batchStore, error) {
+	batches, err := storage.Open[batchRecord](dir, "batches")
+	if err != nil {
+		return nil, err
+	}
+	s := &batchStore{batches: batches}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, record := range batches.List(func(record batchRecord) bool { return record.Status == model.BatchStatusRunning }) {
+		s.update(record.BatchID, func(b *model.Batch) {
+			for i := range b.Results {
+				switch b.Results[i].Status {
+				case model.BatchContactPending:
+					b.Results[i].Status = model.BatchContactCancelled
+					b.Cancelled++
+				case model.BatchContactDispatching:
+					b.Results[i].Status = model.BatchContactFailed
+					b.Results[i].Error = &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "The service stopped while the call was being dispatched; it may have been placed"}
+					b.Failed++
+				}
+			}
+			b.Status, b.CompletedAt = model.BatchStatusStopped, now
+		})
+		log.Printf("Batch %s was interrupted by a restart and is stopped", record.BatchID)
+	}
+	return s, nil
+}
+
+// get returns a copy of a batch that is safe to serialize while dispatch
+// continues: update never changes the results of a batch in place.
+func (s *batchStore) get(batchID string) (model.Batch, bool) {
+	record, ok := s.batches.Get(batchID)
+	return record.Batch, ok
+}
+
+func (s *batchStore) put(b model.Batch, owner string) error {
+	return s.batches.Put(b.BatchID, batchRecord{Batch: b, Owner: owner})
+}
+
+// ownedBy reports whether a batch exists and was started by owner.
+func (s *batchStore) ownedBy(batchID, owner string) bool {
+	record, ok := s.batches.Get(batchID)
+	return ok && record.Owner == owner
+}
+
+// update applies fn to a copy of a batch and stores the result. Errors saving
+// it are logged and leave the batch unchanged.
+func (s *batchStore) update(batchID string, fn func(b *model.Batch)) {
+	_, err := s.batches.Update(batchID, func(record *batchRecord) error {
+		record.Results = append([]model.BatchContactResult(nil), record.Results...)
+		fn(&record.Batch)
+		return nil
+	})
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Error saving batch %s: %v", batchID, err)
+	}
+}
+
+// prune removes batches that finished before cutoff and returns how many.
+func (s *batchStore) prune(cutoff time.Time) (int, error) {
+	return s.batches.DeleteWhere(func(record batchRecord) bool {
+		completedAt, err := time.Parse(time.RFC3339, record.CompletedAt)
+		return record.Status != model.BatchStatusRunning && err == nil && completedAt.Before(cutoff)
+	})
+}
+
+// DispatchBatch godoc
+// @Summary      Dispatch a batch of calls
+// @Description  Sends a call to every contact using the same pathway, with bounded concurrency.
+// @Description  Each contact goes through the same validation as POST /call. Dispatch continues in the background;
+// @Description  query GET /calls/batch/{batch_id} for per-contact results.
+// @Tags         SendCall
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.BatchCallRequest  true  "Contacts and pathway ID"
+// @Success      202  {object}  model.Batch  "Batch accepted"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      500  {object}  model.ErrorResponse  "The batch could not be saved"
+// @Security     bearerToken
+// @Router       /calls/batch [post]
+func (ctl *Controller) DispatchBatch(c *gin.Context) {
+	// Step 1: Bind the request body to the BatchCallRequest struct
+	var request model.BatchCallRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if len(request.Contacts) > ctl.cfg.Batch.MaxContacts {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest,
+			fmt.Sprintf("A batch can contain at most %d contacts", ctl.cfg.Batch.MaxContacts))
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Register the batch and dispatch it in the background
+	calls := make([]model.SendCall, len(request.Contacts))
+	for i, contact := range request.Contacts {
+		calls[i] = model.SendCall{
+			PhoneNumber: contact.PhoneNumber,
+			PathwayID:   request.PathwayID,
+			RequestData: contact.Variables,
+			Metadata:    contact.Metadata,
+		}
+	}
+	batch, err := ctl.startBatch(bearerToken, request.PathwayID, calls)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+	log.Printf("Batch %s accepted with %d contacts", batch.BatchID, batch.Total)
+
+	// Step 4: Return the batch with every contact pending
+	c.JSON(http.StatusAccepted, batch)
+}
+
+// GetBatch godoc
+// @Summary      Get batch results
+// @Description  Returns the progress of a batch and the dispatch outcome for each contact. Only the Authorization
+// @Description  token that started the batch can read it.
+// @Tags         SendCall
+// @Produce      json
+// @Param        batch_id  path  string  true  "Batch ID"
+// @Success      200  {object}  model.Batch  "Batch results"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Batch not found"
+// @Security     bearerToken
+// @Router       /calls/batch/{batch_id} [get]
+func (ctl *Controller) GetBatch(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	batchID := c.Param("batch_id")
+	batch, ok := ctl.batches.get(batchID)
+	if !ok || !ctl.batches.ownedBy(batchID, ownerOf(bearerToken)) {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Batch not found")
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
+// startBatch registers a batch for calls and dispatches them in the background,
+// returning a snapshot with every contact pending. Each call is tagged with the
+// batch ID in its metadata.
+func (ctl *Controller) startBatch(bearerToken, pathwayID string, calls []model.SendCall) (model.Batch, error) {
+	batch := model.Batch{
+		BatchID:   newID("batch"),
+		PathwayID: pathwayID,
+		Status:    model.BatchStatusRunning,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Total:     len(calls),
+		Results:   make([]model.BatchContactResult, len(calls)),
+	}
+	for i := range calls {
+		batch.Results[i] = model.BatchContactResult{Index: i, PhoneNumber: calls[i].PhoneNumber, Status: model.BatchContactPending}
+		calls[i].Metadata = withMetadata(calls[i].Metadata, "batch_id", batch.BatchID)
+	}
+	if err := ctl.batches.put(batch, ownerOf(bearerToken)); err != nil {
+		log.Printf("Error saving batch %s: %v", batch.BatchID, err)
+		return model.Batch{}, &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "Failed to save the batch"}
+	}
+
+	ctl.background.Add(1)
+	go func() {
+		defer ctl.background.Done()
+		ctl.runBatch(bearerToken, batch.BatchID, calls)
+	}()
+	return batch, nil
+}
+
+// runBatch dispatches calls with at most cfg.Batch.Concurrency in flight and
+// records each outcome in the batch store. Once the batch is stopped, pending
+// contacts are skipped and calls dispatched in the meantime are ended.
+func (ctl *Controller) runBatch(bearerToken, batchID string, calls []model.SendCall) {
+	sem := make(chan struct{}, ctl.cfg.Batch.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, call := range calls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, call model.SendCall) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Contacts cancelled by StopBatch are not called
+			var stopped bool
+			ctl.batches.update(batchID, func(b *model.Batch) {
+				stopped = b.Results[i].Status == model.BatchContactCancelled
+				if !stopped {
+					b.Results[i].Status = model.BatchContactDispatching
+				}
+			})
+			if stopped {
+				return
+			}
+
+			response, err := ctl.dispatchCall(context.Background(), bearerToken, call)
+			ctl.batches.update(batchID, func(b *model.Batch) {
+				stopped = b.Status == model.BatchStatusStopped
+				result := &b.Results[i]
+				if err != nil {
+					result.Status = model.BatchContactFailed
+					result.Error = toErrorResponse(err)
+					b.Failed++
+					return
+				}
+				result.Status = model.BatchContactDispatched
+				result.CallID = response.CallID
+				b.Dispatched++
+			})
+
+			// The batch was stopped while this call was being dispatched
+			if err == nil && stopped {
+				if _, err := ctl.stopCall(context.Background(), bearerToken, response.CallID); err != nil {
+					log.Printf("Error stopping call %s of stopped batch %s: %v", response.CallID, batchID, err)
+				}
+			}
+		}(i, call)
+	}
+
+	wg.Wait()
+	ctl.batches.update(batchID, func(b *model.Batch) {
+		if b.Status == model.BatchStatusRunning {
+			b.Status = model.BatchStatusCompleted
+		}
+		b.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	})
+	log.Printf("Batch %s finished", batchID)
+}
+
+// withMetadata returns a copy of metadata with key set, replacing any value the
+// caller gave it, so keys the proxy relies on cannot be spoofed.
+func withMetadata(metadata map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"bland/model"
+	"testing"
+	"time"
+)
+
+func TestOpenBatchStoreStopsInterruptedBatches(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openBatchStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	running := model.Batch{BatchID: "batch-1", Status: model.BatchStatusRunning, Total: 3, Dispatched: 1, Results: []model.BatchContactResult{
+		{Index: 0, Status: model.BatchContactDispatched, CallID: "call-1"},
+		{Index: 1, Status: model.BatchContactDispatching},
+		{Index: 2, Status: model.BatchContactPending},
+	}}
+	if err := store.put(running, "owner"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := openBatchStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := reopened.get("batch-1")
+	if !ok || !reopened.ownedBy("batch-1", "owner") {
+		t.Fatalf("batch-1 = %+v, %v after reopening, want it kept with its owner", got, ok)
+	}
+	if got.Status != model.BatchStatusStopped || got.CompletedAt == "" || got.Dispatched != 1 || got.Failed != 1 || got.Cancelled != 1 {
+		t.Errorf("batch = %+v, want it stopped with one contact failed and one cancelled", got)
+	}
+	if got.Results[1].Status != model.BatchContactFailed || got.Results[1].Error == nil || got.Results[2].Status != model.BatchContactCancelled {
+		t.Errorf("results = %+v, want the contact being dispatched failed and the pending one cancelled", got.Results)
+	}
+}
+
+func TestBatchStorePrune(t *testing.T) {
+	store, err := openBatchStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	for _, b := range []model.Batch{
+		{BatchID: "old", Status: model.BatchStatusCompleted, CompletedAt: now.Add(-48 * time.Hour).Format(time.RFC3339)},
+		{BatchID: "recent", Status: model.BatchStatusStopped, CompletedAt: now.Format(time.RFC3339)},
+		{BatchID: "running", Status: model.BatchStatusRunning},
+	} {
+		if err := store.put(b, "owner"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if removed, err := store.prune(now.Add(-24 * time.Hour)); err != nil || removed != 1 {
+		t.Fatalf("prune = %d, %v, want 1 removed", removed, err)
+	}
+	for id, want := range map[string]bool{"old": false, "recent": true, "running": true} {
+		if _, ok := store.get(id); ok != want {
+			t.Errorf("batch %s kept = %v, want %v", id, ok, want)
+		}
+	}
+}
+
+func TestWithMetadata(t *testing.T) {
+	metadata := map[string]interface{}{"crm_id": "c1", "batch_id": "spoofed"}
+	got := withMetadata(metadata, "batch_id", "batch-1")
+	if got["batch_id"] != "batch-1" || got["crm_id"] != "c1" {
+		t.Errorf("metadata = %v, want batch_id replaced and crm_id kept", got)
+	}
+	if metadata["batch_id"] != "spoofed" {
+		t.Error("withMetadata changed its argument")
+	}
+}
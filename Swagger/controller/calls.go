@@ -0,0 +1,304 @@
+package controller
+
+import (
+	"bland/events"
+	"bland/model"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultCallListLimit = 100
+	maxCallListLimit     = 1000
+)
+
+// ListCalls godoc
+// @Summary      List call history
+// @Description  Returns calls from the local history, newest first. Every call sent through the proxy is recorded,
+// @Description  and call details fetched from Bland update it. Dates are RFC 3339 times or YYYY-MM-DD days; an
+// @Description  end_date day is inclusive. Only calls sent or fetched with the same Authorization token are listed.
+// @Description  Cached call details are omitted; use GET /calls/{call_id} for them.
+// @Tags         CallDetails
+// @Produce      json
+// @Param        pathway_id   query  string  false  "Only calls of this pathway"
+// @Param        status       query  string  false  "Only calls with this Bland status, e.g. queued or completed"
+// @Param        answered_by  query  string  false  "Only calls answered by, e.g. human or voicemail"
+// @Param        batch_id     query  string  false  "Only calls of this batch"
+// @Param        start_date   query  string  false  "Only calls created at or after"
+// @Param        end_date     query  string  false  "Only calls created at or before"
+// @Param        limit        query  int     false  "Page size, at most 1000"  default(100)
+// @Param        offset       query  int     false  "Number of calls to skip"  default(0)
+// @Success      200  {object}  model.CallListResponse  "Calls"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid filter"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /calls [get]
+func (ctl *Controller) ListCalls(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	owner := ownerOf(bearerToken)
+
+	// Step 2: Parse the filters and paging parameters
+	var fields []model.FieldError
+	start, err := parseDateParam(c.Query("start_date"), false)
+	if err != nil {
+		fields = append(fields, model.FieldError{Field: "start_date", Code: "datetime", Message: err.Error()})
+	}
+	end, err := parseDateParam(c.Query("end_date"), true)
+	if err != nil {
+		fields = append(fields, model.FieldError{Field: "end_date", Code: "datetime", Message: err.Error()})
+	}
+	limit, err := parseIntParam(c.Query("limit"), defaultCallListLimit, 1, maxCallListLimit)
+	if err != nil {
+		fields = append(fields, model.FieldError{Field: "limit", Code: "range", Message: err.Error()})
+	}
+	offset, err := parseIntParam(c.Query("offset"), 0, 0, -1)
+	if err != nil {
+		fields = append(fields, model.FieldError{Field: "offset", Code: "range", Message: err.Error()})
+	}
+	if len(fields) > 0 {
+		respondErr(c, validationError(fields))
+		return
+	}
+
+	// Step 3: Select the matching calls of the token, newest first
+	filter := callFilter{
+		pathwayID:  c.Query("pathway_id"),
+		status:     c.Query("status"),
+		answeredBy: c.Query("answered_by"),
+		batchID:    c.Query("batch_id"),
+		start:      start,
+		end:        end,
+	}
+	calls := ctl.calls.List(func(call callRecord) bool { return call.Owner == owner && filter.match(call.CallRecord) })
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].CreatedAt > calls[j].CreatedAt })
+
+	// Step 4: Return the requested page without the cached details
+	response := model.CallListResponse{Total: len(calls), Offset: offset, Limit: limit, Calls: []model.CallRecord{}}
+	for i := offset; i < len(calls) && i < offset+limit; i++ {
+		call := calls[i].CallRecord
+		call.Detail = nil
+		response.Calls = append(response.Calls, call)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// callRecord is a call of the call history with the owner of the token it was
+// sent or fetched with. Calls only reported by Bland callbacks have no owner
+// until a token fetches their details from Bland.
+type callRecord struct {
+	model.CallRecord
+	Owner string `json:"owner,omitempty"`
+}
+
+// recordCall adds a call dispatched through the proxy with a token of owner to the call history.
+func (ctl *Controller) recordCall(owner string, call model.SendCall, response *model.CallResponse) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	record := callRecord{Owner: owner, CallRecord: model.CallRecord{
+		CallID:      response.CallID,
+		PhoneNumber: call.PhoneNumber,
+		PathwayID:   call.PathwayID,
+		Status:      "queued",
+		Metadata:    call.Metadata,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}}
+	if batchID, ok := call.Metadata["batch_id"].(string); ok {
+		record.BatchID = batchID
+	}
+	if err := ctl.calls.Put(record.CallID, record); err != nil {
+		log.Printf("Error recording call %s: %v", record.CallID, err)
+	}
+}
+
+// cacheCallDetail updates the call history from a call detail returned by Bland,
+// creating the record for calls that were not sent through the proxy. The detail
+// itself is only kept once the call has completed, and a call.completed event is
+// published the first time a call is seen completed. owner is the owner of the
+// token the detail was fetched with, or empty for callbacks; it is recorded when
+// the call has no owner yet.
+func (ctl *Controller) cacheCallDetail(detail model.CallDetail, owner string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	newlyCompleted := detail.Completed
+	apply := func(record *callRecord) error {
+		if record.Owner == "" {
+			record.Owner = owner
+		}
+		if record.PathwayID == "" && detail.PathwayID != nil {
+			record.PathwayID = *detail.PathwayID
+		}
+		newlyCompleted = detail.Completed && !record.Completed
+		record.Status = detail.Status
+		record.AnsweredBy = detail.AnsweredBy
+		record.Completed = detail.Completed
+		record.UpdatedAt = now
+		if detail.Completed {
+			snapshot := detail
+			snapshot.Analyses = nil
+			record.Detail = &snapshot
+		}
+		return nil
+	}
+
+	if updated, err := ctl.calls.Update(detail.CallID, apply); err == nil {
+		ctl.publishCompletion(updated.Owner, detail, newlyCompleted)
+		return
+	}
+	record := callRecord{CallRecord: model.CallRecord{CallID: detail.CallID, PhoneNumber: detail.To, CreatedAt: detail.CreatedAt}}
+	if detail.BatchID != nil {
+		record.BatchID = *detail.BatchID
+	}
+	if len(detail.Metadata) > 0 {
+		record.Metadata = make(map[string]interface{}, len(detail.Metadata))
+		for k, v := range detail.Metadata {
+			record.Metadata[k] = v
+		}
+	}
+	if batchID, ok := record.Metadata["batch_id"].(string); ok && record.BatchID == "" {
+		record.BatchID = batchID
+	}
+	apply(&record)
+	if err := ctl.calls.Put(record.CallID, record); err != nil {
+		log.Printf("Error caching call %s: %v", record.CallID, err)
+	}
+	ctl.publishCompletion(record.Owner, detail, newlyCompleted)
+}
+
+func (ctl *Controller) publishCompletion(owner string, detail model.CallDetail, newlyCompleted bool) {
+	if newlyCompleted {
+		ctl.publish(owner, events.CallCompleted, detail)
+	}
+}
+
+// callDetail returns the details of a call, from the call history when the call
+// has completed, belongs to the token and refresh is false, otherwise from Bland,
+// updating the history. Bland decides whether the token may see calls it does
+// not own yet.
+func (ctl *Controller) callDetail(ctx context.Context, bearerToken, callID string, refresh bool) (*model.CallDetail, error) {
+	owner := ownerOf(bearerToken)
+	if !refresh {
+		if callDetail, ok := ctl.cachedCallDetail(callID, owner); ok {
+			return &callDetail, nil
+		}
+	}
+
+	callDetail, err := ctl.client(bearerToken).GetCall(ctx, callID)
+	if err != nil {
+		return nil, err
+	}
+	ctl.cacheCallDetail(*callDetail, owner)
+	return callDetail, nil
+}
+
+// cachedCallDetail returns the cached detail of a completed call of owner.
+func (ctl *Controller) cachedCallDetail(callID, owner string) (model.CallDetail, bool) {
+	record, ok := ctl.calls.Get(callID)
+	if !ok || record.Owner != owner || record.Detail == nil {
+		return model.CallDetail{}, false
+	}
+	return *record.Detail, true
+}
+
+// pruneHistory removes calls unchanged, and batches and analysis jobs finished,
+// for longer than the retention period, now and then every interval, until ctx
+// is cancelled.
+func (ctl *Controller) pruneHistory(ctx context.Context, interval time.Duration) {
+	if ctl.cfg.Retention.Days <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cutoff := time.Now().Add(-time.Duration(ctl.cfg.Retention.Days) * 24 * time.Hour)
+		removed, err := ctl.calls.DeleteWhere(func(call callRecord) bool {
+			updatedAt, err := time.Parse(time.RFC3339, call.UpdatedAt)
+			return err == nil && updatedAt.Before(cutoff)
+		})
+		if err != nil {
+			log.Printf("Error pruning the call history: %v", err)
+		} else if removed > 0 {
+			log.Printf("Removed %d calls older than %d days from the call history", removed, ctl.cfg.Retention.Days)
+		}
+		if removed, err := ctl.batches.prune(cutoff); err != nil {
+			log.Printf("Error pruning batches: %v", err)
+		} else if removed > 0 {
+			log.Printf("Removed %d batches finished more than %d days ago", removed, ctl.cfg.Retention.Days)
+		}
+		if removed, err := ctl.analysisJobs.prune(cutoff); err != nil {
+			log.Printf("Error pruning analysis jobs: %v", err)
+		} else if removed > 0 {
+			log.Printf("Removed %d analysis jobs completed more than %d days ago", removed, ctl.cfg.Retention.Days)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// callFilter selects calls of the local call history. Empty fields match every call.
+type callFilter struct {
+	pathwayID, status, answeredBy, batchID string
+	start, end                             time.Time
+}
+
+func (f callFilter) match(call model.CallRecord) bool {
+	if f.pathwayID != "" && call.PathwayID != f.pathwayID ||
+		f.status != "" && call.Status != f.status ||
+		f.answeredBy != "" && call.AnsweredBy != f.answeredBy ||
+		f.batchID != "" && call.BatchID != f.batchID {
+		return false
+	}
+	createdAt, err := time.Parse(time.RFC3339, call.CreatedAt)
+	if err != nil {
+		return f.start.IsZero() && f.end.IsZero()
+	}
+	return (f.start.IsZero() || !createdAt.Before(f.start)) && (f.end.IsZero() || !createdAt.After(f.end))
+}
+
+// parseDateParam parses an RFC 3339 time or a YYYY-MM-DD day. For a day,
+// endOfDay selects its last instant instead of its first. Empty returns the zero time.
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time or a YYYY-MM-DD date", value)
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// parseIntParam parses an integer query parameter between min and max, or
+// at least min when max is negative. Empty returns fallback.
+func parseIntParam(value string, fallback, min, max int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || (max >= 0 && n > max) {
+		if max >= 0 {
+			return 0, fmt.Errorf("must be an integer between %d and %d", min, max)
+		}
+		return 0, fmt.Errorf("must be an integer of at least %d", min)
+	}
+	return n, nil
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"bland/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenSealerMovesTheKeyOutOfTheDataDir(t *testing.T) {
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	cfg.SecretKeyFile = filepath.Join(t.TempDir(), "secret.key")
+
+	legacy, err := openSealer(config.Config{DataDir: cfg.DataDir, SecretKeyFile: filepath.Join(cfg.DataDir, "secret.key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := legacy.Seal("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealer, err := openSealer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := sealer.Open(sealed); err != nil || got != "token" {
+		t.Errorf("Open with the moved key = %q, %v, want token", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.DataDir, "secret.key")); !os.IsNotExist(err) {
+		t.Errorf("the key is still in the data directory (%v)", err)
+	}
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/phone"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	csvPhoneColumn    = "phone_number"
+	csvMetadataPrefix = "metadata."
+	csvVariablePrefix = "variables."
+)
+
+// ImportCSV godoc
+// @Summary      Import a CSV contact list
+// @Description  Queues a call for every valid row of a CSV file using the given pathway.
+// @Description  The header must contain a phone_number column. Columns named metadata.<key> are sent as call metadata;
+// @Description  every other column, optionally named variables.<key>, is sent as a call variable.
+// @Description  Invalid rows are reported with their line number and are not called. Valid rows are dispatched as a batch,
+// @Description  see GET /calls/batch/{batch_id}. With dry_run=true the file is only validated.
+// @Tags         SendCall
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file        formData  file    true   "CSV contact list"
+// @Param        pathway_id  formData  string  true   "Pathway ID used for every call"
+// @Param        dry_run     formData  bool    false  "Validate the file without dispatching calls"
+// @Success      200  {object}  model.CSVImportResponse  "Dry run result"
+// @Success      202  {object}  model.CSVImportResponse  "Valid rows queued"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid file or no valid rows"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      500  {object}  model.ErrorResponse  "The batch could not be saved"
+// @Security     bearerToken
+// @Router       /calls/batch/csv [post]
+func (ctl *Controller) ImportCSV(c *gin.Context) {
+	// Step 1: Read the form fields
+	pathwayID := c.PostForm("pathway_id")
+	if pathwayID == "" {
+		respondErr(c, validationError([]model.FieldError{{Field: "pathway_id", Code: "required", Message: "pathway_id is required"}}))
+		return
+	}
+	dryRun := c.PostForm("dry_run") == "true"
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondErr(c, validationError([]model.FieldError{{Field: "file", Code: "required", Message: "file is required"}}))
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" && !dryRun {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Parse and validate every row
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("Error opening uploaded file: %v", err)
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, "Failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	calls, response, err := ctl.parseContactsCSV(file, pathwayID)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, err.Error())
+		return
+	}
+	response.DryRun = dryRun
+
+	if dryRun {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	if len(calls) == 0 {
+		fields := make([]model.FieldError, 0, len(response.RowErrors))
+		for _, rowErr := range response.RowErrors {
+			for _, fe := range rowErr.Errors {
+				fe.Field = fmt.Sprintf("row %d: %s", rowErr.Row, fe.Field)
+				fields = append(fields, fe)
+			}
+		}
+		respondErr(c, &model.ErrorResponse{Code: model.ErrCodeInvalidRequest, Message: "The file contains no valid rows", Fields: fields})
+		return
+	}
+
+	// Step 4: Queue the valid rows as a batch
+	batch, err := ctl.startBatch(bearerToken, pathwayID, calls)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+	response.Batch = &batch
+	log.Printf("CSV import queued %d of %d rows as batch %s", response.ValidRows, response.TotalRows, batch.BatchID)
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// parseContactsCSV reads a contact list and returns a call for every valid row.
+// It fails only when the file as a whole is unusable; row problems are reported in the response.
+func (ctl *Controller) parseContactsCSV(r io.Reader, pathwayID string) ([]model.SendCall, *model.CSVImportResponse, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("The file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid CSV header: %v", err)
+	}
+
+	phoneColumn := -1
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		header[i] = name
+		if strings.EqualFold(name, csvPhoneColumn) {
+			phoneColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, nil, fmt.Errorf("The header must contain a %s column", csvPhoneColumn)
+	}
+
+	response := &model.CSVImportResponse{RowErrors: []model.CSVRowError{}}
+	var calls []model.SendCall
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		response.TotalRows++
+		if response.TotalRows > ctl.cfg.Batch.MaxContacts {
+			return nil, nil, fmt.Errorf("The file can contain at most %d rows", ctl.cfg.Batch.MaxContacts)
+		}
+
+		if err != nil {
+			rowErr := model.CSVRowError{Errors: []model.FieldError{{Field: "row", Code: "invalid_format", Message: err.Error()}}}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErr.Row = parseErr.StartLine
+			}
+			response.RowErrors = append(response.RowErrors, rowErr)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		call, rowErr := ctl.contactFromRecord(header, record, phoneColumn, pathwayID)
+		if rowErr != nil {
+			rowErr.Row = line
+			response.RowErrors = append(response.RowErrors, *rowErr)
+			continue
+		}
+		calls = append(calls, call)
+	}
+
+	response.ValidRows = len(calls)
+	return calls, response, nil
+}
+
+// contactFromRecord maps a CSV record to a call, validating its phone number.
+func (ctl *Controller) contactFromRecord(header, record []string, phoneColumn int, pathwayID string) (model.SendCall, *model.CSVRowError) {
+	raw := strings.TrimSpace(record[phoneColumn])
+	if raw == "" {
+		return model.SendCall{}, &model.CSVRowError{Errors: []model.FieldError{{Field: csvPhoneColumn, Code: "required", Message: "phone_number is required"}}}
+	}
+	normalized, err := phone.Normalize(raw, ctl.cfg.Phone.DefaultRegion)
+	if err != nil {
+		var phoneErr *phone.Error
+		errors.As(err, &phoneErr)
+		return model.SendCall{}, &model.CSVRowError{
+			PhoneNumber: raw,
+			Errors:      []model.FieldError{{Field: csvPhoneColumn, Code: phoneErr.Code, Message: phoneErr.Message}},
+		}
+	}
+
+	call := model.SendCall{
+		PhoneNumber: normalized,
+		PathwayID:   pathwayID,
+		RequestData: map[string]interface{}{},
+		Metadata:    map[string]interface{}{},
+	}
+	for i, name := range header {
+		value := strings.TrimSpace(record[i])
+		switch {
+		case i == phoneColumn || name == "" || value == "":
+		case strings.HasPrefix(name, csvMetadataPrefix):
+			call.Metadata[strings.TrimPrefix(name, csvMetadataPrefix)] = value
+		default:
+			call.RequestData[strings.TrimPrefix(name, csvVariablePrefix)] = value
+		}
+	}
+	return call, nil
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"bland/config"
+	"bland/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseContactsCSV(t *testing.T) {
+	cfg := config.Default()
+	cfg.Phone.DefaultRegion = "US"
+	cfg.Batch.MaxContacts = 5
+	ctl := &Controller{cfg: cfg}
+
+	tests := []struct {
+		name      string
+		csv       string
+		wantErr   string
+		calls     []model.SendCall
+		total     int
+		rowErrors []model.CSVRowError
+	}{
+		{
+			name:  "variables and metadata",
+			csv:   "\ufeffname,Phone_Number,variables.plan,metadata.crm_id\nJane,(415) 555-2671,gold,c1\n",
+			total: 1,
+			calls: []model.SendCall{{
+				PhoneNumber: "+14155552671",
+				PathwayID:   "pathway-1",
+				RequestData: map[string]interface{}{"name": "Jane", "plan": "gold"},
+				Metadata:    map[string]interface{}{"crm_id": "c1"},
+			}},
+			rowErrors: []model.CSVRowError{},
+		},
+		{
+			name:  "empty cells are skipped",
+			csv:   "phone_number,name,metadata.crm_id\n+442079460018,,\n",
+			total: 1,
+			calls: []model.SendCall{{
+				PhoneNumber: "+442079460018",
+				PathwayID:   "pathway-1",
+				RequestData: map[string]interface{}{},
+				Metadata:    map[string]interface{}{},
+			}},
+			rowErrors: []model.CSVRowError{},
+		},
+		{
+			name:  "row errors carry their line",
+			csv:   "name,phone_number\nBob,123\nBad\nEmpty,\n",
+			total: 3,
+			rowErrors: []model.CSVRowError{
+				{Row: 2, PhoneNumber: "123", Errors: []model.FieldError{{Field: "phone_number", Code: "short_code", Message: `phone number "123" is a short code`}}},
+				{Row: 3, Errors: []model.FieldError{{Field: "row", Code: "invalid_format", Message: "record on line 3: wrong number of fields"}}},
+				{Row: 4, Errors: []model.FieldError{{Field: "phone_number", Code: "required", Message: "phone_number is required"}}},
+			},
+		},
+		{name: "empty file", csv: "", wantErr: "The file is empty"},
+		{name: "no phone column", csv: "name,phone\nJane,4155552671\n", wantErr: "The header must contain a phone_number column"},
+		{name: "too many rows", csv: "phone_number\n1\n2\n3\n4\n5\n6\n", wantErr: "The file can contain at most 5 rows"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, response, err := ctl.parseContactsCSV(strings.NewReader(tt.csv), "pathway-1")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(calls, tt.calls) {
+				t.Errorf("calls = %+v, want %+v", calls, tt.calls)
+			}
+			if response.TotalRows != tt.total || response.ValidRows != len(tt.calls) {
+				t.Errorf("total, valid = %d, %d; want %d, %d", response.TotalRows, response.ValidRows, tt.total, len(tt.calls))
+			}
+			if !reflect.DeepEqual(response.RowErrors, tt.rowErrors) {
+				t.Errorf("row errors = %+v, want %+v", response.RowErrors, tt.rowErrors)
+			}
+		})
+	}
+}
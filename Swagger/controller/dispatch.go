@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"bland/model"
+	"context"
+)
+
+// dispatchCall validates a call and sends it to Bland. Every outbound call,
+// single or bulk, goes through here so the same checks apply to all of them.
+// Validation failures are returned as *model.ErrorResponse.
+func (ctl *Controller) dispatchCall(ctx context.Context, bearerToken string, call model.SendCall) (*model.CallResponse, error) {
+	// Normalize phone numbers to E.164 before anything is sent upstream
+	if fields := ctl.normalizePhoneFields(&call); len(fields) > 0 {
+		return nil, validationError(fields)
+	}
+
+	// Refuse numbers on the do-not-call list
+	if err := ctl.checkDNC(ownerOf(bearerToken), call); err != nil {
+		return nil, err
+	}
+
+	response, err := ctl.client(bearerToken).SendCall(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember the call so it can be listed and its details cached
+	ctl.recordCall(ownerOf(bearerToken), call, response)
+	return response, nil
+}
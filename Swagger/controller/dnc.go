@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/phone"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	csvReasonColumn = "reason"
+
+	// maxDNCImportRows is the largest number of rows accepted in one do-not-call import.
+	maxDNCImportRows = 10000
+)
+
+// dncEntry is the stored form of a DNCEntry, keyed by its E.164 number. The
+// list is shared by every Authorization token, so a number that opted out
+// stays blocked whichever key is used; Owner only records who added it.
+type dncEntry struct {
+	model.DNCEntry
+	Owner string `json:"owner"`
+}
+
+// dncBlock is the stored form of a DNCBlock. Owner is the token whose call was
+// refused.
+type dncBlock struct {
+	model.DNCBlock
+	Owner string `json:"owner"`
+}
+
+// ListDNC godoc
+// @Summary      List the do-not-call list
+// @Description  Returns every number on the do-not-call list, in E.164. The list is shared by all Authorization tokens.
+// @Tags         DoNotCall
+// @Produce      json
+// @Success      200  {array}   model.DNCEntry  "Do-not-call entries"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /dnc [get]
+func (ctl *Controller) ListDNC(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	entries := []model.DNCEntry{}
+	for _, entry := range ctl.dnc.List(nil) {
+		entries = append(entries, entry.DNCEntry)
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// AddDNC godoc
+// @Summary      Add numbers to the do-not-call list
+// @Description  Normalizes each number to E.164 and adds it to the do-not-call list. Calls to listed numbers,
+// @Description  single, batch or scheduled, are refused with the do_not_call error code before Bland is contacted.
+// @Description  Nothing is added when any number is invalid.
+// @Tags         DoNotCall
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.AddDNCRequest  true  "Numbers and optional reason"
+// @Success      201  {array}   model.DNCEntry  "Numbers added"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /dnc [post]
+func (ctl *Controller) AddDNC(c *gin.Context) {
+	// Step 1: Bind the request body to the AddDNCRequest struct
+	var request model.AddDNCRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	// Step 2: Extract the bearer token, recorded as the one that added the numbers
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	owner := ownerOf(bearerToken)
+
+	// Step 3: Normalize every number, rejecting the request if any is invalid
+	addedAt := time.Now().UTC().Format(time.RFC3339)
+	entries := make(map[string]dncEntry, len(request.PhoneNumbers))
+	var fields []model.FieldError
+	for i, raw := range request.PhoneNumbers {
+		normalized, fieldErr := ctl.normalizeDNCNumber(fmt.Sprintf("phone_numbers[%d]", i), raw)
+		if fieldErr != nil {
+			fields = append(fields, *fieldErr)
+			continue
+		}
+		entries[normalized] = dncEntry{Owner: owner, DNCEntry: model.DNCEntry{PhoneNumber: normalized, Reason: request.Reason, AddedAt: addedAt}}
+	}
+	if len(fields) > 0 {
+		respondErr(c, validationError(fields))
+		return
+	}
+
+	// Step 4: Store the entries
+	if err := ctl.dnc.PutAll(entries); err != nil {
+		log.Printf("Error saving do-not-call list: %v", err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the do-not-call list")
+		return
+	}
+	log.Printf("Added %d numbers to the do-not-call list", len(entries))
+
+	added := make([]model.DNCEntry, 0, len(entries))
+	for _, entry := range entries {
+		added = append(added, entry.DNCEntry)
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i].PhoneNumber < added[j].PhoneNumber })
+	c.JSON(http.StatusCreated, added)
+}
+
+// RemoveDNC godoc
+// @Summary      Remove a number from the do-not-call list
+// @Description  Removes a number from the do-not-call list, whichever Authorization token added it. The number may be
+// @Description  given in any format accepted by POST /dnc.
+// @Tags         DoNotCall
+// @Produce      json
+// @Param        phone_number  path  string  true  "Phone number"
+// @Success      200  {object}  model.DNCEntry  "Number removed"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid phone number"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Number is not on the list"
+// @Security     bearerToken
+// @Router       /dnc/{phone_number} [delete]
+func (ctl *Controller) RemoveDNC(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	normalized, fieldErr := ctl.normalizeDNCNumber("phone_number", c.Param("phone_number"))
+	if fieldErr != nil {
+		respondErr(c, validationError([]model.FieldError{*fieldErr}))
+		return
+	}
+
+	entry, ok := ctl.dnc.Get(normalized)
+	if !ok {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Number is not on the do-not-call list")
+		return
+	}
+	if _, err := ctl.dnc.Delete(normalized); err != nil {
+		log.Printf("Error saving do-not-call list: %v", err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the do-not-call list")
+		return
+	}
+	log.Printf("Removed %s from the do-not-call list for owner %s", normalized, ownerOf(bearerToken))
+
+	c.JSON(http.StatusOK, entry.DNCEntry)
+}
+
+// ImportDNC godoc
+// @Summary      Import numbers to the do-not-call list
+// @Description  Adds every valid row of a CSV file to the do-not-call list. The header must contain a phone_number column
+// @Description  and may contain a reason column; rows without a reason use the reason form field.
+// @Description  Invalid rows are reported with their line number and skipped. A file can have at most 10000 rows.
+// @Tags         DoNotCall
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file    formData  file    true   "CSV file of phone numbers"
+// @Param        reason  formData  string  false  "Reason used for rows without one"
+// @Success      200  {object}  model.DNCImportResponse  "Import result"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid file"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /dnc/import [post]
+func (ctl *Controller) ImportDNC(c *gin.Context) {
+	// Step 1: Extract the bearer token, recorded as the one that added the numbers
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Read the form fields
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondErr(c, validationError([]model.FieldError{{Field: "file", Code: "required", Message: "file is required"}}))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("Error opening uploaded file: %v", err)
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, "Failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	// Step 3: Parse and validate every row
+	entries, response, err := ctl.parseDNCCSV(file, ownerOf(bearerToken), c.PostForm("reason"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, err.Error())
+		return
+	}
+
+	// Step 4: Store the valid rows
+	if err := ctl.dnc.PutAll(entries); err != nil {
+		log.Printf("Error saving do-not-call list: %v", err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to save the do-not-call list")
+		return
+	}
+	response.Added = len(entries)
+	log.Printf("Imported %d of %d rows to the do-not-call list", response.Added, response.TotalRows)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListDNCBlocks godoc
+// @Summary      List blocked calls
+// @Description  Returns the audit records of calls of this Authorization token refused because their number is on the
+// @Description  do-not-call list, newest first
+// @Tags         DoNotCall
+// @Produce      json
+// @Param        phone_number  query  string  false  "Only blocks of this number"
+// @Success      200  {array}   model.DNCBlock  "Blocked calls"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid phone number"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /dnc/blocks [get]
+func (ctl *Controller) ListDNCBlocks(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	owner := ownerOf(bearerToken)
+
+	var number string
+	if raw := c.Query("phone_number"); raw != "" {
+		var fieldErr *model.FieldError
+		if number, fieldErr = ctl.normalizeDNCNumber("phone_number", raw); fieldErr != nil {
+			respondErr(c, validationError([]model.FieldError{*fieldErr}))
+			return
+		}
+	}
+
+	blocks := []model.DNCBlock{}
+	for _, block := range ctl.dncBlocks.List(func(block dncBlock) bool {
+		return block.Owner == owner && (number == "" || block.PhoneNumber == number)
+	}) {
+		blocks = append(blocks, block.DNCBlock)
+	}
+	blockedAt := func(block model.DNCBlock) time.Time {
+		t, _ := time.Parse(time.RFC3339Nano, block.BlockedAt)
+		return t
+	}
+	sort.SliceStable(blocks, func(i, j int) bool { return blockedAt(blocks[i]).After(blockedAt(blocks[j])) })
+	c.JSON(http.StatusOK, blocks)
+}
+
+// checkDNC refuses call when the number it dials or transfers to is on the
+// do-not-call list, recording the block for owner. The numbers of call must
+// already be in E.164.
+func (ctl *Controller) checkDNC(owner string, call model.SendCall) error {
+	field, number := "phone_number", call.PhoneNumber
+	entry, ok := ctl.dnc.Get(number)
+	if !ok && call.TransferPhoneNumber != "" {
+		field, number = "transfer_phone_number", call.TransferPhoneNumber
+		entry, ok = ctl.dnc.Get(number)
+	}
+	if !ok {
+		return nil
+	}
+
+	block := dncBlock{Owner: owner, DNCBlock: model.DNCBlock{
+		ID:          newID("block"),
+		PhoneNumber: number,
+		Field:       field,
+		Reason:      entry.Reason,
+		PathwayID:   call.PathwayID,
+		BlockedAt:   time.Now().UTC().Format(time.RFC3339Nano),
+	}}
+	if batchID, ok := call.Metadata["batch_id"].(string); ok {
+		block.BatchID = batchID
+	}
+	if err := ctl.dncBlocks.Put(block.ID, block); err != nil {
+		log.Printf("Error recording do-not-call block: %v", err)
+	}
+	log.Printf("Call to %s blocked by the do-not-call list (%s %s)", call.PhoneNumber, field, number)
+
+	return &model.ErrorResponse{
+		Code:    model.ErrCodeDoNotCall,
+		Message: "The phone number is on the do-not-call list",
+		Fields:  []model.FieldError{{Field: field, Code: model.ErrCodeDoNotCall, Message: strings.ReplaceAll(field, "_", " ") + " is on the do-not-call list"}},
+	}
+}
+
+// normalizeDNCNumber converts raw to E.164, or returns a FieldError for field when it is invalid.
+func (ctl *Controller) normalizeDNCNumber(field, raw string) (string, *model.FieldError) {
+	normalized, err := phone.Normalize(raw, ctl.cfg.Phone.DefaultRegion)
+	if err != nil {
+		var phoneErr *phone.Error
+		errors.As(err, &phoneErr)
+		return "", &model.FieldError{Field: field, Code: phoneErr.Code, Message: phoneErr.Message}
+	}
+	return normalized, nil
+}
+
+// parseDNCCSV reads a list of numbers and returns an entry added by owner, by
+// number, for every valid row. It fails only when the file as a whole is
+// unusable or too long; row problems are reported in the response.
+func (ctl *Controller) parseDNCCSV(r io.Reader, owner, defaultReason string) (map[string]dncEntry, *model.DNCImportResponse, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("The file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid CSV header: %v", err)
+	}
+
+	phoneColumn, reasonColumn := -1, -1
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		switch {
+		case strings.EqualFold(name, csvPhoneColumn):
+			phoneColumn = i
+		case strings.EqualFold(name, csvReasonColumn):
+			reasonColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, nil, fmt.Errorf("The header must contain a %s column", csvPhoneColumn)
+	}
+
+	addedAt := time.Now().UTC().Format(time.RFC3339)
+	entries := make(map[string]dncEntry)
+	response := &model.DNCImportResponse{RowErrors: []model.CSVRowError{}}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		response.TotalRows++
+		if response.TotalRows > maxDNCImportRows {
+			return nil, nil, fmt.Errorf("The file can contain at most %d rows", maxDNCImportRows)
+		}
+
+		if err != nil {
+			rowErr := model.CSVRowError{Errors: []model.FieldError{{Field: "row", Code: "invalid_format", Message: err.Error()}}}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErr.Row = parseErr.StartLine
+			}
+			response.RowErrors = append(response.RowErrors, rowErr)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		var raw string
+		if phoneColumn < len(record) {
+			raw = strings.TrimSpace(record[phoneColumn])
+		}
+		if raw == "" {
+			response.RowErrors = append(response.RowErrors, model.CSVRowError{
+				Row:    line,
+				Errors: []model.FieldError{{Field: csvPhoneColumn, Code: "required", Message: "phone_number is required"}},
+			})
+			continue
+		}
+		normalized, fieldErr := ctl.normalizeDNCNumber(csvPhoneColumn, raw)
+		if fieldErr != nil {
+			response.RowErrors = append(response.RowErrors, model.CSVRowError{Row: line, PhoneNumber: raw, Errors: []model.FieldError{*fieldErr}})
+			continue
+		}
+
+		entry := model.DNCEntry{PhoneNumber: normalized, Reason: defaultReason, AddedAt: addedAt}
+		if reasonColumn >= 0 && reasonColumn < len(record) && strings.TrimSpace(record[reasonColumn]) != "" {
+			entry.Reason = strings.TrimSpace(record[reasonColumn])
+		}
+		entries[normalized] = dncEntry{Owner: owner, DNCEntry: entry}
+	}
+
+	return entries, response, nil
+}
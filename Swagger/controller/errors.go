@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"bland/blandclient"
+	"bland/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header used to read and echo the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "request_id"
+
+// RequestID is a middleware that assigns every request an ID, reusing the
+// caller's X-Request-ID header when present, and echoes it in the response.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = newID("req")
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// respondError writes an ErrorResponse with the given status, code and message.
+func respondError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, &model.ErrorResponse{Code: code, Message: message, RequestID: c.GetString(requestIDKey)})
+}
+
+// respondErr writes err to the response as an ErrorResponse.
+//
+// *model.ErrorResponse values are written as they are. Errors from the Bland
+// client keep upstream 4xx status codes; upstream 5xx responses, failures
+// reported in a 2xx body and unreachable upstreams become 502, and timeouts 504.
+func respondErr(c *gin.Context, err error) {
+	response := toErrorResponse(err)
+	response.RequestID = c.GetString(requestIDKey)
+	c.JSON(errorStatus(response), response)
+}
+
+// toErrorResponse converts an error returned by a handler's dependencies to an ErrorResponse.
+func toErrorResponse(err error) *model.ErrorResponse {
+	var response *model.ErrorResponse
+	var apiErr *blandclient.APIError
+	switch {
+	case errors.As(err, &response):
+		copied := *response
+		return &copied
+	case errors.As(err, &apiErr):
+		response := &model.ErrorResponse{
+			Code:           model.ErrCodeUpstreamError,
+			Message:        apiErr.Message,
+			UpstreamStatus: apiErr.StatusCode,
+			UpstreamBody:   parseBody(apiErr.Body),
+		}
+		if apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden {
+			response.Code = model.ErrCodeUnauthorized
+		} else if apiErr.StatusCode == http.StatusNotFound {
+			response.Code = model.ErrCodeNotFound
+		} else if apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+			response.Code = model.ErrCodeUpstreamRejected
+		}
+		return response
+	case errors.Is(err, blandclient.ErrMissingToken):
+		return &model.ErrorResponse{Code: model.ErrCodeUnauthorized, Message: "Authorization token is required"}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &model.ErrorResponse{Code: model.ErrCodeUpstreamTimeout, Message: "Bland API did not respond in time"}
+	default:
+		return &model.ErrorResponse{Code: model.ErrCodeUpstreamUnavailable, Message: err.Error()}
+	}
+}
+
+// errorStatus returns the HTTP status the proxy answers with for response.
+func errorStatus(response *model.ErrorResponse) int {
+	switch response.Code {
+	case model.ErrCodeInvalidRequest:
+		if response.UpstreamStatus != 0 {
+			return response.UpstreamStatus
+		}
+		return http.StatusBadRequest
+	case model.ErrCodeUnauthorized:
+		if response.UpstreamStatus != 0 {
+			return response.UpstreamStatus
+		}
+		return http.StatusUnauthorized
+	case model.ErrCodeNotFound:
+		return http.StatusNotFound
+	case model.ErrCodeNotAcceptable:
+		return http.StatusNotAcceptable
+	case model.ErrCodeConflict:
+		return http.StatusConflict
+	case model.ErrCodeDoNotCall:
+		return http.StatusForbidden
+	case model.ErrCodeUpstreamRejected:
+		return response.UpstreamStatus
+	case model.ErrCodeUpstreamTimeout, model.ErrCodeWaitTimeout:
+		return http.StatusGatewayTimeout
+	case model.ErrCodeInternal:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// parseBody returns body decoded as JSON, or as a string when it is not valid JSON.
+func parseBody(body []byte) interface{} {
+	if len(body) == 0 {
+		return nil
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		return parsed
+	}
+	return string(body)
+}
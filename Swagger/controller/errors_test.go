@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"bland/blandclient"
+	"bland/model"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestToErrorResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   string
+		status int
+	}{
+		{"unauthorized", &blandclient.APIError{StatusCode: http.StatusUnauthorized, Message: "Invalid API key"}, model.ErrCodeUnauthorized, http.StatusUnauthorized},
+		{"not found", &blandclient.APIError{StatusCode: http.StatusNotFound, Message: "Pathway not found"}, model.ErrCodeNotFound, http.StatusNotFound},
+		{"rejected", &blandclient.APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Invalid node"}, model.ErrCodeUpstreamRejected, http.StatusUnprocessableEntity},
+		{"server error", &blandclient.APIError{StatusCode: http.StatusInternalServerError, Message: "down"}, model.ErrCodeUpstreamError, http.StatusBadGateway},
+		{"failure in a 2xx body", &blandclient.APIError{StatusCode: http.StatusOK, Message: "pathway creation failed"}, model.ErrCodeUpstreamError, http.StatusBadGateway},
+		{"wrapped API error", fmt.Errorf("update: %w", &blandclient.APIError{StatusCode: http.StatusBadRequest}), model.ErrCodeUpstreamRejected, http.StatusBadRequest},
+		{"missing token", blandclient.ErrMissingToken, model.ErrCodeUnauthorized, http.StatusUnauthorized},
+		{"timeout", fmt.Errorf("get call: %w", context.DeadlineExceeded), model.ErrCodeUpstreamTimeout, http.StatusGatewayTimeout},
+		{"unreachable", errors.New("connection refused"), model.ErrCodeUpstreamUnavailable, http.StatusBadGateway},
+		{"validation error", validationError([]model.FieldError{{Field: "phone_number", Code: "required"}}), model.ErrCodeInvalidRequest, http.StatusBadRequest},
+		{"internal error", &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "disk full"}, model.ErrCodeInternal, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := toErrorResponse(tt.err)
+			if response.Code != tt.code {
+				t.Errorf("code = %s, want %s", response.Code, tt.code)
+			}
+			if status := errorStatus(response); status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestParseBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want interface{}
+	}{
+		{"", nil},
+		{`{"message":"down"}`, map[string]interface{}{"message": "down"}},
+		{"<html>Bad Gateway</html>", "<html>Bad Gateway</html>"},
+	}
+	for _, tt := range tests {
+		if got := parseBody([]byte(tt.body)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseBody(%q) = %#v, want %#v", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"bland/model"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClonePathway godoc
+// @Summary      Clone a pathway
+// @Description  Copies the nodes and edges of a pathway into a new pathway with a new name, created through the
+// @Description  create-and-move flow in folder_id, or in the root folder when it is empty. The source is read with the
+// @Description  Authorization token; the copy is created with X-Target-Authorization when given, so pathways can be
+// @Description  copied to another account. Nodes and edges get new IDs; node_ids maps the IDs of the source to the new
+// @Description  ones. If the copy cannot be moved or updated, it is deleted again.
+// @Tags         Pathway
+// @Accept       json
+// @Produce      json
+// @Param        pathway_id              path    string                     true   "Source pathway ID"
+// @Param        X-Target-Authorization  header  string                     false  "Token of the account to create the copy in, defaults to Authorization"
+// @Param        request                 body    model.ClonePathwayRequest  true   "Name and folder of the copy"
+// @Success      201  {object}  model.ClonePathwayResponse  "Pathway cloned"
+// @Failure      400  {object}  model.ErrorResponse  "Bad Request - invalid input or rejected by Bland"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Pathway or folder not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /pathways/{pathway_id}/clone [post]
+func (ctl *Controller) ClonePathway(c *gin.Context) {
+	pathwayID := c.Param("pathway_id")
+
+	// Step 1: Bind the request JSON
+	var request model.ClonePathwayRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("Error binding JSON for ClonePathwayRequest: %v", err)
+		respondBindError(c, err)
+		return
+	}
+
+	// Step 2: Extract the bearer tokens of the source and target accounts
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	targetToken := c.GetHeader("X-Target-Authorization")
+	if targetToken == "" {
+		targetToken = bearerToken
+	}
+
+	// Step 3: Read the source pathway
+	source, err := ctl.client(bearerToken).GetPathway(c.Request.Context(), pathwayID)
+	if err != nil {
+		log.Printf("Error getting pathway %s: %v", pathwayID, err)
+		respondErr(c, err)
+		return
+	}
+
+	// Step 4: Create the copy and apply the nodes and edges
+	doc := model.PathwayDocument{Name: request.Name, Description: request.Description, Nodes: source.Nodes, Edges: source.Edges}
+	if doc.Description == "" && source.Description != nil {
+		doc.Description = *source.Description
+	}
+	created, err := ctl.createPathwayFrom(c.Request.Context(), ctl.client(targetToken), doc, request.FolderID)
+	if err != nil {
+		respondErr(c, err)
+		return
+	}
+	log.Printf("Pathway %s cloned as %s", pathwayID, created.PathwayID)
+
+	c.JSON(http.StatusCreated, model.ClonePathwayResponse{
+		PathwayID:       created.PathwayID,
+		SourcePathwayID: pathwayID,
+		Name:            created.Name,
+		FolderID:        created.FolderID,
+		CrossAccount:    targetToken != bearerToken,
+		NodeIDs:         created.NodeIDs,
+		Pathway:         created.Pathway,
+	})
+}
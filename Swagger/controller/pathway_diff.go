@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/pathway"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPathwayDiff godoc
+// @Summary      Compare a pathway with another version
+// @Description  Reports the changes that turn another version into the current pathway: the name and description,
+// @Description  and the nodes and edges that were added, removed or modified, matched by ID. Modified nodes and edges
+// @Description  list each changed field, including prompts, conditions and model options, with its value in the
+// @Description  compared version (from) and in the current pathway (to). Compare with a pathway given as the JSON
+// @Description  body, in the format returned by GET /convo_pathway/{pathway_id}, with a stored snapshot of any
+// @Description  pathway taken for the same Authorization token, or with another pathway.
+// @Tags         Pathway
+// @Accept       json
+// @Produce      json
+// @Param        pathway_id        path   string                    true   "Pathway ID"
+// @Param        other_pathway_id  query  string                    false  "Compare with this pathway"
+// @Param        snapshot_id       query  string                    false  "Compare with this snapshot"
+// @Param        request           body   model.GetPathwayResponse  false  "Compare with this pathway version"
+// @Success      200  {object}  model.PathwayDiff  "Changes"
+// @Failure      400  {object}  model.ErrorResponse  "Not exactly one of a body, other_pathway_id and snapshot_id, or invalid JSON"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Pathway or snapshot not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /pathways/{pathway_id}/diff [get]
+func (ctl *Controller) GetPathwayDiff(c *gin.Context) {
+	pathwayID := c.Param("pathway_id")
+	otherPathwayID := c.Query("other_pathway_id")
+	snapshotID := c.Query("snapshot_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	client := ctl.client(bearerToken)
+
+	// Step 2: Read the version to compare with from the body or a snapshot of the token
+	var other model.GetPathwayResponse
+	hasBody := true
+	if err := c.ShouldBindJSON(&other); errors.Is(err, io.EOF) {
+		hasBody = false
+	} else if err != nil {
+		respondBindError(c, err)
+		return
+	}
+	sources := 0
+	for _, given := range []bool{hasBody, otherPathwayID != "", snapshotID != ""} {
+		if given {
+			sources++
+		}
+	}
+	if sources != 1 {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, "Give one of a JSON body, other_pathway_id or snapshot_id to compare with")
+		return
+	}
+	against, againstID := "body", ""
+	if snapshotID != "" {
+		snapshot, ok := ctl.pathwaySnapshot(ownerOf(bearerToken), snapshotID)
+		if !ok || snapshot.Pathway == nil {
+			respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Pathway snapshot not found")
+			return
+		}
+		other, against, againstID = *snapshot.Pathway, "snapshot", snapshotID
+	}
+
+	// Step 3: Fetch the current pathway, and the other pathway when comparing with one
+	current, err := client.GetPathway(c.Request.Context(), pathwayID)
+	if err != nil {
+		log.Printf("Error getting pathway %s: %v", pathwayID, err)
+		respondErr(c, err)
+		return
+	}
+	if otherPathwayID != "" {
+		fetched, err := client.GetPathway(c.Request.Context(), otherPathwayID)
+		if err != nil {
+			log.Printf("Error getting pathway %s: %v", otherPathwayID, err)
+			respondErr(c, err)
+			return
+		}
+		other, against, againstID = *fetched, "pathway", otherPathwayID
+	}
+
+	// Step 4: Compare the versions
+	diff := pathway.Diff(other, *current)
+	diff.PathwayID, diff.Against, diff.AgainstID = pathwayID, against, againstID
+	c.JSON(http.StatusOK, diff)
+}
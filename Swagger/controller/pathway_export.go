@@ -0,0 +1,354 @@
+package controller
+
+import (
+	"bland/blandclient"
+	"bland/events"
+	"bland/model"
+	"bland/pathway"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportPathway godoc
+// @Summary      Export a pathway as a document
+// @Description  Returns a self-contained document with the name, description, nodes, edges and folder path of a
+// @Description  pathway, as JSON or YAML, chosen by the format query parameter or, without it, by the Accept header;
+// @Description  JSON is the default. The document can be kept in version control and imported into any account with
+// @Description  POST /pathways/import.
+// @Tags         Pathway
+// @Produce      json
+// @Produce      application/yaml
+// @Param        pathway_id  path   string  true   "Pathway ID"
+// @Param        format      query  string  false  "Document format, overrides the Accept header"  Enums(json, yaml)
+// @Success      200  {object}  model.PathwayDocument  "Pathway document"
+// @Failure      400  {object}  model.ErrorResponse  "Unknown format"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Pathway not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /pathways/{pathway_id}/export [get]
+func (ctl *Controller) ExportPathway(c *gin.Context) {
+	pathwayID := c.Param("pathway_id")
+
+	// Step 1: Choose the document format
+	format := c.Query("format")
+	switch format {
+	case "":
+		format = pathway.FormatJSON
+		if strings.Contains(c.GetHeader("Accept"), "yaml") {
+			format = pathway.FormatYAML
+		}
+	case pathway.FormatJSON, pathway.FormatYAML:
+	default:
+		respondErr(c, validationError([]model.FieldError{{Field: "format", Code: "oneof", Message: "format must be one of json or yaml"}}))
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	client := ctl.client(bearerToken)
+
+	// Step 3: Fetch the pathway and the path of its folder
+	current, err := client.GetPathway(c.Request.Context(), pathwayID)
+	if err != nil {
+		log.Printf("Error getting pathway %s: %v", pathwayID, err)
+		respondErr(c, err)
+		return
+	}
+	folderPath, err := folderPathOf(c.Request.Context(), client, pathwayID)
+	if err != nil {
+		log.Printf("Error finding the folder of pathway %s: %v", pathwayID, err)
+		respondErr(c, err)
+		return
+	}
+
+	// Step 4: Render the document
+	doc := model.PathwayDocument{
+		Version:    pathway.DocumentVersion,
+		Name:       current.Name,
+		FolderPath: folderPath,
+		Nodes:      current.Nodes,
+		Edges:      current.Edges,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if current.Description != nil {
+		doc.Description = *current.Description
+	}
+	data, err := pathway.Marshal(doc, format)
+	if err != nil {
+		log.Printf("Error rendering pathway %s as %s: %v", pathwayID, format, err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to render the pathway")
+		return
+	}
+	contentType := "application/json; charset=utf-8"
+	if format == pathway.FormatYAML {
+		contentType = "application/yaml; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pathwayID+"."+format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportPathway godoc
+// @Summary      Import a pathway from a document
+// @Description  Creates a pathway from a document produced by GET /pathways/{pathway_id}/export, sent as JSON or, with
+// @Description  a YAML Content-Type, as YAML. The pathway is created through the create-and-move flow in folder_id when
+// @Description  given, otherwise in the folder at the folder path of the document, creating missing folders. Nodes and
+// @Description  edges get new IDs so documents can be imported more than once; node_ids maps the IDs of the document
+// @Description  to the new ones. The nodes and edges are validated first, unless force=true. A failed import removes
+// @Description  the pathway and the folders it created.
+// @Tags         Pathway
+// @Accept       json
+// @Accept       application/yaml
+// @Produce      json
+// @Param        folder_id  query  string                  false  "Folder to create the pathway in, overrides the folder path"
+// @Param        force      query  bool                    false  "Import even if the nodes and edges are invalid"
+// @Param        request    body   model.PathwayDocument  true   "Pathway document"
+// @Success      201  {object}  model.ImportPathwayResponse  "Pathway imported"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid document, failed pathway validation or rejected by Bland"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Folder not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /pathways/import [post]
+func (ctl *Controller) ImportPathway(c *gin.Context) {
+	// Step 1: Parse the document
+	body, err := c.GetRawData()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, "Failed to read the request body")
+		return
+	}
+	format := pathway.FormatJSON
+	if strings.Contains(c.ContentType(), "yaml") {
+		format = pathway.FormatYAML
+	}
+	doc, err := pathway.Unmarshal(body, format)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, fmt.Sprintf("Invalid %s document: %v", format, err))
+		return
+	}
+
+	// Step 2: Validate the document
+	var fields []model.FieldError
+	if strings.TrimSpace(doc.Name) == "" {
+		fields = append(fields, model.FieldError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	if doc.Version < 0 || doc.Version > pathway.DocumentVersion {
+		fields = append(fields, model.FieldError{Field: "version", Code: "unsupported", Message: fmt.Sprintf("document version %d is not supported", doc.Version)})
+	}
+	if len(fields) > 0 {
+		respondErr(c, validationError(fields))
+		return
+	}
+	if c.Query("force") != "true" {
+		if response := pathwayValidationError(model.UpdatePathwayRequest{Nodes: doc.Nodes, Edges: doc.Edges}); response != nil {
+			respondErr(c, response)
+			return
+		}
+	}
+
+	// Step 3: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	client := ctl.client(bearerToken)
+
+	// Step 4: Find or create the folder
+	folderID := c.Query("folder_id")
+	var createdFolders []string
+	if folderID == "" && doc.FolderPath != "" {
+		folderID, createdFolders, err = ensureFolderPath(c.Request.Context(), client, doc.FolderPath)
+		if err != nil {
+			log.Printf("Error creating folder path %q: %v", doc.FolderPath, err)
+			respondErr(c, err)
+			return
+		}
+	}
+
+	// Step 5: Create the pathway and apply the document
+	response, err := ctl.createPathwayFrom(c.Request.Context(), client, doc, folderID)
+	if err != nil {
+		deleteFolders(c.Request.Context(), client, createdFolders)
+		respondErr(c, err)
+		return
+	}
+	log.Printf("Pathway %q imported as %s", doc.Name, response.PathwayID)
+	c.JSON(http.StatusCreated, response)
+}
+
+// createPathwayFrom creates a pathway in folderID through the create-and-move
+// flow and applies the nodes and edges of doc with new IDs. When they cannot
+// be applied, the new pathway is deleted again.
+func (ctl *Controller) createPathwayFrom(ctx context.Context, client *blandclient.Client, doc model.PathwayDocument, folderID string) (*model.ImportPathwayResponse, error) {
+	created, err := ctl.createAndMovePathway(ctx, client, model.CreatePathwayRequest{Name: doc.Name, Description: doc.Description}, folderID)
+	if err != nil {
+		return nil, err
+	}
+	pathwayID := created.CreatePathwayResponse.PathwayID
+
+	nodes, edges, nodeIDs := pathway.RemapIDs(doc.Nodes, doc.Edges, func() string { return newID("node") })
+	update := model.UpdatePathwayRequest{Name: doc.Name, Description: doc.Description, Nodes: nodes, Edges: edges}
+	updated, err := client.UpdatePathway(ctx, pathwayID, update)
+	if err != nil {
+		// Remove the empty pathway so a failed import leaves nothing behind
+		log.Printf("Error applying nodes and edges to new pathway %s, deleting it: %v", pathwayID, err)
+		if _, deleteErr := client.DeletePathway(ctx, pathwayID); deleteErr != nil {
+			log.Printf("Error deleting empty pathway %s, which is left behind: %v", pathwayID, deleteErr)
+		} else {
+			ctl.publish(ownerOf(client.Token), events.PathwayDeleted, model.PathwayEvent{PathwayID: pathwayID})
+		}
+		return nil, err
+	}
+	ctl.publish(ownerOf(client.Token), events.PathwayUpdated, model.PathwayEvent{PathwayID: pathwayID, Name: updated.PathwayData.Name})
+
+	return &model.ImportPathwayResponse{
+		PathwayID: pathwayID,
+		Name:      doc.Name,
+		FolderID:  folderID,
+		NodeIDs:   nodeIDs,
+		Pathway:   updated.PathwayData,
+	}, nil
+}
+
+// folderLookups bounds the folders listed at once by folderPathOf.
+const folderLookups = 4
+
+// folderPathOf returns the path of the folder holding a pathway, with the names
+// of the folders from the root joined by pathway.JoinFolderPath, or "" when the
+// pathway is not in a folder. Bland does not return the folder of a pathway, so
+// the folders are listed, at most folderLookups at a time, until one holds it.
+func folderPathOf(ctx context.Context, client *blandclient.Client, pathwayID string) (string, error) {
+	folders, err := client.ListFolders(ctx)
+	if err != nil {
+		return "", err
+	}
+	byID := make(map[string]model.CreateFolderData, len(folders))
+	for _, folder := range folders {
+		byID[folder.FolderID] = folder
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sem := make(chan struct{}, folderLookups)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		found    *model.CreateFolderData
+		firstErr error
+	)
+	for i := range folders {
+		wg.Add(1)
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			wg.Done()
+			<-sem
+			break
+		}
+		go func(folder *model.CreateFolderData) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			pathways, err := client.ListFolderPathways(ctx, folder.FolderID)
+			mu.Lock()
+			defer mu.Unlock()
+			if found != nil {
+				return
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for _, p := range pathways {
+				if p.PathwayID == pathwayID {
+					found = folder
+					cancel()
+					return
+				}
+			}
+		}(&folders[i])
+	}
+	wg.Wait()
+
+	if found == nil {
+		return "", firstErr
+	}
+	// Walk up to the root; the length check stops on a cycle
+	names := []string{found.Name}
+	for parentID := found.ParentFolderID; parentID != nil && len(names) <= len(folders); {
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		names = append([]string{parent.Name}, names...)
+		parentID = parent.ParentFolderID
+	}
+	return pathway.JoinFolderPath(names), nil
+}
+
+// ensureFolderPath returns the ID of the folder at path, a folder path as read
+// by pathway.SplitFolderPath, creating the folders that do not exist. It also
+// returns the IDs of the folders it created, parents first, so the caller can
+// remove them when what it puts in the folder fails; when ensureFolderPath
+// itself fails, it removes them.
+func ensureFolderPath(ctx context.Context, client *blandclient.Client, path string) (string, []string, error) {
+	folders, err := client.ListFolders(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parentID := ""
+	var createdIDs []string
+	for _, name := range pathway.SplitFolderPath(path) {
+		folderID := ""
+		for _, folder := range folders {
+			parent := ""
+			if folder.ParentFolderID != nil {
+				parent = *folder.ParentFolderID
+			}
+			if folder.Name == name && parent == parentID {
+				folderID = folder.FolderID
+				break
+			}
+		}
+		if folderID == "" {
+			created, err := client.CreateFolder(ctx, model.CreateFolderRequest{Name: name, ParentFolderID: parentID})
+			if err != nil {
+				deleteFolders(ctx, client, createdIDs)
+				return "", nil, err
+			}
+			folderID = created.Data.FolderID
+			createdIDs = append(createdIDs, folderID)
+			log.Printf("Folder %q created as %s", name, folderID)
+		}
+		parentID = folderID
+	}
+	return parentID, createdIDs, nil
+}
+
+// deleteFolders deletes folders created by ensureFolderPath, children first.
+// Folders that cannot be deleted are logged and left behind.
+func deleteFolders(ctx context.Context, client *blandclient.Client, folderIDs []string) {
+	for i := len(folderIDs) - 1; i >= 0; i-- {
+		if err := client.DeleteFolder(ctx, folderIDs[i]); err != nil {
+			log.Printf("Error deleting folder %s, which is left behind: %v", folderIDs[i], err)
+			continue
+		}
+		log.Printf("Folder %s deleted", folderIDs[i])
+	}
+}
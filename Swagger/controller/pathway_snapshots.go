@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"bland/blandclient"
+	"bland/events"
+	"bland/model"
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pathwaySnapshot is the stored form of a PathwaySnapshot, with the owner of
+// the token whose change triggered it.
+type pathwaySnapshot struct {
+	model.PathwaySnapshot
+	Owner string `json:"owner"`
+}
+
+// ListPathwaySnapshots godoc
+// @Summary      List pathway snapshots
+// @Description  Returns the snapshots of a pathway, newest first, without their content. A snapshot of the full
+// @Description  pathway is stored before every update, delete and restore made through the proxy. Only the snapshots
+// @Description  taken for the same Authorization token are listed.
+// @Tags         Pathway
+// @Produce      json
+// @Param        pathway_id  path  string  true  "Pathway ID"
+// @Success      200  {array}  model.PathwaySnapshot  "Snapshots"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /pathways/{pathway_id}/snapshots [get]
+func (ctl *Controller) ListPathwaySnapshots(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	snapshots := ctl.snapshotsOf(ownerOf(bearerToken), c.Param("pathway_id"))
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Version > snapshots[j].Version })
+	for i := range snapshots {
+		snapshots[i].Pathway = nil
+	}
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// GetPathwaySnapshot godoc
+// @Summary      Get a pathway snapshot
+// @Description  Returns a snapshot with the full pathway as it was before the operation that triggered it
+// @Tags         Pathway
+// @Produce      json
+// @Param        pathway_id   path  string  true  "Pathway ID"
+// @Param        snapshot_id  path  string  true  "Snapshot ID"
+// @Success      200  {object}  model.PathwaySnapshot  "Snapshot"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Snapshot not found, or taken for another token"
+// @Security     bearerToken
+// @Router       /pathways/{pathway_id}/snapshots/{snapshot_id} [get]
+func (ctl *Controller) GetPathwaySnapshot(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	snapshot, ok := ctl.pathwaySnapshot(ownerOf(bearerToken), c.Param("snapshot_id"))
+	if !ok || snapshot.PathwayID != c.Param("pathway_id") {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Pathway snapshot not found")
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// RestorePathwaySnapshot godoc
+// @Summary      Restore a pathway to a snapshot
+// @Description  Replaces the name, description, nodes and edges of a pathway with those of a snapshot, after taking a
+// @Description  snapshot of the current state. A pathway that was deleted is re-created through the create-and-move
+// @Description  flow, in folder_id when given, and gets a new ID.
+// @Tags         Pathway
+// @Produce      json
+// @Param        pathway_id   path   string  true   "Pathway ID"
+// @Param        snapshot_id  path   string  true   "Snapshot ID"
+// @Param        folder_id    query  string  false  "Folder to re-create a deleted pathway in"
+// @Success      200  {object}  model.RestorePathwayResponse  "Pathway restored"
+// @Failure      400  {object}  model.ErrorResponse  "Bad Request - rejected by Bland"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Snapshot or folder not found, or snapshot taken for another token"
+// @Failure      500  {object}  model.ErrorResponse  "The current state could not be saved"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /pathways/{pathway_id}/snapshots/{snapshot_id}/restore [post]
+func (ctl *Controller) RestorePathwaySnapshot(c *gin.Context) {
+	pathwayID := c.Param("pathway_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	owner := ownerOf(bearerToken)
+	client := ctl.client(bearerToken)
+
+	// Step 2: Look up the snapshot
+	snapshot, ok := ctl.pathwaySnapshot(owner, c.Param("snapshot_id"))
+	if !ok || snapshot.PathwayID != pathwayID || snapshot.Pathway == nil {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Pathway snapshot not found")
+		return
+	}
+
+	// Step 3: Snapshot the current state, which also tells whether the pathway still exists
+	current, err := ctl.snapshotPathway(c.Request.Context(), client, owner, pathwayID, model.PathwaySnapshotRestore)
+	if err != nil {
+		log.Printf("Error snapshotting pathway %s, not restoring it: %v", pathwayID, err)
+		respondErr(c, err)
+		return
+	}
+
+	// Step 4: Re-create the pathway if it was deleted
+	response := model.RestorePathwayResponse{PathwayID: pathwayID, SnapshotID: snapshot.SnapshotID}
+	if current == nil {
+		create := model.CreatePathwayRequest{Name: snapshot.Pathway.Name}
+		if snapshot.Pathway.Description != nil {
+			create.Description = *snapshot.Pathway.Description
+		}
+		created, err := ctl.createAndMovePathway(c.Request.Context(), client, create, c.Query("folder_id"))
+		if err != nil {
+			respondErr(c, err)
+			return
+		}
+		response.PathwayID, response.Recreated = created.CreatePathwayResponse.PathwayID, true
+		log.Printf("Pathway %s re-created as %s", pathwayID, response.PathwayID)
+	}
+
+	// Step 5: Apply the snapshot
+	updated, err := client.UpdatePathway(c.Request.Context(), response.PathwayID, updateRequestOf(*snapshot.Pathway))
+	if err != nil {
+		log.Printf("Error restoring pathway %s to snapshot %s: %v", response.PathwayID, snapshot.SnapshotID, err)
+		respondErr(c, err)
+		return
+	}
+	log.Printf("Pathway %s restored to snapshot %s", response.PathwayID, snapshot.SnapshotID)
+	ctl.publish(owner, events.PathwayUpdated, model.PathwayEvent{PathwayID: response.PathwayID, Name: updated.PathwayData.Name})
+
+	response.Pathway = updated.PathwayData
+	c.JSON(http.StatusOK, response)
+}
+
+// snapshotPathway stores the current state of a pathway before it is changed,
+// on behalf of owner. It returns nil and no error when the pathway does not
+// exist, so there is nothing to lose.
+func (ctl *Controller) snapshotPathway(ctx context.Context, client *blandclient.Client, owner, pathwayID, reason string) (*model.PathwaySnapshot, error) {
+	current, err := client.GetPathway(ctx, pathwayID)
+	if err != nil {
+		if toErrorResponse(err).Code == model.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ctl.snapshotMu.Lock()
+	defer ctl.snapshotMu.Unlock()
+	snapshot := model.PathwaySnapshot{
+		SnapshotID: newID("snap"),
+		PathwayID:  pathwayID,
+		Version:    1,
+		Reason:     reason,
+		Name:       current.Name,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Pathway:    current,
+	}
+	for _, existing := range ctl.snapshotsOf(owner, pathwayID) {
+		if existing.Version >= snapshot.Version {
+			snapshot.Version = existing.Version + 1
+		}
+	}
+	if err := ctl.pathwaySnapshots.Put(snapshot.SnapshotID, pathwaySnapshot{PathwaySnapshot: snapshot, Owner: owner}); err != nil {
+		log.Printf("Error saving snapshot of pathway %s: %v", pathwayID, err)
+		return nil, &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "Failed to save a snapshot of the pathway"}
+	}
+	log.Printf("Pathway %s saved as snapshot %s (version %d)", pathwayID, snapshot.SnapshotID, snapshot.Version)
+
+	// Keep the newest snapshots of the pathway within the retention limit
+	if limit := ctl.cfg.Retention.SnapshotsPerPathway; limit > 0 {
+		oldest := snapshot.Version - limit
+		if _, err := ctl.pathwaySnapshots.DeleteWhere(func(existing pathwaySnapshot) bool {
+			return existing.PathwayID == pathwayID && existing.Owner == owner && existing.Version <= oldest
+		}); err != nil {
+			log.Printf("Error removing old snapshots of pathway %s: %v", pathwayID, err)
+		}
+	}
+	return &snapshot, nil
+}
+
+// pathwaySnapshot returns the stored snapshot with the given ID if it belongs to owner.
+func (ctl *Controller) pathwaySnapshot(owner, snapshotID string) (model.PathwaySnapshot, bool) {
+	snapshot, ok := ctl.pathwaySnapshots.Get(snapshotID)
+	if !ok || snapshot.Owner != owner {
+		return model.PathwaySnapshot{}, false
+	}
+	return snapshot.PathwaySnapshot, true
+}
+
+// snapshotsOf returns the stored snapshots of a pathway that belong to owner.
+func (ctl *Controller) snapshotsOf(owner, pathwayID string) []model.PathwaySnapshot {
+	snapshots := []model.PathwaySnapshot{}
+	for _, snapshot := range ctl.pathwaySnapshots.List(func(snapshot pathwaySnapshot) bool {
+		return snapshot.PathwayID == pathwayID && snapshot.Owner == owner
+	}) {
+		snapshots = append(snapshots, snapshot.PathwaySnapshot)
+	}
+	return snapshots
+}
+
+// updateRequestOf returns the update request that sets a pathway to p.
+func updateRequestOf(p model.GetPathwayResponse) model.UpdatePathwayRequest {
+	request := model.UpdatePathwayRequest{Name: p.Name, Nodes: p.Nodes, Edges: p.Edges}
+	if p.Description != nil {
+		request.Description = *p.Description
+	}
+	return request
+}
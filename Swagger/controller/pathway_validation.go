@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/pathway"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidatePathway godoc
+// @Summary      Validate pathway nodes and edges
+// @Description  Checks the nodes and edges of an update request without sending anything to Bland: exactly one start
+// @Description  node, unique node and edge IDs, edges between existing nodes, every node reachable from the start node
+// @Description  and a prompt on every Default and End Call node. Nodes without outgoing edges that do not end or
+// @Description  transfer the call are reported as dead_end warnings. Global nodes are exempt from the reachability
+// @Description  and dead-end checks. The pathway is valid when no issue is an error.
+// @Tags         Pathway
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.UpdatePathwayRequest  true  "Pathway nodes and edges"
+// @Success      200  {object}  model.PathwayValidation  "Validation outcome"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid JSON"
+// @Router       /pathways/validate [post]
+func (ctl *Controller) ValidatePathway(c *gin.Context) {
+	var request model.UpdatePathwayRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	issues := pathway.Validate(request.Nodes, request.Edges)
+	if issues == nil {
+		issues = []model.PathwayIssue{}
+	}
+	c.JSON(http.StatusOK, model.PathwayValidation{Valid: pathway.Valid(issues), Issues: issues})
+}
+
+// pathwayValidationError validates the nodes and edges of an update and returns
+// its errors as a validation error, or nil when the graph is valid. Updates that
+// only change the name or description carry no graph and are not checked.
+func pathwayValidationError(request model.UpdatePathwayRequest) *model.ErrorResponse {
+	if len(request.Nodes) == 0 && len(request.Edges) == 0 {
+		return nil
+	}
+	var fields []model.FieldError
+	for _, issue := range pathway.Validate(request.Nodes, request.Edges) {
+		if issue.Severity == model.PathwayIssueError {
+			fields = append(fields, model.FieldError{Field: issue.Field, Code: issue.Code, Message: issue.Message})
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	response := validationError(fields)
+	response.Message = "Pathway validation failed; fix the listed issues or set force=true to update anyway"
+	return response
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"bland/model"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	archiveAttempts = 3                // Downloads attempted before a recording is given up on
+	archiveTimeout  = 10 * time.Minute // Time allowed for one download
+	archiveWorkers  = 2                // Recordings downloaded at once
+	archiveQueue    = 1000             // Recordings waiting to be downloaded before new ones are skipped
+)
+
+// archiveJob is a recording waiting to be archived.
+type archiveJob struct {
+	callID       string
+	recordingURL string
+}
+
+// GetRecording godoc
+// @Summary      Stream a call recording
+// @Description  Streams the audio of a recorded call, so clients can listen without Bland credentials of their own.
+// @Description  Range requests are supported for seeking. The call is looked up as with GET /calls/{call_id}, so only
+// @Description  tokens allowed to see the call get its recording. Recordings kept in the local archive are served from
+// @Description  disk; the others are fetched from the call's recording URL.
+// @Tags         CallDetails
+// @Produce      audio/mpeg
+// @Param        call_id  path    string  true   "Call ID"
+// @Param        Range    header  string  false  "Byte range, e.g. bytes=0-1023"
+// @Success      200  {file}    file  "Recording"
+// @Success      206  {file}    file  "Requested part of the recording"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Call not found, or the call has no recording"
+// @Failure      416  {object}  model.ErrorResponse  "The requested range is not satisfiable"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /calls/{call_id}/recording [get]
+func (ctl *Controller) GetRecording(c *gin.Context) {
+	callID := c.Param("call_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Fetch the call details, from the call history when the call belongs to the token or else from Bland
+	callDetail, err := ctl.callDetail(c.Request.Context(), bearerToken, callID, false)
+	if err != nil {
+		log.Printf("Error getting call %s: %v", callID, err)
+		respondErr(c, err)
+		return
+	}
+
+	// Step 3: Serve the recording from the local archive when it is there
+	if ctl.recordings != nil {
+		if recording, err := ctl.recordings.Open(callID); err == nil {
+			defer recording.File.Close()
+			c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", recording.Name))
+			http.ServeContent(c.Writer, c.Request, recording.Name, recording.ModTime, recording.File)
+			return
+		}
+	}
+	if callDetail.RecordingURL == nil || *callDetail.RecordingURL == "" {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Call has no recording")
+		return
+	}
+
+	// Step 4: Open the recording, forwarding the requested range
+	recording, err := ctl.client(bearerToken).GetRecording(c.Request.Context(), *callDetail.RecordingURL, c.GetHeader("Range"))
+	if err != nil {
+		log.Printf("Error getting recording of call %s: %v", callID, err)
+		respondErr(c, err)
+		return
+	}
+	defer recording.Body.Close()
+
+	// Step 5: Stream the audio to the client
+	header := c.Writer.Header()
+	for name, value := range map[string]string{
+		"Content-Type":  recording.ContentType,
+		"Content-Range": recording.ContentRange,
+		"Accept-Ranges": recording.AcceptRanges,
+		"ETag":          recording.ETag,
+		"Last-Modified": recording.LastModified,
+	} {
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+	if recording.ContentLength >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(recording.ContentLength, 10))
+	}
+	c.Status(recording.StatusCode)
+	if _, err := io.Copy(c.Writer, recording.Body); err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("Error streaming recording of call %s: %v", callID, err)
+	}
+}
+
+// archiveRecording is subscribed to call.completed when archival is enabled.
+// It queues the recording for the archive workers so the publisher is not held
+// up; when the queue is full the recording is not archived.
+func (ctl *Controller) archiveRecording(event model.Event) {
+	callDetail, ok := event.Data.(model.CallDetail)
+	if !ok || callDetail.RecordingURL == nil || *callDetail.RecordingURL == "" {
+		return
+	}
+	select {
+	case ctl.archiveQueue <- archiveJob{callID: callDetail.CallID, recordingURL: *callDetail.RecordingURL}:
+	default:
+		log.Printf("Archive queue full, skipping the recording of call %s", callDetail.CallID)
+	}
+}
+
+// runArchiveWorker downloads queued recordings until ctx is cancelled, making
+// up to archiveAttempts attempts for each.
+func (ctl *Controller) runArchiveWorker(ctx context.Context) {
+	for {
+		var job archiveJob
+		select {
+		case <-ctx.Done():
+			return
+		case job = <-ctl.archiveQueue:
+		}
+
+		for attempt := 1; attempt <= archiveAttempts; attempt++ {
+			err := ctl.downloadRecording(ctx, job.callID, job.recordingURL)
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+			log.Printf("Error archiving recording of call %s (attempt %d of %d): %v", job.callID, attempt, archiveAttempts, err)
+			if attempt < archiveAttempts {
+				select {
+				case <-ctx.Done():
+				case <-time.After(ctl.archiveRetry):
+				}
+			}
+		}
+	}
+}
+
+// downloadRecording copies a recording into the local archive, unless it is already there.
+func (ctl *Controller) downloadRecording(ctx context.Context, callID, recordingURL string) error {
+	if ctl.recordings.Has(callID) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, archiveTimeout)
+	defer cancel()
+	recording, err := ctl.client(ctl.cfg.Recordings.ArchiveToken).GetRecording(ctx, recordingURL, "")
+	if err != nil {
+		return err
+	}
+	defer recording.Body.Close()
+
+	size, err := ctl.recordings.Store(callID, recording.ContentType, recording.Body)
+	if err != nil {
+		return err
+	}
+	log.Printf("Archived recording of call %s (%d bytes)", callID, size)
+	return nil
+}
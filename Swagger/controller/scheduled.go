@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/scheduler"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleCall godoc
+// @Summary      Schedule a call
+// @Description  Stores a call to be sent at scheduled_at, but only inside the contact's calling window in their time zone.
+// @Description  A time outside the window is moved to the next window opening. The time zone and window default to the
+// @Description  configured ones. Scheduled calls are persisted and survive restarts; the call is validated like POST /call.
+// @Tags         ScheduledCalls
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.ScheduleCallRequest  true  "Call, time and optional time zone and window"
+// @Success      201  {object}  model.ScheduledCall  "Call scheduled"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /calls/scheduled [post]
+func (ctl *Controller) ScheduleCall(c *gin.Context) {
+	// Step 1: Bind the request body to the ScheduleCallRequest struct
+	var request model.ScheduleCallRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Reject invalid phone numbers now rather than when the call is due
+	if fields := ctl.normalizePhoneFields(&request.Call); len(fields) > 0 {
+		for i := range fields {
+			fields[i].Field = "call." + fields[i].Field
+		}
+		respondErr(c, validationError(fields))
+		return
+	}
+
+	// Step 4: Store the call for dispatch
+	scheduled, err := ctl.scheduler.Schedule(ownerOf(bearerToken), bearerToken, request)
+	if err != nil {
+		respondErr(c, schedulerError(err))
+		return
+	}
+	log.Printf("Call %s scheduled for %s", scheduled.ID, scheduled.DueAt)
+
+	c.JSON(http.StatusCreated, scheduled)
+}
+
+// ListScheduledCalls godoc
+// @Summary      List scheduled calls
+// @Description  Returns the calls scheduled with this Authorization token, ordered by the time they are due,
+// @Description  optionally filtered by status
+// @Tags         ScheduledCalls
+// @Produce      json
+// @Param        status  query  string  false  "Filter by status"  Enums(scheduled, dispatching, dispatched, failed, cancelled)
+// @Success      200  {array}   model.ScheduledCall  "Scheduled calls"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /calls/scheduled [get]
+func (ctl *Controller) ListScheduledCalls(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+	c.JSON(http.StatusOK, ctl.scheduler.List(ownerOf(bearerToken), c.Query("status")))
+}
+
+// GetScheduledCall godoc
+// @Summary      Get a scheduled call
+// @Description  Returns a scheduled call, including the Bland call ID once it has been dispatched
+// @Tags         ScheduledCalls
+// @Produce      json
+// @Param        scheduled_id  path  string  true  "Scheduled call ID"
+// @Success      200  {object}  model.ScheduledCall  "Scheduled call"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Scheduled call not found"
+// @Security     bearerToken
+// @Router       /calls/scheduled/{scheduled_id} [get]
+func (ctl *Controller) GetScheduledCall(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	scheduled, err := ctl.scheduler.Get(ownerOf(bearerToken), c.Param("scheduled_id"))
+	if err != nil {
+		respondErr(c, schedulerError(err))
+		return
+	}
+	c.JSON(http.StatusOK, scheduled)
+}
+
+// RescheduleCall godoc
+// @Summary      Reschedule a call
+// @Description  Moves a scheduled or failed call to a new time, and optionally a new time zone.
+// @Description  The new time is moved to the next window opening when it falls outside the calling window.
+// @Tags         ScheduledCalls
+// @Accept       json
+// @Produce      json
+// @Param        scheduled_id  path  string                       true  "Scheduled call ID"
+// @Param        request       body  model.RescheduleCallRequest  true  "New time and optional time zone"
+// @Success      200  {object}  model.ScheduledCall  "Call rescheduled"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Scheduled call not found"
+// @Failure      409  {object}  model.ErrorResponse  "The call was already dispatched or cancelled"
+// @Security     bearerToken
+// @Router       /calls/scheduled/{scheduled_id} [patch]
+func (ctl *Controller) RescheduleCall(c *gin.Context) {
+	var request model.RescheduleCallRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	scheduled, err := ctl.scheduler.Reschedule(ownerOf(bearerToken), c.Param("scheduled_id"), request)
+	if err != nil {
+		respondErr(c, schedulerError(err))
+		return
+	}
+	log.Printf("Call %s rescheduled for %s", scheduled.ID, scheduled.DueAt)
+
+	c.JSON(http.StatusOK, scheduled)
+}
+
+// CancelScheduledCall godoc
+// @Summary      Cancel a scheduled call
+// @Description  Cancels a call that has not been dispatched yet. Cancelled calls stay listed with status cancelled.
+// @Tags         ScheduledCalls
+// @Produce      json
+// @Param        scheduled_id  path  string  true  "Scheduled call ID"
+// @Success      200  {object}  model.ScheduledCall  "Call cancelled"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Scheduled call not found"
+// @Failure      409  {object}  model.ErrorResponse  "The call was already dispatched or cancelled"
+// @Security     bearerToken
+// @Router       /calls/scheduled/{scheduled_id} [delete]
+func (ctl *Controller) CancelScheduledCall(c *gin.Context) {
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	scheduled, err := ctl.scheduler.Cancel(ownerOf(bearerToken), c.Param("scheduled_id"))
+	if err != nil {
+		respondErr(c, schedulerError(err))
+		return
+	}
+	log.Printf("Scheduled call %s cancelled", scheduled.ID)
+
+	c.JSON(http.StatusOK, scheduled)
+}
+
+// schedulerError converts an error returned by the scheduler to an ErrorResponse.
+func schedulerError(err error) error {
+	var response *model.ErrorResponse
+	switch {
+	case errors.As(err, &response):
+		return response
+	case errors.Is(err, scheduler.ErrNotFound):
+		return &model.ErrorResponse{Code: model.ErrCodeNotFound, Message: "Scheduled call not found"}
+	case errors.Is(err, scheduler.ErrNotReschedulable), errors.Is(err, scheduler.ErrNotCancellable):
+		return &model.ErrorResponse{Code: model.ErrCodeConflict, Message: err.Error()}
+	default:
+		return &model.ErrorResponse{Code: model.ErrCodeInternal, Message: err.Error()}
+	}
+}
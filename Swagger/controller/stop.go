@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/storage"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StopCall godoc
+// @Summary      Stop an active call
+// @Description  Ends a call that is queued or in progress. The call is marked stopped in the call history until Bland
+// @Description  reports its final status.
+// @Tags         SendCall
+// @Produce      json
+// @Param        call_id  path  string  true  "Call ID"
+// @Success      200  {object}  model.StopCallResponse  "Call stopped"
+// @Failure      400  {object}  model.ErrorResponse  "Rejected by Bland, e.g. because the call has already ended"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Call not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /calls/{call_id}/stop [post]
+func (ctl *Controller) StopCall(c *gin.Context) {
+	callID := c.Param("call_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Ask Bland to end the call and record it as stopped
+	response, err := ctl.stopCall(c.Request.Context(), bearerToken, callID)
+	if err != nil {
+		log.Printf("Error stopping call %s: %v", callID, err)
+		respondErr(c, err)
+		return
+	}
+	log.Printf("Call %s stopped", callID)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// StopBatch godoc
+// @Summary      Stop the active calls of a batch
+// @Description  Cancels the contacts of a batch that have not been called yet and ends every call of the batch that
+// @Description  has not completed according to the call history. Calls being dispatched while the batch stops are
+// @Description  ended as soon as Bland accepts them. The outcome of each call is listed in results. Only batches
+// @Description  started with the same Authorization token can be stopped; other batches are reported as not found.
+// @Tags         SendCall
+// @Produce      json
+// @Param        batch_id  path  string  true  "Batch ID"
+// @Success      200  {object}  model.StopBatchResponse  "Batch stopped"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Batch not found"
+// @Security     bearerToken
+// @Router       /calls/batch/{batch_id}/stop [post]
+func (ctl *Controller) StopBatch(c *gin.Context) {
+	batchID := c.Param("batch_id")
+
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Cancel the contacts that have not been dispatched yet
+	// Batches started with another token are left alone
+	owner := ownerOf(bearerToken)
+	response := model.StopBatchResponse{BatchID: batchID}
+	found := ctl.batches.ownedBy(batchID, owner)
+	if found {
+		ctl.batches.update(batchID, func(b *model.Batch) {
+			if b.Status != model.BatchStatusRunning {
+				return
+			}
+			b.Status = model.BatchStatusStopped
+			for i := range b.Results {
+				if b.Results[i].Status == model.BatchContactPending {
+					b.Results[i].Status = model.BatchContactCancelled
+					b.Cancelled++
+					response.Cancelled++
+				}
+			}
+		})
+	}
+
+	// Step 3: Select the calls of the batch that have not completed
+	calls := ctl.calls.List(func(call callRecord) bool { return call.BatchID == batchID && call.Owner == owner })
+	if !found && len(calls) == 0 {
+		respondError(c, http.StatusNotFound, model.ErrCodeNotFound, "Batch not found")
+		return
+	}
+	var active []string
+	for _, call := range calls {
+		if !call.Completed && call.StoppedAt == "" {
+			active = append(active, call.CallID)
+		}
+	}
+	sort.Strings(active)
+
+	// Step 4: Stop them with at most cfg.Batch.Concurrency requests in flight
+	response.Results = make([]model.StopCallResult, len(active))
+	sem := make(chan struct{}, ctl.cfg.Batch.Concurrency)
+	var wg sync.WaitGroup
+	for i, callID := range active {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, callID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := model.StopCallResult{CallID: callID, Status: model.StopCallStopped}
+			if _, err := ctl.stopCall(c.Request.Context(), bearerToken, callID); err != nil {
+				log.Printf("Error stopping call %s of batch %s: %v", callID, batchID, err)
+				result.Status = model.StopCallFailed
+				result.Error = toErrorResponse(err)
+			}
+			response.Results[i] = result
+		}(i, callID)
+	}
+	wg.Wait()
+
+	for _, result := range response.Results {
+		if result.Status == model.StopCallStopped {
+			response.Stopped++
+		} else {
+			response.Failed++
+		}
+	}
+	log.Printf("Batch %s stopped: %d contacts cancelled, %d calls stopped, %d failed", batchID, response.Cancelled, response.Stopped, response.Failed)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// stopCall asks Bland to end a call and marks it stopped in the call history.
+// Calls that are not in the history are stopped all the same.
+func (ctl *Controller) stopCall(ctx context.Context, bearerToken, callID string) (*model.StopCallResponse, error) {
+	response, err := ctl.client(bearerToken).StopCall(ctx, callID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err = ctl.calls.Update(callID, func(record *callRecord) error {
+		if !record.Completed {
+			record.Status = model.StopCallStopped
+		}
+		record.StoppedAt = now
+		record.UpdatedAt = now
+		return nil
+	})
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("Error recording that call %s was stopped: %v", callID, err)
+	}
+	return response, nil
+}
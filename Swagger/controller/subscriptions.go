@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/webhooks"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhookSubscription godoc
+// @Summary      Subscribe to events
+// @Description  Registers a URL to be notified of events: call.completed, analysis.completed, pathway.created,
+// @Description  pathway.updated and pathway.deleted. Each event is POSTed as JSON with the X-Webhook-Event and
+// @Description  X-Webhook-Delivery headers, and X-Webhook-Signature set to "sha256=" and the hex HMAC-SHA256 of the
+// @Description  body keyed with the secret. Non-2xx answers are retried with exponential backoff; deliveries that
+// @Description  still fail are kept as dead letters. Only events of calls, analyses and pathways handled with the same
+// @Description  Authorization token are delivered, and the subscription is only listed and deleted with that token.
+// @Description  The URL must use https and must not target a private, loopback or link-local address, unless
+// @Description  webhooks.allow_private_targets is set; redirects are not followed.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request  body  model.CreateWebhookSubscriptionRequest  true  "Target URL, event types and secret"
+// @Success      201  {object}  model.WebhookSubscription  "Subscription created"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid input"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /webhooks/subscriptions [post]
+func (ctl *Controller) CreateWebhookSubscription(c *gin.Context) {
+	// Step 1: Bind the request body to the CreateWebhookSubscriptionRequest struct
+	var request model.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Store the subscription
+	subscription, err := ctl.webhooks.Subscribe(ownerOf(bearerToken), request)
+	if err != nil {
+		respondErr(c, webhookError(err))
+		return
+	}
+	log.Printf("Webhook subscription %s created for %s", subscription.ID, subscription.URL)
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListWebhookSubscriptions godoc
+// @Summary      List event subscriptions
+// @Description  Returns the webhook subscriptions created with the Authorization token, oldest first. Secrets are never returned.
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200  {array}  model.WebhookSubscription  "Subscriptions"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /webhooks/subscriptions [get]
+func (ctl *Controller) ListWebhookSubscriptions(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the subscriptions of the token
+	c.JSON(http.StatusOK, ctl.webhooks.Subscriptions(ownerOf(bearerToken)))
+}
+
+// DeleteWebhookSubscription godoc
+// @Summary      Delete an event subscription
+// @Description  Stops notifying a subscription. Its pending deliveries become dead letters.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        subscription_id  path  string  true  "Subscription ID"
+// @Success      200  {object}  model.WebhookSubscription  "Subscription deleted"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Subscription not found"
+// @Security     bearerToken
+// @Router       /webhooks/subscriptions/{subscription_id} [delete]
+func (ctl *Controller) DeleteWebhookSubscription(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Delete the subscription if it belongs to the token
+	subscription, err := ctl.webhooks.Unsubscribe(ownerOf(bearerToken), c.Param("subscription_id"))
+	if err != nil {
+		respondErr(c, webhookError(err))
+		return
+	}
+	log.Printf("Webhook subscription %s deleted", subscription.ID)
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary      List event deliveries
+// @Description  Returns the event deliveries of the subscriptions of the Authorization token, newest first, optionally
+// @Description  filtered by status and subscription. Successful deliveries are kept for a week.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        status           query  string  false  "Filter by status"  Enums(pending, delivered, dead)
+// @Param        subscription_id  query  string  false  "Filter by subscription"
+// @Success      200  {array}  model.WebhookDelivery  "Deliveries"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /webhooks/deliveries [get]
+func (ctl *Controller) ListWebhookDeliveries(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the deliveries of the token
+	c.JSON(http.StatusOK, ctl.webhooks.Deliveries(ownerOf(bearerToken), c.Query("status"), c.Query("subscription_id")))
+}
+
+// ListWebhookDeadLetters godoc
+// @Summary      List dead letters
+// @Description  Returns the deliveries of the subscriptions of the Authorization token that failed every attempt, newest
+// @Description  first. They can be sent again with POST /webhooks/deliveries/{delivery_id}/redeliver.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        subscription_id  query  string  false  "Filter by subscription"
+// @Success      200  {array}  model.WebhookDelivery  "Dead letters"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Security     bearerToken
+// @Router       /webhooks/dead-letters [get]
+func (ctl *Controller) ListWebhookDeadLetters(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Return the dead letters of the token
+	c.JSON(http.StatusOK, ctl.webhooks.Deliveries(ownerOf(bearerToken), model.WebhookDeliveryDead, c.Query("subscription_id")))
+}
+
+// RedeliverWebhook godoc
+// @Summary      Redeliver an event
+// @Description  Queues a dead or delivered delivery again with a fresh set of attempts
+// @Tags         Webhooks
+// @Produce      json
+// @Param        delivery_id  path  string  true  "Delivery ID"
+// @Success      202  {object}  model.WebhookDelivery  "Delivery queued"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required"
+// @Failure      404  {object}  model.ErrorResponse  "Delivery not found"
+// @Failure      409  {object}  model.ErrorResponse  "The delivery is still pending"
+// @Security     bearerToken
+// @Router       /webhooks/deliveries/{delivery_id}/redeliver [post]
+func (ctl *Controller) RedeliverWebhook(c *gin.Context) {
+	// Step 1: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 2: Queue the delivery again if it belongs to the token
+	delivery, err := ctl.webhooks.Redeliver(ownerOf(bearerToken), c.Param("delivery_id"))
+	if err != nil {
+		respondErr(c, webhookError(err))
+		return
+	}
+	log.Printf("Webhook delivery %s queued for redelivery", delivery.ID)
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// webhookError converts an error returned by the webhook dispatcher to an ErrorResponse.
+func webhookError(err error) error {
+	var response *model.ErrorResponse
+	switch {
+	case errors.As(err, &response):
+		return response
+	case errors.Is(err, webhooks.ErrSubscriptionNotFound), errors.Is(err, webhooks.ErrDeliveryNotFound):
+		return &model.ErrorResponse{Code: model.ErrCodeNotFound, Message: err.Error()}
+	case errors.Is(err, webhooks.ErrNotRedeliverable):
+		return &model.ErrorResponse{Code: model.ErrCodeConflict, Message: err.Error()}
+	default:
+		return &model.ErrorResponse{Code: model.ErrCodeInternal, Message: err.Error()}
+	}
+}
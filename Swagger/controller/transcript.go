@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/transcript"
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTranscript godoc
+// @Summary      Export a call transcript
+// @Description  Returns the transcript of a call as plain text, Markdown, SRT or WebVTT subtitles, CSV or JSON Lines.
+// @Description  The format is chosen by the format query parameter or, without it, by the Accept header; plain text
+// @Description  is the default. Subtitle and CSV timings are offsets from the start of the call.
+// @Tags         CallDetails
+// @Produce      plain
+// @Produce      text/markdown
+// @Produce      application/x-subrip
+// @Produce      text/vtt
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        call_id  path   string  true   "Call ID"
+// @Param        format   query  string  false  "Output format, overrides the Accept header"  Enums(text, markdown, srt, vtt, csv, jsonl)
+// @Param        refresh  query  bool    false  "Fetch from Bland even when the call is cached"
+// @Success      200  {string}  string  "Transcript"
+// @Failure      400  {object}  model.ErrorResponse  "Unknown format"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Call not found"
+// @Failure      406  {object}  model.ErrorResponse  "None of the accepted media types is supported"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - Bland did not respond in time"
+// @Security     bearerToken
+// @Router       /calls/{call_id}/transcript [get]
+func (ctl *Controller) GetTranscript(c *gin.Context) {
+	callID := c.Param("call_id")
+
+	// Step 1: Choose the output format
+	var format transcript.Format
+	var ok bool
+	if name := c.Query("format"); name != "" {
+		if format, ok = transcript.ByName(name); !ok {
+			respondErr(c, validationError([]model.FieldError{{
+				Field:   "format",
+				Code:    "oneof",
+				Message: "format must be one of text, markdown, srt, vtt, csv or jsonl",
+			}}))
+			return
+		}
+	} else if format, ok = transcript.Negotiate(c.GetHeader("Accept")); !ok {
+		respondError(c, http.StatusNotAcceptable, model.ErrCodeNotAcceptable,
+			"Supported media types are text/plain, text/markdown, application/x-subrip, text/vtt, text/csv and application/x-ndjson")
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Fetch the call details from the call history or the external API
+	callDetail, err := ctl.callDetail(c.Request.Context(), bearerToken, callID, c.Query("refresh") == "true")
+	if err != nil {
+		log.Printf("Error getting call %s: %v", callID, err)
+		respondErr(c, err)
+		return
+	}
+
+	// Step 4: Render the transcript
+	var buf bytes.Buffer
+	if err := transcript.Render(&buf, *callDetail, format); err != nil {
+		log.Printf("Error rendering transcript of call %s: %v", callID, err)
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Failed to render the transcript")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", callID+"."+format.Extension))
+	c.Data(http.StatusOK, format.ContentType+"; charset=utf-8", buf.Bytes())
+}
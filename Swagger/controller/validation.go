@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"bland/model"
+	"bland/phone"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report validation errors using JSON field names rather than Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// respondBindError writes a 400 for a request body that failed to bind,
+// listing each invalid field when the failure came from validation.
+func respondBindError(c *gin.Context, err error) {
+	fields := fieldErrors(err)
+	if fields == nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, err.Error())
+		return
+	}
+	respondErr(c, validationError(fields))
+}
+
+// validationError returns an invalid_request error listing the invalid fields.
+func validationError(fields []model.FieldError) *model.ErrorResponse {
+	return &model.ErrorResponse{Code: model.ErrCodeInvalidRequest, Message: "Request validation failed", Fields: fields}
+}
+
+// fieldErrors converts validator errors to FieldErrors, or returns nil when err is not a validation error.
+func fieldErrors(err error) []model.FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fields := make([]model.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, model.FieldError{Field: fe.Field(), Code: fe.Tag(), Message: validationMessage(fe)})
+	}
+	return fields
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "required_without":
+		return fmt.Sprintf("%s is required when %s is not set", fe.Field(), jsonName(fe.Param()))
+	case "required_if":
+		return fmt.Sprintf("%s is required when %s", fe.Field(), jsonName(fe.Param()))
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", fe.Field())
+	case "datetime":
+		return fmt.Sprintf("%s must use the format %q", fe.Field(), fe.Param())
+	case "bcp47_language_tag":
+		return fmt.Sprintf("%s must be a language tag such as en-US", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed the %s validation", fe.Field(), fe.Tag())
+	}
+}
+
+// jsonName converts a Go field name used in a validation parameter, such as
+// PathwayID or "VoicemailAction leave_message", to snake case for messages.
+func jsonName(param string) string {
+	name, rest, _ := strings.Cut(param, " ")
+	var b strings.Builder
+	for i, ch := range name {
+		if i > 0 && ch >= 'A' && ch <= 'Z' && !(name[i-1] >= 'A' && name[i-1] <= 'Z') {
+			b.WriteByte('_')
+		}
+		b.WriteRune(ch)
+	}
+	if rest != "" {
+		return strings.ToLower(b.String()) + " is " + rest
+	}
+	return strings.ToLower(b.String())
+}
+
+// normalizePhoneFields converts the phone numbers of a call to E.164 in place
+// and returns a FieldError for each number that is invalid.
+func (ctl *Controller) normalizePhoneFields(call *model.SendCall) []model.FieldError {
+	var fields []model.FieldError
+	for _, f := range []struct {
+		name     string
+		value    *string
+		optional bool
+	}{
+		{"phone_number", &call.PhoneNumber, false},
+		{"transfer_phone_number", &call.TransferPhoneNumber, true},
+		{"from", &call.From, true},
+	} {
+		if f.optional && *f.value == "" {
+			continue
+		}
+		normalized, err := phone.Normalize(*f.value, ctl.cfg.Phone.DefaultRegion)
+		if err != nil {
+			var phoneErr *phone.Error
+			errors.As(err, &phoneErr)
+			fields = append(fields, model.FieldError{Field: f.name, Code: phoneErr.Code, Message: phoneErr.Message})
+			continue
+		}
+		*f.value = normalized
+	}
+	return fields
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"bland/blandclient"
+	"bland/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultWaitTimeout = 120 * time.Second
+	maxWaitTimeout     = 900 * time.Second
+)
+
+// WaitForCall godoc
+// @Summary      Wait for a call to complete
+// @Description  Polls Bland with backoff until the call is completed and returns its final details.
+// @Description  Gives up after timeout seconds with the wait_timeout error code. Stops polling when the client disconnects.
+// @Tags         CallDetails
+// @Produce      json
+// @Param        call_id  path   string  true   "Call ID"
+// @Param        timeout  query  int     false  "Seconds to wait, at most 900"  default(120)
+// @Success      200  {object}  model.CallDetail  "Completed call"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid timeout"
+// @Failure      401  {object}  model.ErrorResponse  "Unauthorized - Bearer token required or rejected by Bland"
+// @Failure      404  {object}  model.ErrorResponse  "Call not found"
+// @Failure      502  {object}  model.ErrorResponse  "Bad Gateway - Bland failed or could not be reached"
+// @Failure      504  {object}  model.ErrorResponse  "Gateway Timeout - the call did not complete in time"
+// @Security     bearerToken
+// @Router       /calls/{call_id}/wait [get]
+func (ctl *Controller) WaitForCall(c *gin.Context) {
+	// Step 1: Read the wait timeout
+	timeout, ok := waitTimeout(c)
+	if !ok {
+		return
+	}
+
+	// Step 2: Extract the bearer token from the request header
+	bearerToken := c.GetHeader("Authorization")
+	if bearerToken == "" {
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Authorization token is required")
+		return
+	}
+
+	// Step 3: Poll until the call completes
+	ctl.respondWhenCompleted(c, bearerToken, c.Param("call_id"), timeout)
+}
+
+// waitTimeout reads the timeout query parameter, writing a 400 when it is invalid.
+func waitTimeout(c *gin.Context) (time.Duration, bool) {
+	seconds, err := parseIntParam(c.Query("timeout"), int(defaultWaitTimeout/time.Second), 1, int(maxWaitTimeout/time.Second))
+	if err != nil {
+		respondErr(c, validationError([]model.FieldError{{Field: "timeout", Code: "range", Message: err.Error()}}))
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// respondWhenCompleted waits for a call to complete and writes its details, or the error.
+// Nothing is written when the client went away.
+func (ctl *Controller) respondWhenCompleted(c *gin.Context, bearerToken, callID string, timeout time.Duration) {
+	if callDetail, ok := ctl.cachedCallDetail(callID, ownerOf(bearerToken)); ok {
+		c.JSON(http.StatusOK, callDetail)
+		return
+	}
+
+	callDetail, err := ctl.client(bearerToken).WaitForCall(c.Request.Context(), callID, blandclient.WaitOptions{
+		Timeout:         timeout,
+		InitialInterval: ctl.waitInterval,
+	})
+	if callDetail != nil {
+		ctl.cacheCallDetail(*callDetail, ownerOf(bearerToken))
+	}
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, callDetail)
+	case errors.Is(err, context.Canceled):
+		log.Printf("Stopped waiting for call %s: client went away", callID)
+	case errors.Is(err, blandclient.ErrWaitTimeout):
+		respondErr(c, &model.ErrorResponse{
+			Code:    model.ErrCodeWaitTimeout,
+			Message: fmt.Sprintf("Call %s did not complete within %s", callID, timeout),
+		})
+	default:
+		log.Printf("Error waiting for call %s: %v", callID, err)
+		respondErr(c, err)
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"bland/events"
+	"bland/model"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BlandSignatureHeader carries the hex HMAC-SHA256 of a Bland callback body.
+const BlandSignatureHeader = "X-Webhook-Signature"
+
+// ReceiveBlandWebhook godoc
+// @Summary      Receive a Bland call callback
+// @Description  Endpoint to use as the webhook of calls. Bland posts the call details here when a call ends.
+// @Description  The X-Webhook-Signature header must hold the hex HMAC-SHA256 of the raw body, keyed with the configured
+// @Description  webhook secret. The call is saved in the local call history and a call.completed event is published.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        X-Webhook-Signature  header  string            true  "Hex HMAC-SHA256 of the body"
+// @Param        request              body    model.CallDetail  true  "Call details posted by Bland"
+// @Success      200  {object}  model.WebhookReceipt  "Callback received"
+// @Failure      400  {object}  model.ErrorResponse  "Invalid payload"
+// @Failure      401  {object}  model.ErrorResponse  "Missing or invalid signature"
+// @Failure      500  {object}  model.ErrorResponse  "Webhook secret is not configured"
+// @Router       /webhooks/bland [post]
+func (ctl *Controller) ReceiveBlandWebhook(c *gin.Context) {
+	// Step 1: Read the raw body, which the signature is computed over
+	body, err := c.GetRawData()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, "Failed to read request body")
+		return
+	}
+
+	// Step 2: Verify the signature
+	if ctl.cfg.Webhooks.BlandSecret == "" {
+		log.Printf("Refusing Bland callback: no webhook secret is configured")
+		respondError(c, http.StatusInternalServerError, model.ErrCodeInternal, "Webhook secret is not configured")
+		return
+	}
+	if !validSignature(ctl.cfg.Webhooks.BlandSecret, body, c.GetHeader(BlandSignatureHeader)) {
+		log.Printf("Refusing Bland callback with an invalid signature")
+		respondError(c, http.StatusUnauthorized, model.ErrCodeUnauthorized, "Invalid webhook signature")
+		return
+	}
+
+	// Step 3: Parse the call details
+	var callDetail model.CallDetail
+	if err := json.Unmarshal(body, &callDetail); err != nil {
+		respondError(c, http.StatusBadRequest, model.ErrCodeInvalidRequest, "Invalid call details: "+err.Error())
+		return
+	}
+	if callDetail.CallID == "" {
+		respondErr(c, validationError([]model.FieldError{{Field: "call_id", Code: "required", Message: "call_id is required"}}))
+		return
+	}
+	log.Printf("Received Bland callback for call %s (%s)", callDetail.CallID, callDetail.Status)
+
+	// Step 4: Save the call and notify subscribers
+	ctl.cacheCallDetail(callDetail, "")
+
+	c.JSON(http.StatusOK, model.WebhookReceipt{Status: "received", CallID: callDetail.CallID})
+}
+
+// Subscribe registers handler for internal events of the given types, or all events when none are given.
+func (ctl *Controller) Subscribe(handler events.Handler, types ...string) {
+	ctl.events.Subscribe(handler, types...)
+}
+
+// publish sends an event with data to the internal subscribers.
+func (ctl *Controller) publish(owner, eventType string, data interface{}) {
+	ctl.events.Publish(model.Event{
+		ID:        newID("evt"),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data:      data,
+		Owner:     owner,
+	})
+}
+
+// validSignature reports whether signature is the hex HMAC-SHA256 of body keyed with secret.
+// A "sha256=" prefix is accepted.
+func validSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
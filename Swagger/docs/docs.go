@@ -24,14 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/call": {
+        "/analysis/jobs": {
             "post": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Send call using Pathways by providing a phone number and pathway ID",
+                "description": "Runs the same analysis on a list of call IDs and/or the completed calls of the local history that\nmatch a filter, among the calls sent or fetched with the Authorization token. The job is only returned\nto that token. The analysis uses a stored schema, or a goal and questions. Calls are analyzed in the\nbackground with bounded concurrency and at most requests_per_minute requests per minute; query\nGET /analysis/jobs/{job_id} for progress and the total credits used.",
                 "consumes": [
                     "application/json"
                 ],
@@ -39,29 +39,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SendCall"
+                    "AnalyzeCall"
                 ],
-                "summary": "Send call using Pathways",
+                "summary": "Analyze many calls",
                 "parameters": [
                     {
-                        "description": "Request body",
+                        "description": "Calls and analysis",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SendCall"
+                            "$ref": "#/definitions/model.AnalysisJobRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Success",
+                    "202": {
+                        "description": "Job accepted",
                         "schema": {
-                            "$ref": "#/definitions/model.CallResponse"
+                            "$ref": "#/definitions/model.AnalysisJob"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid input, unknown schema or no matching calls",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -73,7 +73,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "The job could not be saved",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -81,53 +81,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/call/{call_id}/analyze": {
-            "post": {
+        "/analysis/jobs/{job_id}": {
+            "get": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Analyze a call by providing the call ID, goal, and an array of questions",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the progress of an analysis job, the credits used so far and the outcome for each call",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "AnalyzeCall"
                 ],
-                "summary": "Analyze a call with AI",
+                "summary": "Get an analysis job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Call ID",
-                        "name": "call_id",
+                        "description": "Job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.AnalyzeCallRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success",
-                        "schema": {
-                            "$ref": "#/definitions/model.AnalyzeCallResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "Job",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/model.AnalysisJob"
                         }
                     },
                     "401": {
@@ -136,8 +118,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Job not found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -145,48 +127,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/calls/{call_id}": {
+        "/analysis/jobs/{job_id}/results": {
             "get": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Retrieve detailed information, metadata, and transcripts for a call",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the outcome for each call of a job as JSON or CSV, chosen by the format query parameter or,\nwithout it, by the Accept header; JSON is the default. The CSV has one row per call and one column per\nquestion: the question name with typed answers for schema jobs, the question text otherwise.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "CallDetails"
+                    "AnalyzeCall"
                 ],
-                "summary": "Get call details",
+                "summary": "Export the results of an analysis job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Call ID",
-                        "name": "call_id",
+                        "description": "Job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Output format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Call details retrieved successfully",
+                        "description": "Results",
                         "schema": {
-                            "$ref": "#/definitions/model.CallDetail"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AnalysisJobCallResult"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Unknown format",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -194,63 +193,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/convo_pathway/{pathway_id}": {
+        "/analysis/rules": {
             "get": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Returns detailed information about a specific pathway, including nodes and edges.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the automatic analysis rules of the Authorization token, oldest first, optionally only those of a pathway",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Pathway"
+                    "AnalyzeCall"
                 ],
-                "summary": "Get pathway information",
+                "summary": "List automatic analysis rules",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The pathway ID",
+                        "description": "Only rules of this pathway",
                         "name": "pathway_id",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pathway information retrieved successfully",
-                        "schema": {
-                            "$ref": "#/definitions/model.GetPathwayResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input",
+                        "description": "Rules",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AutoAnalysisRule"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/delete/convo_pathway/{pathway_id}": {
-            "delete": {
+            },
+            "post": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Deletes a specific conversational pathway by its ID",
+                "description": "Analyzes every call of a pathway sent or fetched with the Authorization token of this request as soon\nas the proxy sees it complete, by polling or by webhook. The analysis uses a stored schema, or a goal\nand questions. Its outcome is stored with the call and returned in the analyses field of\nGET /calls/{call_id}. The Authorization token of this request is kept, encrypted, to run the analyses,\nand the rule is only returned to and changed with that token.\nCalls of the pathway sent in the last 24 hours are checked for completion every minute, so calls that\nare neither fetched nor reported by a Bland callback are analyzed too.",
                 "consumes": [
                     "application/json"
                 ],
@@ -258,33 +248,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pathway"
+                    "AnalyzeCall"
                 ],
-                "summary": "Delete a conversational pathway",
+                "summary": "Create an automatic analysis rule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Pathway ID to delete",
-                        "name": "pathway_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AutoAnalysisRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Pathway deleted successfully",
+                    "201": {
+                        "description": "Rule created",
                         "schema": {
-                            "$ref": "#/definitions/model.DeletePathwayResponse"
+                            "$ref": "#/definitions/model.AutoAnalysisRule"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input or unknown schema",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -292,65 +284,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/folders": {
-            "post": {
+        "/analysis/rules/{rule_id}": {
+            "get": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Creates a new folder for the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
+                    "AnalyzeCall"
                 ],
-                "summary": "Create a new folder",
+                "summary": "Get an automatic analysis rule",
                 "parameters": [
                     {
-                        "description": "Request body for creating folder",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.CreateFolderRequest"
-                        }
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Folder created successfully",
+                        "description": "Rule",
                         "schema": {
-                            "$ref": "#/definitions/model.CreateFolderResponse"
+                            "$ref": "#/definitions/model.AutoAnalysisRule"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Rule not found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/pathway/update/{pathway_id}": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Updates a conversational pathway’s fields including name, description, nodes, and edges",
+                "description": "Replaces a rule of the Authorization token. The token of this request replaces the one kept to run the\nanalyses.",
                 "consumes": [
                     "application/json"
                 ],
@@ -358,93 +342,92 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Pathway"
+                    "AnalyzeCall"
                 ],
-                "summary": "Update conversational pathway",
+                "summary": "Replace an automatic analysis rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Pathway ID to update",
-                        "name": "pathway_id",
+                        "description": "Rule ID",
+                        "name": "rule_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Request body for updating the pathway",
+                        "description": "Rule",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdatePathwayRequest"
+                            "$ref": "#/definitions/model.AutoAnalysisRuleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pathway updated successfully",
+                        "description": "Rule updated",
                         "schema": {
-                            "$ref": "#/definitions/model.PathwayData"
+                            "$ref": "#/definitions/model.AutoAnalysisRule"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input or unknown schema",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/pathways/chat/create": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Creates a chat instance for a pathway, which can be used to send and receive messages.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Stops analyzing the calls of the rule's pathway. Analyses already stored with calls are kept.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "AnalyzeCall"
                 ],
-                "summary": "Create a pathway chat",
+                "summary": "Delete an automatic analysis rule",
                 "parameters": [
                     {
-                        "description": "Request body for creating chat",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.CreateChatRequest"
-                        }
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Chat instance created successfully",
+                        "description": "Rule deleted",
                         "schema": {
-                            "$ref": "#/definitions/model.CreateChatResponse"
+                            "$ref": "#/definitions/model.AutoAnalysisRule"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Rule not found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -452,14 +435,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/pathways/chat/{chat_id}/send": {
+        "/analysis/schemas": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the analysis schemas of the Authorization token, sorted by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AnalyzeCall"
+                ],
+                "summary": "List analysis schemas",
+                "responses": {
+                    "200": {
+                        "description": "Schemas",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AnalysisSchema"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Sends a message to a specific pathway chat and receives a response",
+                "description": "Stores a named, reusable set of analysis questions. Each question has a name, which keys its answer\nin the result, and an expected type: boolean, number, enum (with options) or string. The schema belongs\nto the Authorization token and can only be read, changed and used with it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -467,32 +482,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "AnalyzeCall"
                 ],
-                "summary": "Send a message to a pathway chat",
+                "summary": "Create an analysis schema",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Chat ID to send message to",
-                        "name": "chat_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Request body for sending a message",
+                        "description": "Schema",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SendMessageRequest"
+                            "$ref": "#/definitions/model.AnalysisSchemaRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Message sent successfully",
+                    "201": {
+                        "description": "Schema created",
                         "schema": {
-                            "$ref": "#/definitions/model.SendMessageResponse"
+                            "$ref": "#/definitions/model.AnalysisSchema"
                         }
                     },
                     "400": {
@@ -506,56 +514,94 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/pathways/create-and-move": {
-            "post": {
+        "/analysis/schemas/{schema_id}": {
+            "get": {
                 "security": [
                     {
                         "bearerToken": []
                     }
                 ],
-                "description": "Creates a new conversational pathway and moves it to a folder",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Pathway"
+                    "AnalyzeCall"
                 ],
-                "summary": "Create and move pathway",
+                "summary": "Get an analysis schema",
                 "parameters": [
-                    {
-                        "description": "Request body for creating pathway",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.CreatePathwayRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "Folder ID to move the pathway into",
-                        "name": "folder_id",
-                        "in": "query"
+                        "description": "Schema ID",
+                        "name": "schema_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Combined response of creating and moving pathway",
+                        "description": "Schema",
                         "schema": {
-                            "$ref": "#/definitions/model.CombinedResponse"
+                            "$ref": "#/definitions/model.AnalysisSchema"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Replaces the name, description, goal and questions of a schema. Results of earlier analyses are unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AnalyzeCall"
+                ],
+                "summary": "Replace an analysis schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schema_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalysisSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema updated",
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalysisSchema"
                         }
                     },
                     "400": {
@@ -564,521 +610,4890 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AnalyzeCall"
+                ],
+                "summary": "Delete an analysis schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schema_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Schema deleted",
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalysisSchema"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The schema is used by an automatic analysis rule",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.AnalyzeCallRequest": {
-            "type": "object",
-            "required": [
-                "goal",
-                "questions"
+        },
+        "/call": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Send call using Pathways by providing a phone number and pathway ID, or a task prompt instead of a pathway.\nVoice, recording, voicemail, scheduling, webhook, metadata and request data options are forwarded to Bland.\nPhone numbers are normalized to E.164; numbers without a country code use the configured default region.\nInvalid, premium-rate and short-code numbers are rejected with field-level errors before Bland is contacted.\nNumbers on the do-not-call list are refused with the do_not_call error code.\nWith wait=true the response is held until the call completes and its model.CallDetail is returned\ninstead, as with GET /calls/{call_id}/wait.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SendCall"
+                ],
+                "summary": "Send call using Pathways",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SendCall"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for the call to complete and return its details",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 120,
+                        "description": "Seconds to wait, at most 900",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.CallResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - the number is on the do-not-call list",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time, or the call did not complete in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/call/{call_id}/analyze": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Analyze a call by providing the call ID, goal, and an array of questions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AnalyzeCall"
+                ],
+                "summary": "Analyze a call with AI",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalyzeCallRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalyzeCallResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/call/{call_id}/analyze/{schema_id}": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Asks Bland the questions of a stored analysis schema about a call and returns the answers keyed by\nquestion name, converted to each question's type. Answers that do not match their type are null and\nlisted in issues, and valid is false.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AnalyzeCall"
+                ],
+                "summary": "Analyze a call with a schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema ID",
+                        "name": "schema_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Typed answers",
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalysisResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call or schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns calls from the local history, newest first. Every call sent through the proxy is recorded,\nand call details fetched from Bland update it. Dates are RFC 3339 times or YYYY-MM-DD days; an\nend_date day is inclusive. Only calls sent or fetched with the same Authorization token are listed.\nCached call details are omitted; use GET /calls/{call_id} for them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CallDetails"
+                ],
+                "summary": "List call history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only calls of this pathway",
+                        "name": "pathway_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls with this Bland status, e.g. queued or completed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls answered by, e.g. human or voicemail",
+                        "name": "answered_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls of this batch",
+                        "name": "batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls created at or after",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls created at or before",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of calls to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Calls",
+                        "schema": {
+                            "$ref": "#/definitions/model.CallListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/batch": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Sends a call to every contact using the same pathway, with bounded concurrency.\nEach contact goes through the same validation as POST /call. Dispatch continues in the background;\nquery GET /calls/batch/{batch_id} for per-contact results.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SendCall"
+                ],
+                "summary": "Dispatch a batch of calls",
+                "parameters": [
+                    {
+                        "description": "Contacts and pathway ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BatchCallRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Batch accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Batch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "The batch could not be saved",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/batch/csv": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Queues a call for every valid row of a CSV file using the given pathway.\nThe header must contain a phone_number column. Columns named metadata.\u003ckey\u003e are sent as call metadata;\nevery other column, optionally named variables.\u003ckey\u003e, is sent as a call variable.\nInvalid rows are reported with their line number and are not called. Valid rows are dispatched as a batch,\nsee GET /calls/batch/{batch_id}. With dry_run=true the file is only validated.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SendCall"
+                ],
+                "summary": "Import a CSV contact list",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV contact list",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pathway ID used for every call",
+                        "name": "pathway_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate the file without dispatching calls",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run result",
+                        "schema": {
+                            "$ref": "#/definitions/model.CSVImportResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Valid rows queued",
+                        "schema": {
+                            "$ref": "#/definitions/model.CSVImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file or no valid rows",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "The batch could not be saved",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/batch/{batch_id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the progress of a batch and the dispatch outcome for each contact. Only the Authorization\ntoken that started the batch can read it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SendCall"
+                ],
+                "summary": "Get batch results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch results",
+                        "schema": {
+                            "$ref": "#/definitions/model.Batch"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/batch/{batch_id}/stop": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Cancels the contacts of a batch that have not been called yet and ends every call of the batch that\nhas not completed according to the call history. Calls being dispatched while the batch stops are\nended as soon as Bland accepts them. The outcome of each call is listed in results. Only batches\nstarted with the same Authorization token can be stopped; other batches are reported as not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SendCall"
+                ],
+                "summary": "Stop the active calls of a batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch stopped",
+                        "schema": {
+                            "$ref": "#/definitions/model.StopBatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/scheduled": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the calls scheduled with this Authorization token, ordered by the time they are due,\noptionally filtered by status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ScheduledCalls"
+                ],
+                "summary": "List scheduled calls",
+                "parameters": [
+                    {
+                        "enum": [
+                            "scheduled",
+                            "dispatching",
+                            "dispatched",
+                            "failed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduled calls",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ScheduledCall"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Stores a call to be sent at scheduled_at, but only inside the contact's calling window in their time zone.\nA time outside the window is moved to the next window opening. The time zone and window default to the\nconfigured ones. Scheduled calls are persisted and survive restarts; the call is validated like POST /call.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ScheduledCalls"
+                ],
+                "summary": "Schedule a call",
+                "parameters": [
+                    {
+                        "description": "Call, time and optional time zone and window",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleCallRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Call scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledCall"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/scheduled/{scheduled_id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns a scheduled call, including the Bland call ID once it has been dispatched",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ScheduledCalls"
+                ],
+                "summary": "Get a scheduled call",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled call ID",
+                        "name": "scheduled_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduled call",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledCall"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Cancels a call that has not been dispatched yet. Cancelled calls stay listed with status cancelled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ScheduledCalls"
+                ],
+                "summary": "Cancel a scheduled call",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled call ID",
+                        "name": "scheduled_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Call cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledCall"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The call was already dispatched or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Moves a scheduled or failed call to a new time, and optionally a new time zone.\nThe new time is moved to the next window opening when it falls outside the calling window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ScheduledCalls"
+                ],
+                "summary": "Reschedule a call",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled call ID",
+                        "name": "scheduled_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New time and optional time zone",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RescheduleCallRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Call rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledCall"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The call was already dispatched or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/{call_id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Retrieve detailed information, metadata, and transcripts for a call.\nCompleted calls are cached in the local call history and served without contacting Bland unless refresh=true;\nthe cache is only used for the token the call was sent or fetched with.\nOutcomes of automatic analysis rules are returned in analyses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CallDetails"
+                ],
+                "summary": "Get call details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch from Bland even when the call is cached",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Call details retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.CallDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/{call_id}/recording": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Streams the audio of a recorded call, so clients can listen without Bland credentials of their own.\nRange requests are supported for seeking. The call is looked up as with GET /calls/{call_id}, so only\ntokens allowed to see the call get its recording. Recordings kept in the local archive are served from\ndisk; the others are fetched from the call's recording URL.",
+                "produces": [
+                    "audio/mpeg"
+                ],
+                "tags": [
+                    "CallDetails"
+                ],
+                "summary": "Stream a call recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recording",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Requested part of the recording",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found, or the call has no recording",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "416": {
+                        "description": "The requested range is not satisfiable",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/{call_id}/stop": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Ends a call that is queued or in progress. The call is marked stopped in the call history until Bland\nreports its final status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SendCall"
+                ],
+                "summary": "Stop an active call",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Call stopped",
+                        "schema": {
+                            "$ref": "#/definitions/model.StopCallResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Rejected by Bland, e.g. because the call has already ended",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/{call_id}/transcript": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the transcript of a call as plain text, Markdown, SRT or WebVTT subtitles, CSV or JSON Lines.\nThe format is chosen by the format query parameter or, without it, by the Accept header; plain text\nis the default. Subtitle and CSV timings are offsets from the start of the call.",
+                "produces": [
+                    "text/plain",
+                    "text/markdown",
+                    "application/x-subrip",
+                    "text/vtt",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "CallDetails"
+                ],
+                "summary": "Export a call transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "text",
+                            "markdown",
+                            "srt",
+                            "vtt",
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "Output format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch from Bland even when the call is cached",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transcript",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown format",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/calls/{call_id}/wait": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Polls Bland with backoff until the call is completed and returns its final details.\nGives up after timeout seconds with the wait_timeout error code. Stops polling when the client disconnects.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "CallDetails"
+                ],
+                "summary": "Wait for a call to complete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 120,
+                        "description": "Seconds to wait, at most 900",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Completed call",
+                        "schema": {
+                            "$ref": "#/definitions/model.CallDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid timeout",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - the call did not complete in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/convo_pathway/{pathway_id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns detailed information about a specific pathway, including nodes and edges.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Get pathway information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pathway information retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetPathwayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/delete/convo_pathway/{pathway_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Deletes a specific conversational pathway by its ID. A snapshot of the pathway is stored first, so it\ncan be re-created with POST /pathways/{pathway_id}/snapshots/{snapshot_id}/restore.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Delete a conversational pathway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID to delete",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pathway deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeletePathwayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dnc": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns every number on the do-not-call list, in E.164. The list is shared by all Authorization tokens.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DoNotCall"
+                ],
+                "summary": "List the do-not-call list",
+                "responses": {
+                    "200": {
+                        "description": "Do-not-call entries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DNCEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Normalizes each number to E.164 and adds it to the do-not-call list. Calls to listed numbers,\nsingle, batch or scheduled, are refused with the do_not_call error code before Bland is contacted.\nNothing is added when any number is invalid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DoNotCall"
+                ],
+                "summary": "Add numbers to the do-not-call list",
+                "parameters": [
+                    {
+                        "description": "Numbers and optional reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddDNCRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Numbers added",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DNCEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dnc/blocks": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the audit records of calls of this Authorization token refused because their number is on the\ndo-not-call list, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DoNotCall"
+                ],
+                "summary": "List blocked calls",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only blocks of this number",
+                        "name": "phone_number",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blocked calls",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DNCBlock"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone number",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dnc/import": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Adds every valid row of a CSV file to the do-not-call list. The header must contain a phone_number column\nand may contain a reason column; rows without a reason use the reason form field.\nInvalid rows are reported with their line number and skipped. A file can have at most 10000 rows.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DoNotCall"
+                ],
+                "summary": "Import numbers to the do-not-call list",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file of phone numbers",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason used for rows without one",
+                        "name": "reason",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import result",
+                        "schema": {
+                            "$ref": "#/definitions/model.DNCImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dnc/{phone_number}": {
+            "delete": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Removes a number from the do-not-call list, whichever Authorization token added it. The number may be\ngiven in any format accepted by POST /dnc.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DoNotCall"
+                ],
+                "summary": "Remove a number from the do-not-call list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number removed",
+                        "schema": {
+                            "$ref": "#/definitions/model.DNCEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone number",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Number is not on the list",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/folders": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Creates a new folder for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Folder"
+                ],
+                "summary": "Create a new folder",
+                "parameters": [
+                    {
+                        "description": "Request body for creating folder",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Folder created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateFolderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathway/update/{pathway_id}": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Updates a conversational pathway’s fields including name, description, nodes, and edges.\nThe nodes and edges are checked as by POST /pathways/validate first, and an invalid graph is\nrejected with one field error per issue unless force=true. Warnings never block an update.\nA snapshot of the pathway is stored before it is updated; see GET /pathways/{pathway_id}/snapshots.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Update conversational pathway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID to update",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body for updating the pathway",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdatePathwayRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Send the update even if the nodes and edges are invalid",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pathway updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.PathwayData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/chat/create": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Creates a chat instance for a pathway, which can be used to send and receive messages.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Create a pathway chat",
+                "parameters": [
+                    {
+                        "description": "Request body for creating chat",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chat instance created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/chat/{chat_id}/send": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Sends a message to a specific pathway chat and receives a response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Send a message to a pathway chat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat ID to send message to",
+                        "name": "chat_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body for sending a message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SendMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/model.SendMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Chat not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/create-and-move": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Creates a new conversational pathway and moves it to a folder. If the move fails, the pathway is deleted again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Create and move pathway",
+                "parameters": [
+                    {
+                        "description": "Request body for creating pathway",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreatePathwayRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Folder ID to move the pathway into",
+                        "name": "folder_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Combined response of creating and moving pathway",
+                        "schema": {
+                            "$ref": "#/definitions/model.CombinedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/import": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Creates a pathway from a document produced by GET /pathways/{pathway_id}/export, sent as JSON or, with\na YAML Content-Type, as YAML. The pathway is created through the create-and-move flow in folder_id when\ngiven, otherwise in the folder at the folder path of the document, creating missing folders. Nodes and\nedges get new IDs so documents can be imported more than once; node_ids maps the IDs of the document\nto the new ones. The nodes and edges are validated first, unless force=true. A failed import removes\nthe pathway and the folders it created.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Import a pathway from a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Folder to create the pathway in, overrides the folder path",
+                        "name": "folder_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Import even if the nodes and edges are invalid",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Pathway document",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PathwayDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Pathway imported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportPathwayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid document, failed pathway validation or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/validate": {
+            "post": {
+                "description": "Checks the nodes and edges of an update request without sending anything to Bland: exactly one start\nnode, unique node and edge IDs, edges between existing nodes, every node reachable from the start node\nand a prompt on every Default and End Call node. Nodes without outgoing edges that do not end or\ntransfer the call are reported as dead_end warnings. Global nodes are exempt from the reachability\nand dead-end checks. The pathway is valid when no issue is an error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Validate pathway nodes and edges",
+                "parameters": [
+                    {
+                        "description": "Pathway nodes and edges",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdatePathwayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation outcome",
+                        "schema": {
+                            "$ref": "#/definitions/model.PathwayValidation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/{pathway_id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Copies the nodes and edges of a pathway into a new pathway with a new name, created through the\ncreate-and-move flow in folder_id, or in the root folder when it is empty. The source is read with the\nAuthorization token; the copy is created with X-Target-Authorization when given, so pathways can be\ncopied to another account. Nodes and edges get new IDs; node_ids maps the IDs of the source to the new\nones. If the copy cannot be moved or updated, it is deleted again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Clone a pathway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the account to create the copy in, defaults to Authorization",
+                        "name": "X-Target-Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Name and folder of the copy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ClonePathwayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Pathway cloned",
+                        "schema": {
+                            "$ref": "#/definitions/model.ClonePathwayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - invalid input or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway or folder not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/{pathway_id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Reports the changes that turn another version into the current pathway: the name and description,\nand the nodes and edges that were added, removed or modified, matched by ID. Modified nodes and edges\nlist each changed field, including prompts, conditions and model options, with its value in the\ncompared version (from) and in the current pathway (to). Compare with a pathway given as the JSON\nbody, in the format returned by GET /convo_pathway/{pathway_id}, with a stored snapshot of any\npathway taken for the same Authorization token, or with another pathway.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Compare a pathway with another version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Compare with this pathway",
+                        "name": "other_pathway_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Compare with this snapshot",
+                        "name": "snapshot_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Compare with this pathway version",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetPathwayResponse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes",
+                        "schema": {
+                            "$ref": "#/definitions/model.PathwayDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Not exactly one of a body, other_pathway_id and snapshot_id, or invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway or snapshot not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/{pathway_id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns a self-contained document with the name, description, nodes, edges and folder path of a\npathway, as JSON or YAML, chosen by the format query parameter or, without it, by the Accept header;\nJSON is the default. The document can be kept in version control and imported into any account with\nPOST /pathways/import.",
+                "produces": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Export a pathway as a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "yaml"
+                        ],
+                        "type": "string",
+                        "description": "Document format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pathway document",
+                        "schema": {
+                            "$ref": "#/definitions/model.PathwayDocument"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown format",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pathway not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/{pathway_id}/snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the snapshots of a pathway, newest first, without their content. A snapshot of the full\npathway is stored before every update, delete and restore made through the proxy. Only the snapshots\ntaken for the same Authorization token are listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "List pathway snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshots",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PathwaySnapshot"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/{pathway_id}/snapshots/{snapshot_id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns a snapshot with the full pathway as it was before the operation that triggered it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Get a pathway snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Snapshot",
+                        "schema": {
+                            "$ref": "#/definitions/model.PathwaySnapshot"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Snapshot not found, or taken for another token",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pathways/{pathway_id}/snapshots/{snapshot_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Replaces the name, description, nodes and edges of a pathway with those of a snapshot, after taking a\nsnapshot of the current state. A pathway that was deleted is re-created through the create-and-move\nflow, in folder_id when given, and gets a new ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pathway"
+                ],
+                "summary": "Restore a pathway to a snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pathway ID",
+                        "name": "pathway_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Folder to re-create a deleted pathway in",
+                        "name": "folder_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pathway restored",
+                        "schema": {
+                            "$ref": "#/definitions/model.RestorePathwayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required or rejected by Bland",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Snapshot or folder not found, or snapshot taken for another token",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "The current state could not be saved",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway - Bland failed or could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout - Bland did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/bland": {
+            "post": {
+                "description": "Endpoint to use as the webhook of calls. Bland posts the call details here when a call ends.\nThe X-Webhook-Signature header must hold the hex HMAC-SHA256 of the raw body, keyed with the configured\nwebhook secret. The call is saved in the local call history and a call.completed event is published.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Receive a Bland call callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the body",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Call details posted by Bland",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CallDetail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Callback received",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookReceipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Webhook secret is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the deliveries of the subscriptions of the Authorization token that failed every attempt, newest\nfirst. They can be sent again with POST /webhooks/deliveries/{delivery_id}/redeliver.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List dead letters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by subscription",
+                        "name": "subscription_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dead letters",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the event deliveries of the subscriptions of the Authorization token, newest first, optionally\nfiltered by status and subscription. Successful deliveries are kept for a week.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List event deliveries",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by subscription",
+                        "name": "subscription_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Queues a dead or delivered delivery again with a fresh set of attempts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Redeliver an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Delivery queued",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDelivery"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The delivery is still pending",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Returns the webhook subscriptions created with the Authorization token, oldest first. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List event subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "Subscriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Registers a URL to be notified of events: call.completed, analysis.completed, pathway.created,\npathway.updated and pathway.deleted. Each event is POSTed as JSON with the X-Webhook-Event and\nX-Webhook-Delivery headers, and X-Webhook-Signature set to \"sha256=\" and the hex HMAC-SHA256 of the\nbody keyed with the secret. Non-2xx answers are retried with exponential backoff; deliveries that\nstill fail are kept as dead letters. Only events of calls, analyses and pathways handled with the same\nAuthorization token are delivered, and the subscription is only listed and deleted with that token.\nThe URL must use https and must not target a private, loopback or link-local address, unless\nwebhooks.allow_private_targets is set; redirects are not followed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Subscribe to events",
+                "parameters": [
+                    {
+                        "description": "Target URL, event types and secret",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateWebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription created",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/subscriptions/{subscription_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "bearerToken": []
+                    }
+                ],
+                "description": "Stops notifying a subscription. Its pending deliveries become dead letters.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete an event subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription deleted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookSubscription"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Bearer token required",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AddDNCRequest": {
+            "type": "object",
+            "required": [
+                "phone_numbers"
+            ],
+            "properties": {
+                "phone_numbers": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "+14155552671",
+                        "(212) 555-0123"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Asked not to be called again"
+                }
+            }
+        },
+        "model.AnalysisIssue": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "description": "Answer returned by Bland",
+                    "type": "string",
+                    "example": "about five thousand"
+                },
+                "code": {
+                    "description": "missing, invalid_boolean, invalid_number or invalid_option",
+                    "type": "string",
+                    "example": "invalid_number"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "answer is not a number"
+                },
+                "question": {
+                    "description": "Question name",
+                    "type": "string",
+                    "example": "budget"
+                }
+            }
+        },
+        "model.AnalysisJob": {
+            "type": "object",
+            "properties": {
+                "analyzed": {
+                    "type": "integer",
+                    "example": 97
+                },
+                "completed_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:40:56Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "credits_used": {
+                    "description": "Sum over the analyzed calls",
+                    "type": "number",
+                    "example": 29.1
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "job_id": {
+                    "type": "string",
+                    "example": "ajob_5f2b6c0e9a8d4e1f"
+                },
+                "questions": {
+                    "description": "Names of the schema questions, in order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "interested",
+                        "budget"
+                    ]
+                },
+                "request": {
+                    "description": "Goal and questions sent for every call",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AnalyzeCallRequest"
+                        }
+                    ]
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AnalysisJobCallResult"
+                    }
+                },
+                "schema_id": {
+                    "type": "string",
+                    "example": "schema_5f2b6c0e9a8d4e1f"
+                },
+                "status": {
+                    "description": "running or completed",
+                    "type": "string",
+                    "example": "running"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "model.AnalysisJobCallResult": {
+            "type": "object",
+            "properties": {
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.ErrorResponse"
+                },
+                "response": {
+                    "$ref": "#/definitions/model.AnalyzeCallResponse"
+                },
+                "result": {
+                    "description": "Typed answers, set when the job uses a schema",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AnalysisResult"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "pending, analyzed or failed",
+                    "type": "string",
+                    "example": "analyzed"
+                }
+            }
+        },
+        "model.AnalysisJobFilter": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string",
+                    "example": "batch_5f2b6c0e9a8d4e1f"
+                },
+                "end_date": {
+                    "description": "RFC 3339 time or YYYY-MM-DD day, inclusive",
+                    "type": "string",
+                    "example": "2024-09-30"
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "3c90c3cc-0d44-4b50-8888-8dd25736052a"
+                },
+                "start_date": {
+                    "description": "RFC 3339 time or YYYY-MM-DD day",
+                    "type": "string",
+                    "example": "2024-09-01"
+                }
+            }
+        },
+        "model.AnalysisJobRequest": {
+            "type": "object",
+            "properties": {
+                "call_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                    ]
+                },
+                "filter": {
+                    "$ref": "#/definitions/model.AnalysisJobFilter"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Qualify the lead for the sales team"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "requests_per_minute": {
+                    "description": "Lowers the configured rate limit for this job",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 30
+                },
+                "schema_id": {
+                    "type": "string",
+                    "example": "schema_5f2b6c0e9a8d4e1f"
+                }
+            }
+        },
+        "model.AnalysisQuestion": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Guidance sent to Bland with the question",
+                    "type": "string",
+                    "example": "True when a follow-up was agreed"
+                },
+                "name": {
+                    "description": "Key of the answer in the result; letters, digits and underscores",
+                    "type": "string",
+                    "example": "interested"
+                },
+                "options": {
+                    "description": "Allowed answers of an enum question",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "morning",
+                        "afternoon",
+                        "evening"
+                    ]
+                },
+                "question": {
+                    "description": "Question asked about the call",
+                    "type": "string",
+                    "example": "Is the customer interested in the offer?"
+                },
+                "type": {
+                    "description": "boolean, number, enum or string",
+                    "type": "string",
+                    "example": "boolean"
+                }
+            }
+        },
+        "model.AnalysisResult": {
+            "type": "object",
+            "properties": {
+                "analyzed_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:40:00Z"
+                },
+                "answers": {
+                    "description": "Typed answer of each question keyed by name; null when the answer is invalid",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "interested": "true"
+                    }
+                },
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "credits_used": {
+                    "type": "number",
+                    "example": 0.3
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AnalysisIssue"
+                    }
+                },
+                "raw_answers": {
+                    "description": "Answers as returned by Bland",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "interested": "Yes"
+                    }
+                },
+                "schema_id": {
+                    "type": "string",
+                    "example": "schema_5f2b6c0e9a8d4e1f"
+                },
+                "valid": {
+                    "description": "False when any answer is missing or invalid",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "model.AnalysisSchema": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Questions asked after every sales call"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Qualify the lead for the sales team"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "schema_5f2b6c0e9a8d4e1f"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lead qualification"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AnalysisQuestion"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56Z"
+                }
+            }
+        },
+        "model.AnalysisSchemaRequest": {
+            "type": "object",
+            "required": [
+                "goal",
+                "name",
+                "questions"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Questions asked after every sales call"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Qualify the lead for the sales team"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lead qualification"
+                },
+                "questions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.AnalysisQuestion"
+                    }
+                }
+            }
+        },
+        "model.AnalyzeCallRequest": {
+            "type": "object",
+            "required": [
+                "goal",
+                "questions"
+            ],
+            "properties": {
+                "goal": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "model.AnalyzeCallResponse": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credits_used": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AutoAnalysisRule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56.789Z"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Qualify the lead for the sales team"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "rule_5f2b6c0e9a8d4e1f"
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "3c90c3cc-0d44-4b50-8888-8dd25736052a"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schema_id": {
+                    "type": "string",
+                    "example": "schema_5f2b6c0e9a8d4e1f"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56.789Z"
+                }
+            }
+        },
+        "model.AutoAnalysisRuleRequest": {
+            "type": "object",
+            "required": [
+                "pathway_id"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "Defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "goal": {
+                    "description": "Goal used without a schema",
+                    "type": "string",
+                    "example": "Qualify the lead for the sales team"
+                },
+                "pathway_id": {
+                    "description": "Calls of this pathway are analyzed when they complete",
+                    "type": "string",
+                    "example": "3c90c3cc-0d44-4b50-8888-8dd25736052a"
+                },
+                "questions": {
+                    "description": "Questions used without a schema",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schema_id": {
+                    "description": "Analysis schema to use",
+                    "type": "string",
+                    "example": "schema_5f2b6c0e9a8d4e1f"
+                }
+            }
+        },
+        "model.Batch": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string",
+                    "example": "batch_5f2b6c0e9a8d4e1f"
+                },
+                "cancelled": {
+                    "description": "Contacts not called because the batch was stopped",
+                    "type": "integer",
+                    "example": 0
+                },
+                "completed_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:35:56Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "dispatched": {
+                    "type": "integer",
+                    "example": 97
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BatchContactResult"
+                    }
+                },
+                "status": {
+                    "description": "running, completed or stopped",
+                    "type": "string",
+                    "example": "running"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "model.BatchCallRequest": {
+            "type": "object",
+            "required": [
+                "contacts",
+                "pathway_id"
+            ],
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.BatchContact"
+                    }
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
+                }
+            }
+        },
+        "model.BatchContact": {
+            "type": "object",
+            "required": [
+                "phone_number"
+            ],
+            "properties": {
+                "metadata": {
+                    "description": "Returned with the call",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "crm_id": "12345"
+                    }
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+14155552671"
+                },
+                "variables": {
+                    "description": "Sent to Bland as request_data",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "name": "Jane"
+                    }
+                }
+            }
+        },
+        "model.BatchContactResult": {
+            "type": "object",
+            "properties": {
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.ErrorResponse"
+                },
+                "index": {
+                    "description": "Position of the contact in the request",
+                    "type": "integer",
+                    "example": 0
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+14155552671"
+                },
+                "status": {
+                    "description": "pending, dispatching, dispatched, failed or cancelled",
+                    "type": "string",
+                    "example": "dispatched"
+                }
+            }
+        },
+        "model.CSVImportResponse": {
+            "type": "object",
+            "properties": {
+                "batch": {
+                    "description": "Batch the valid rows were queued in, absent for a dry run",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Batch"
+                        }
+                    ]
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "row_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CSVRowError"
+                    }
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "valid_rows": {
+                    "type": "integer",
+                    "example": 98
+                }
+            }
+        },
+        "model.CSVRowError": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldError"
+                    }
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "555-0100"
+                },
+                "row": {
+                    "description": "Line number in the file, the header being line 1",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.CallAnalysis": {
+            "type": "object",
+            "properties": {
+                "analyzed_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:40:00Z"
+                },
+                "error": {
+                    "description": "Set when the analysis failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    ]
+                },
+                "request": {
+                    "$ref": "#/definitions/model.AnalyzeCallRequest"
+                },
+                "response": {
+                    "description": "Set when the analysis succeeded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AnalyzeCallResponse"
+                        }
+                    ]
+                },
+                "result": {
+                    "description": "Typed answers, set when the rule uses a schema",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AnalysisResult"
+                        }
+                    ]
+                },
+                "rule_id": {
+                    "type": "string",
+                    "example": "rule_5f2b6c0e9a8d4e1f"
+                }
+            }
+        },
+        "model.CallDetail": {
+            "type": "object",
+            "properties": {
+                "analyses": {
+                    "description": "Automatic post-call analyses stored by the proxy; not returned by Bland",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CallAnalysis"
+                    }
+                },
+                "analysis": {
+                    "type": "string",
+                    "example": "Detailed analysis of the call..."
+                },
+                "analysis_schema": {
+                    "type": "string",
+                    "example": "analysis-schema-123"
+                },
+                "answered_by": {
+                    "type": "string",
+                    "example": "human"
+                },
+                "batch_id": {
+                    "type": "string",
+                    "example": "batch123"
+                },
+                "call_ended_by": {
+                    "type": "string",
+                    "example": "customer"
+                },
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "call_length": {
+                    "type": "number",
+                    "example": 120.5
+                },
+                "completed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "concatenated_transcript": {
+                    "type": "string",
+                    "example": "Full transcript of the call..."
+                },
+                "corrected_duration": {
+                    "type": "string",
+                    "example": "2m 30s"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:36:56Z"
+                },
+                "endpoint_url": {
+                    "type": "string",
+                    "example": "https://api.bland.ai/callback"
+                },
+                "error_message": {
+                    "type": "string",
+                    "example": "Call failed due to timeout"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "+14155552672"
+                },
+                "inbound": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "local_dialing": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "max_duration": {
+                    "type": "number",
+                    "example": 3600
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        " \"region\"": "\"US\"}",
+                        "{\"source\"": "\"ads\""
+                    }
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "3c90c3cc-0d44-4b50-8888-8dd25736052a"
+                },
+                "pathway_logs": {
+                    "type": "string",
+                    "example": "Log details here..."
+                },
+                "price": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "queue_status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "record": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "recording_url": {
+                    "type": "string",
+                    "example": "https://api.bland.ai/recordings/12345"
+                },
+                "request_data": {
+                    "$ref": "#/definitions/model.RequestData"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "summary": {
+                    "type": "string",
+                    "example": "Call summary notes..."
+                },
+                "to": {
+                    "type": "string",
+                    "example": "+14155552671"
+                },
+                "transcripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Transcript"
+                    }
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        " \"campaign\"": "\"welcome\"}",
+                        "{\"user_id\"": "\"12345\""
+                    }
+                }
+            }
+        },
+        "model.CallListResponse": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CallRecord"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "description": "Number of calls matching the filters",
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "model.CallRecord": {
+            "type": "object",
+            "properties": {
+                "analyses": {
+                    "description": "Automatic post-call analyses, one per rule",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CallAnalysis"
+                    }
+                },
+                "answered_by": {
+                    "type": "string",
+                    "example": "human"
+                },
+                "batch_id": {
+                    "type": "string",
+                    "example": "batch_5f2b6c0e9a8d4e1f"
+                },
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "completed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "description": "When the call was dispatched",
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "detail": {
+                    "description": "Snapshot cached once the call completed; omitted from listings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CallDetail"
+                        }
+                    ]
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "3c90c3cc-0d44-4b50-8888-8dd25736052a"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+14155552671"
+                },
+                "status": {
+                    "description": "Last status reported by Bland, queued until then, stopped once stopped through the proxy",
+                    "type": "string",
+                    "example": "completed"
+                },
+                "stopped_at": {
+                    "description": "When the call was stopped through the proxy",
+                    "type": "string",
+                    "example": "2024-09-26T12:35:30Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:36:56Z"
+                }
+            }
+        },
+        "model.CallResponse": {
+            "type": "object",
+            "properties": {
+                "call_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CallingWindow": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Allowed weekdays: mon, tue, wed, thu, fri, sat, sun",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri",
+                        "sat"
+                    ]
+                },
+                "end": {
+                    "description": "Local closing time, HH:MM, exclusive",
+                    "type": "string",
+                    "example": "20:00"
+                },
+                "start": {
+                    "description": "Local opening time, HH:MM",
+                    "type": "string",
+                    "example": "09:00"
+                }
+            }
+        },
+        "model.ChatHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "The message content",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "\"user\" or \"assistant\"",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ClonePathwayRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "description": "Defaults to the description of the source pathway",
+                    "type": "string",
+                    "example": "Starting point for the billing line"
+                },
+                "folder_id": {
+                    "description": "Folder of the copy; the root folder when empty",
+                    "type": "string",
+                    "example": "folder_123"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Customer Support (copy)"
+                }
+            }
+        },
+        "model.ClonePathwayResponse": {
+            "type": "object",
+            "properties": {
+                "cross_account": {
+                    "description": "Whether the copy was made with a different Authorization token",
+                    "type": "boolean",
+                    "example": false
+                },
+                "folder_id": {
+                    "type": "string",
+                    "example": "folder_123"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Customer Support (copy)"
+                },
+                "node_ids": {
+                    "description": "New ID of each node, by its ID in the source pathway",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "node-1": "node_5f2b6c0e9a8d4e1f"
+                    }
+                },
+                "pathway": {
+                    "$ref": "#/definitions/model.PathwayData"
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "b7c3d4e5-pathway"
+                },
+                "source_pathway_id": {
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
+                }
+            }
+        },
+        "model.CombinedResponse": {
+            "type": "object",
+            "properties": {
+                "new_folder_id": {
+                    "description": "This is the field you want, not FolderID",
+                    "type": "string"
+                },
+                "old_folder_id": {
+                    "type": "string"
+                },
+                "pathway_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateChatRequest": {
+            "type": "object",
+            "required": [
+                "pathway_id",
+                "start_node_id"
+            ],
+            "properties": {
+                "pathway_id": {
+                    "type": "string"
+                },
+                "start_node_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateChatResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.CreateChatResponseData"
+                },
+                "errors": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateChatResponseData": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateFolderData": {
+            "type": "object",
+            "properties": {
+                "folder_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_folder_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateFolderRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parent_folder_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateFolderResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.CreateFolderData"
+                },
+                "errors": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreatePathwayRequest": {
+            "type": "object",
+            "required": [
+                "name"
             ],
             "properties": {
-                "goal": {
+                "description": {
                     "type": "string"
                 },
-                "questions": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "model.AnalyzeCallResponse": {
+        "model.CreateWebhookSubscriptionRequest": {
             "type": "object",
+            "required": [
+                "events",
+                "secret",
+                "url"
+            ],
             "properties": {
-                "answers": {
+                "description": {
+                    "type": "string",
+                    "example": "CRM sync"
+                },
+                "events": {
+                    "description": "call.completed, analysis.completed, pathway.created, pathway.updated, pathway.deleted",
                     "type": "array",
+                    "minItems": 1,
                     "items": {
                         "type": "string"
-                    }
-                },
-                "credits_used": {
-                    "type": "number"
+                    },
+                    "example": [
+                        "call.completed",
+                        "analysis.completed"
+                    ]
                 },
-                "message": {
-                    "type": "string"
+                "secret": {
+                    "description": "Key of the X-Webhook-Signature HMAC; never returned",
+                    "type": "string",
+                    "minLength": 16,
+                    "example": "9c1f0b7e2a4d4c6f8e0a1b3c5d7e9f11"
                 },
-                "status": {
-                    "type": "string"
+                "url": {
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bland"
                 }
             }
         },
-        "model.CallDetail": {
+        "model.DNCBlock": {
             "type": "object",
             "properties": {
-                "analysis": {
+                "batch_id": {
+                    "description": "Set when the call was part of a batch",
                     "type": "string",
-                    "example": "Detailed analysis of the call..."
+                    "example": "batch_5f2b6c0e9a8d4e1f"
                 },
-                "analysis_schema": {
+                "blocked_at": {
                     "type": "string",
-                    "example": "analysis-schema-123"
+                    "example": "2024-09-25T12:34:56.789Z"
                 },
-                "answered_by": {
+                "field": {
+                    "description": "Field of the call holding the listed number: phone_number or transfer_phone_number",
                     "type": "string",
-                    "example": "human"
+                    "example": "phone_number"
                 },
-                "batch_id": {
+                "id": {
                     "type": "string",
-                    "example": "batch123"
+                    "example": "block_5f2b6c0e9a8d4e1f"
                 },
-                "call_ended_by": {
+                "pathway_id": {
                     "type": "string",
-                    "example": "customer"
+                    "example": "3c90c3cc-0d44-4b50-8888-8dd25736052a"
                 },
-                "call_id": {
+                "phone_number": {
                     "type": "string",
-                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
-                },
-                "call_length": {
-                    "type": "number",
-                    "example": 120.5
-                },
-                "completed": {
-                    "type": "boolean",
-                    "example": true
+                    "example": "+14155552671"
                 },
-                "concatenated_transcript": {
+                "reason": {
+                    "description": "Reason the number was listed",
                     "type": "string",
-                    "example": "Full transcript of the call..."
-                },
-                "corrected_duration": {
+                    "example": "Asked not to be called again"
+                }
+            }
+        },
+        "model.DNCEntry": {
+            "type": "object",
+            "properties": {
+                "added_at": {
                     "type": "string",
-                    "example": "2m 30s"
+                    "example": "2024-09-25T12:34:56Z"
                 },
-                "created_at": {
+                "phone_number": {
+                    "description": "E.164",
                     "type": "string",
-                    "example": "2024-09-26T12:34:56Z"
+                    "example": "+14155552671"
                 },
-                "end_at": {
+                "reason": {
                     "type": "string",
-                    "example": "2024-09-26T12:36:56Z"
+                    "example": "Asked not to be called again"
+                }
+            }
+        },
+        "model.DNCImportResponse": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer",
+                    "example": 98
                 },
-                "endpoint_url": {
-                    "type": "string",
-                    "example": "https://api.bland.ai/callback"
+                "row_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CSVRowError"
+                    }
                 },
-                "error_message": {
-                    "type": "string",
-                    "example": "Call failed due to timeout"
+                "total_rows": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "model.DeletePathwayResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Message providing details (e.g., \"Pathway deleted successfully\")",
+                    "type": "string"
                 },
-                "from": {
-                    "type": "string",
-                    "example": "+14155552672"
+                "pathway_id": {
+                    "description": "The ID of the pathway being used",
+                    "type": "string"
                 },
-                "inbound": {
-                    "type": "boolean",
-                    "example": false
+                "status": {
+                    "description": "Status of the operation (success or error)",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Edge": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
                 },
-                "local_dialing": {
-                    "type": "boolean",
-                    "example": false
+                "id": {
+                    "type": "string"
                 },
-                "max_duration": {
-                    "type": "number",
-                    "example": 3600
+                "label": {
+                    "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        " \"region\"": "\"US\"}",
-                        "{\"source\"": "\"ads\""
-                    }
+                "source": {
+                    "type": "string"
                 },
-                "pathway_logs": {
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string",
-                    "example": "Log details here..."
+                    "example": "upstream_error"
                 },
-                "price": {
-                    "type": "number",
-                    "example": 0.05
+                "fields": {
+                    "description": "Field-level validation errors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldError"
+                    }
                 },
-                "queue_status": {
+                "message": {
                     "type": "string",
-                    "example": "completed"
-                },
-                "record": {
-                    "type": "boolean",
-                    "example": true
+                    "example": "Pathway creation failed"
                 },
-                "recording_url": {
+                "request_id": {
                     "type": "string",
-                    "example": "https://api.bland.ai/recordings/12345"
+                    "example": "5f2b6c0e9a8d4e1f"
                 },
-                "request_data": {
-                    "$ref": "#/definitions/model.RequestData"
+                "upstream_body": {
+                    "description": "Parsed as JSON when possible, otherwise the raw string",
+                    "type": "object"
                 },
-                "started_at": {
+                "upstream_status": {
+                    "type": "integer",
+                    "example": 500
+                }
+            }
+        },
+        "model.Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string",
-                    "example": "2024-09-26T12:34:56Z"
+                    "example": "2024-09-26T12:36:56Z"
                 },
-                "status": {
-                    "type": "string",
-                    "example": "completed"
+                "data": {
+                    "description": "CallDetail for call events",
+                    "type": "object"
                 },
-                "summary": {
+                "id": {
                     "type": "string",
-                    "example": "Call summary notes..."
+                    "example": "evt_5f2b6c0e9a8d4e1f"
                 },
-                "to": {
+                "type": {
                     "type": "string",
-                    "example": "+14155552671"
-                },
-                "transcripts": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Transcript"
-                    }
-                },
-                "variables": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        " \"campaign\"": "\"welcome\"}",
-                        "{\"user_id\"": "\"12345\""
-                    }
+                    "example": "call.completed"
                 }
             }
         },
-        "model.CallResponse": {
+        "model.FieldError": {
             "type": "object",
             "properties": {
-                "call_id": {
-                    "type": "string"
+                "code": {
+                    "type": "string",
+                    "example": "invalid_length"
                 },
-                "status": {
-                    "type": "string"
+                "field": {
+                    "type": "string",
+                    "example": "phone_number"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "phone number is a short code"
                 }
             }
         },
-        "model.ChatHistoryEntry": {
+        "model.GetPathwayResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "The message content",
+                "description": {
                     "type": "string"
                 },
-                "role": {
-                    "description": "\"user\" or \"assistant\"",
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Edge"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Node"
+                    }
+                },
+                "production_version_number": {
+                    "type": "string"
+                },
+                "published_at": {
                     "type": "string"
                 }
             }
         },
-        "model.CombinedResponse": {
+        "model.ImportPathwayResponse": {
             "type": "object",
             "properties": {
-                "new_folder_id": {
-                    "description": "This is the field you want, not FolderID",
-                    "type": "string"
+                "folder_id": {
+                    "type": "string",
+                    "example": "folder_123"
                 },
-                "old_folder_id": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "Customer Support"
                 },
-                "pathway_id": {
-                    "type": "string"
+                "node_ids": {
+                    "description": "New ID of each node, by its ID in the document",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "node-1": "node_5f2b6c0e9a8d4e1f"
+                    }
                 },
-                "status": {
-                    "type": "string"
+                "pathway": {
+                    "$ref": "#/definitions/model.PathwayData"
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
                 }
             }
         },
-        "model.CreateChatRequest": {
+        "model.ModelOptions": {
             "type": "object",
-            "required": [
-                "pathway_id",
-                "start_node_id"
-            ],
             "properties": {
-                "pathway_id": {
-                    "type": "string"
+                "block_interruptions": {
+                    "type": "boolean"
                 },
-                "start_node_id": {
+                "modelType": {
                     "type": "string"
+                },
+                "skipUserResponse": {
+                    "type": "boolean"
+                },
+                "temperature": {
+                    "type": "number"
                 }
             }
         },
-        "model.CreateChatResponse": {
+        "model.Node": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/model.CreateChatResponseData"
+                    "$ref": "#/definitions/model.NodeData"
                 },
-                "errors": {
+                "id": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "model.CreateChatResponseData": {
+        "model.NodeData": {
             "type": "object",
             "properties": {
-                "chat_id": {
+                "active": {
+                    "type": "boolean"
+                },
+                "condition": {
                     "type": "string"
                 },
-                "message": {
+                "globalDescription": {
                     "type": "string"
-                }
-            }
-        },
-        "model.CreateFolderData": {
-            "type": "object",
-            "properties": {
-                "folder_id": {
+                },
+                "globalLabel": {
                     "type": "string"
                 },
+                "globalPrompt": {
+                    "type": "string"
+                },
+                "isGlobal": {
+                    "type": "boolean"
+                },
+                "isStart": {
+                    "type": "boolean"
+                },
+                "modelOptions": {
+                    "$ref": "#/definitions/model.ModelOptions"
+                },
                 "name": {
                     "type": "string"
                 },
-                "parent_folder_id": {
+                "prompt": {
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Static text spoken as is; used instead of prompt",
                     "type": "string"
                 }
             }
         },
-        "model.CreateFolderRequest": {
+        "model.PathwayData": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "description": {
                     "type": "string"
                 },
-                "parent_folder_id": {
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Edge"
+                    }
+                },
+                "name": {
                     "type": "string"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Node"
+                    }
                 }
             }
         },
-        "model.CreateFolderResponse": {
+        "model.PathwayDiff": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.CreateFolderData"
+                "against": {
+                    "description": "body, pathway or snapshot",
+                    "type": "string",
+                    "example": "pathway"
                 },
-                "errors": {
-                    "type": "string"
+                "against_id": {
+                    "description": "ID of the compared pathway or snapshot",
+                    "type": "string",
+                    "example": "b7c3d4e5-pathway"
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PathwayEdgeChange"
+                    }
+                },
+                "fields": {
+                    "description": "Changes to the name and description",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PathwayFieldChange"
+                    }
+                },
+                "identical": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PathwayNodeChange"
+                    }
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
                 }
             }
         },
-        "model.CreatePathwayRequest": {
+        "model.PathwayDocument": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Handles inbound support calls"
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Edge"
+                    }
+                },
+                "exported_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "folder_path": {
+                    "description": "Folder names from the root, separated by /, with / and \\ in names escaped as \\/ and \\\\; empty for the root",
+                    "type": "string",
+                    "example": "Support/Inbound"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Customer Support"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Node"
+                    }
+                },
+                "version": {
+                    "description": "Format version of the document",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "model.DeletePathwayResponse": {
+        "model.PathwayEdgeChange": {
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Message providing details (e.g., \"Pathway deleted successfully\")",
-                    "type": "string"
+                "change": {
+                    "description": "added, removed or modified",
+                    "type": "string",
+                    "example": "added"
                 },
-                "pathway_id": {
-                    "description": "The ID of the pathway being used",
-                    "type": "string"
+                "edge_id": {
+                    "type": "string",
+                    "example": "edge-1"
                 },
-                "status": {
-                    "description": "Status of the operation (success or error)",
-                    "type": "string"
+                "fields": {
+                    "description": "Changed fields of a modified edge",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PathwayFieldChange"
+                    }
+                },
+                "source": {
+                    "type": "string",
+                    "example": "node-1"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "node-2"
                 }
             }
         },
-        "model.Edge": {
+        "model.PathwayFieldChange": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "label": {
-                    "type": "string"
+                "field": {
+                    "type": "string",
+                    "example": "data.prompt"
                 },
-                "source": {
-                    "type": "string"
+                "from": {
+                    "description": "Value in the compared version, null when unset",
+                    "type": "string",
+                    "example": "Greet the caller"
                 },
-                "target": {
-                    "type": "string"
+                "to": {
+                    "description": "Value in the current pathway, null when unset",
+                    "type": "string",
+                    "example": "Greet the caller by name"
                 }
             }
         },
-        "model.ErrorResponse": {
+        "model.PathwayIssue": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "unreachable_node"
+                },
+                "edge_id": {
+                    "type": "string",
+                    "example": "edge-2"
+                },
+                "field": {
+                    "description": "Path of the offending value in the request body",
+                    "type": "string",
+                    "example": "nodes[3]"
+                },
                 "message": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "node Goodbye cannot be reached from the start node"
+                },
+                "node_id": {
+                    "type": "string",
+                    "example": "node-4"
+                },
+                "severity": {
+                    "description": "error, or warning for problems that do not make the pathway invalid",
+                    "type": "string",
+                    "example": "error"
                 }
             }
         },
-        "model.GetPathwayResponse": {
+        "model.PathwayNodeChange": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "change": {
+                    "description": "added, removed or modified",
+                    "type": "string",
+                    "example": "modified"
                 },
-                "edges": {
+                "fields": {
+                    "description": "Changed fields of a modified node",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Edge"
+                        "$ref": "#/definitions/model.PathwayFieldChange"
                     }
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Greeting"
                 },
-                "nodes": {
+                "node_id": {
+                    "type": "string",
+                    "example": "node-1"
+                }
+            }
+        },
+        "model.PathwaySnapshot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:34:56Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Customer Support"
+                },
+                "pathway": {
+                    "description": "Full pathway; omitted from listings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.GetPathwayResponse"
+                        }
+                    ]
+                },
+                "pathway_id": {
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
+                },
+                "reason": {
+                    "description": "update, delete or restore: the operation the snapshot was taken before",
+                    "type": "string",
+                    "example": "update"
+                },
+                "snapshot_id": {
+                    "type": "string",
+                    "example": "snap_5f2b6c0e9a8d4e1f"
+                },
+                "version": {
+                    "description": "Position in the pathway's history, starting at 1",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.PathwayValidation": {
+            "type": "object",
+            "properties": {
+                "issues": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Node"
+                        "$ref": "#/definitions/model.PathwayIssue"
                     }
                 },
-                "production_version_number": {
+                "valid": {
+                    "description": "True when no issue is an error",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "model.RequestData": {
+            "type": "object",
+            "properties": {
+                "language": {
                     "type": "string"
                 },
-                "published_at": {
+                "phone_number": {
                     "type": "string"
+                },
+                "wait": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.RescheduleCallRequest": {
+            "type": "object",
+            "required": [
+                "scheduled_at"
+            ],
+            "properties": {
+                "scheduled_at": {
+                    "description": "RFC 3339 time",
+                    "type": "string",
+                    "example": "2024-09-27T10:00:00-04:00"
+                },
+                "timezone": {
+                    "description": "Changes the contact's time zone when set",
+                    "type": "string",
+                    "example": "America/Chicago"
                 }
             }
         },
-        "model.ModelOptions": {
+        "model.RestorePathwayResponse": {
             "type": "object",
             "properties": {
-                "block_interruptions": {
-                    "type": "boolean"
+                "pathway": {
+                    "$ref": "#/definitions/model.PathwayData"
                 },
-                "modelType": {
-                    "type": "string"
+                "pathway_id": {
+                    "description": "New ID when the pathway was re-created",
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
                 },
-                "skipUserResponse": {
-                    "type": "boolean"
+                "recreated": {
+                    "description": "True when the pathway had been deleted and was created again",
+                    "type": "boolean",
+                    "example": false
                 },
-                "temperature": {
-                    "type": "number"
+                "snapshot_id": {
+                    "type": "string",
+                    "example": "snap_5f2b6c0e9a8d4e1f"
                 }
             }
         },
-        "model.Node": {
+        "model.ScheduleCallRequest": {
             "type": "object",
+            "required": [
+                "call",
+                "scheduled_at"
+            ],
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.NodeData"
+                "call": {
+                    "$ref": "#/definitions/model.SendCall"
                 },
-                "id": {
-                    "type": "string"
+                "scheduled_at": {
+                    "description": "RFC 3339 time; moved to the next window opening when outside the window",
+                    "type": "string",
+                    "example": "2024-09-26T10:00:00-04:00"
                 },
-                "type": {
-                    "type": "string"
+                "timezone": {
+                    "description": "IANA time zone of the contact; the configured default when empty",
+                    "type": "string",
+                    "example": "America/New_York"
+                },
+                "window": {
+                    "description": "Overrides the configured calling window",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CallingWindow"
+                        }
+                    ]
                 }
             }
         },
-        "model.NodeData": {
+        "model.ScheduledCall": {
             "type": "object",
             "properties": {
-                "active": {
-                    "type": "boolean"
-                },
-                "condition": {
-                    "type": "string"
-                },
-                "globalDescription": {
-                    "type": "string"
+                "call": {
+                    "$ref": "#/definitions/model.SendCall"
                 },
-                "globalLabel": {
-                    "type": "string"
-                },
-                "globalPrompt": {
-                    "type": "string"
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
                 },
-                "isGlobal": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56Z"
                 },
-                "isStart": {
-                    "type": "boolean"
+                "dispatched_at": {
+                    "type": "string",
+                    "example": "2024-09-26T13:00:01Z"
                 },
-                "modelOptions": {
-                    "$ref": "#/definitions/model.ModelOptions"
+                "due_at": {
+                    "description": "Time the call will be dispatched, inside the calling window",
+                    "type": "string",
+                    "example": "2024-09-26T09:00:00-04:00"
                 },
-                "name": {
-                    "type": "string"
+                "error": {
+                    "$ref": "#/definitions/model.ErrorResponse"
                 },
-                "prompt": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.PathwayData": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
+                "id": {
+                    "type": "string",
+                    "example": "sched_5f2b6c0e9a8d4e1f"
                 },
-                "edges": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Edge"
-                    }
+                "scheduled_at": {
+                    "description": "Time requested by the caller",
+                    "type": "string",
+                    "example": "2024-09-26T07:00:00-04:00"
                 },
-                "name": {
-                    "type": "string"
+                "status": {
+                    "description": "scheduled, dispatching, dispatched, failed or cancelled",
+                    "type": "string",
+                    "example": "scheduled"
                 },
-                "nodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Node"
-                    }
-                }
-            }
-        },
-        "model.RequestData": {
-            "type": "object",
-            "properties": {
-                "language": {
-                    "type": "string"
+                "timezone": {
+                    "type": "string",
+                    "example": "America/New_York"
                 },
-                "phone_number": {
-                    "type": "string"
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56Z"
                 },
-                "wait": {
-                    "type": "boolean"
+                "window": {
+                    "$ref": "#/definitions/model.CallingWindow"
                 }
             }
         },
         "model.SendCall": {
             "type": "object",
             "required": [
-                "pathway_id",
                 "phone_number"
             ],
             "properties": {
+                "answered_by_enabled": {
+                    "description": "Detect whether a human or a voicemail answered",
+                    "type": "boolean",
+                    "example": true
+                },
+                "first_sentence": {
+                    "description": "First thing the agent says",
+                    "type": "string",
+                    "example": "Hi, this is Maya from Acme."
+                },
+                "from": {
+                    "description": "Caller ID, must be a number owned by the account",
+                    "type": "string",
+                    "example": "+14155552673"
+                },
+                "language": {
+                    "description": "Language of the conversation",
+                    "type": "string",
+                    "example": "en-US"
+                },
+                "max_duration": {
+                    "description": "Maximum call length in minutes",
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 1,
+                    "example": 12
+                },
+                "metadata": {
+                    "description": "Arbitrary data returned with the call",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "campaign": "spring"
+                    }
+                },
                 "pathway_id": {
-                    "type": "string"
+                    "description": "Pathway that drives the conversation",
+                    "type": "string",
+                    "example": "a6b2c3d4-pathway"
                 },
                 "phone_number": {
-                    "type": "string"
+                    "description": "Number to call",
+                    "type": "string",
+                    "example": "+14155552671"
+                },
+                "record": {
+                    "description": "Record the call audio",
+                    "type": "boolean",
+                    "example": true
+                },
+                "request_data": {
+                    "description": "Variables available to the agent",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "name": "Jane"
+                    }
+                },
+                "start_time": {
+                    "description": "Schedule the call for later",
+                    "type": "string",
+                    "example": "2024-09-26 12:00:00 -05:00"
+                },
+                "task": {
+                    "description": "Prompt used instead of a pathway",
+                    "type": "string",
+                    "example": "Confirm the appointment"
+                },
+                "transfer_phone_number": {
+                    "description": "Number to transfer the call to",
+                    "type": "string",
+                    "example": "+14155552672"
+                },
+                "voice": {
+                    "description": "Voice name or ID",
+                    "type": "string",
+                    "example": "maya"
+                },
+                "voicemail_action": {
+                    "description": "What to do when a voicemail is reached",
+                    "type": "string",
+                    "enum": [
+                        "hangup",
+                        "leave_message",
+                        "ignore"
+                    ],
+                    "example": "leave_message"
+                },
+                "voicemail_message": {
+                    "description": "Message left when a voicemail is reached",
+                    "type": "string",
+                    "example": "Sorry we missed you."
+                },
+                "wait_for_greeting": {
+                    "description": "Wait for the callee to speak first",
+                    "type": "boolean",
+                    "example": true
+                },
+                "webhook": {
+                    "description": "URL notified when the call ends",
+                    "type": "string",
+                    "example": "https://example.com/bland/callback"
                 }
             }
         },
@@ -1149,6 +5564,64 @@ const docTemplate = `{
                 }
             }
         },
+        "model.StopBatchResponse": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string",
+                    "example": "batch_5f2b6c0e9a8d4e1f"
+                },
+                "cancelled": {
+                    "description": "Contacts that were still pending and will not be called",
+                    "type": "integer",
+                    "example": 20
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StopCallResult"
+                    }
+                },
+                "stopped": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "model.StopCallResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Call ended successfully."
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "model.StopCallResult": {
+            "type": "object",
+            "properties": {
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.ErrorResponse"
+                },
+                "status": {
+                    "description": "stopped or failed",
+                    "type": "string",
+                    "example": "stopped"
+                }
+            }
+        },
         "model.Transcript": {
             "type": "object",
             "properties": {
@@ -1192,6 +5665,104 @@ const docTemplate = `{
                     "x-order": "4"
                 }
             }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts since the delivery was created or last redelivered",
+                    "type": "integer",
+                    "example": 2
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:36:56Z"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:37:17Z"
+                },
+                "event": {
+                    "$ref": "#/definitions/model.Event"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "dlv_5f2b6c0e9a8d4e1f"
+                },
+                "last_attempt_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:36:56Z"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "target answered 503 Service Unavailable"
+                },
+                "last_status_code": {
+                    "type": "integer",
+                    "example": 503
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "example": "2024-09-26T12:37:16Z"
+                },
+                "status": {
+                    "description": "pending, delivered or dead",
+                    "type": "string",
+                    "example": "pending"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "sub_5f2b6c0e9a8d4e1f"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bland"
+                }
+            }
+        },
+        "model.WebhookReceipt": {
+            "type": "object",
+            "properties": {
+                "call_id": {
+                    "type": "string",
+                    "example": "f0300301-b066-47a0-83ce-895cb1b63a9a"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "received"
+                }
+            }
+        },
+        "model.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-09-25T12:34:56Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "CRM sync"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "call.completed",
+                        "analysis.completed"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "sub_5f2b6c0e9a8d4e1f"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bland"
+                }
+            }
         }
     },
     "securityDefinitions": {
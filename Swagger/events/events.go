@@ -0,0 +1,79 @@
+// Package events is an in-process publish/subscribe bus for things that happen
+// to calls, analyses and pathways, so features can react to them without the
+// handlers knowing about each other.
+package events
+
+import (
+	"bland/model"
+	"sync"
+)
+
+// Event types published by the controller.
+const (
+	CallCompleted     = "call.completed"
+	AnalysisCompleted = "analysis.completed"
+	PathwayCreated    = "pathway.created"
+	PathwayUpdated    = "pathway.updated"
+	PathwayDeleted    = "pathway.deleted"
+)
+
+// Types lists every event type that can be published.
+var Types = []string{CallCompleted, AnalysisCompleted, PathwayCreated, PathwayUpdated, PathwayDeleted}
+
+// Known reports whether eventType is one of Types.
+func Known(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler receives published events. Handlers run synchronously in the
+// publisher's goroutine and must hand slow work off to their own goroutines.
+type Handler func(event model.Event)
+
+type subscription struct {
+	types   map[string]bool
+	handler Handler
+}
+
+// Bus delivers events to subscribers. It is safe for concurrent use.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+// NewBus returns a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers handler for the given event types, or for every event when none are given.
+func (b *Bus) Subscribe(handler Handler, types ...string) {
+	sub := subscription{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, sub)
+}
+
+// Publish delivers event to every subscriber of its type.
+func (b *Bus) Publish(event model.Event) {
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if sub.types == nil || sub.types[event.Type] {
+			sub.handler(event)
+		}
+	}
+}
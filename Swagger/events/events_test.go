@@ -0,0 +1,35 @@
+package events
+
+import (
+	"bland/model"
+	"reflect"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	bus := NewBus()
+	var all, completed []string
+	bus.Subscribe(func(event model.Event) { all = append(all, event.ID) })
+	bus.Subscribe(func(event model.Event) { completed = append(completed, event.ID) }, CallCompleted)
+
+	bus.Publish(model.Event{ID: "evt_1", Type: CallCompleted})
+	bus.Publish(model.Event{ID: "evt_2", Type: "call.other"})
+
+	if want := []string{"evt_1", "evt_2"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("subscriber of every event got %v, want %v", all, want)
+	}
+	if want := []string{"evt_1"}; !reflect.DeepEqual(completed, want) {
+		t.Errorf("subscriber of %s got %v, want %v", CallCompleted, completed, want)
+	}
+}
+
+func TestKnown(t *testing.T) {
+	for _, eventType := range Types {
+		if !Known(eventType) {
+			t.Errorf("Known(%q) = false", eventType)
+		}
+	}
+	if Known("call.other") {
+		t.Error(`Known("call.other") = true`)
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bland/config"
 	"bland/controller"
 	_ "bland/docs"
+	"context"
+	"log"
+	_ "time/tzdata" // Calling windows need time zones; the container image has no zoneinfo
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -31,29 +35,113 @@ import (
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+
+	ctl, err := controller.New(cfg)
+	if err != nil {
+		log.Fatalf("Error opening local stores: %v", err)
+	}
+	ctl.Start(context.Background())
+
+	r := setupRouter(ctl)
+	r.Run("0.0.0.0:8080")
+}
+
+// setupRouter registers the API and Swagger routes for ctl.
+func setupRouter(ctl *controller.Controller) *gin.Engine {
 	r := gin.Default()
+	r.Use(controller.RequestID())
 
 	v1 := r.Group("/api/v1")
 
 	{
-        // Define the route for sending calls 
-		v1.POST("/call", controller.SendCall)
+		// Define the route for sending calls
+		v1.POST("/call", ctl.SendCall)
+		// Define the routes for dispatching a batch of calls, reading its results and stopping it
+		v1.POST("/calls/batch", ctl.DispatchBatch)
+		v1.GET("/calls/batch/:batch_id", ctl.GetBatch)
+		v1.POST("/calls/batch/:batch_id/stop", ctl.StopBatch)
+		// Define the route for importing a CSV contact list as a batch
+		v1.POST("/calls/batch/csv", ctl.ImportCSV)
+		// Define the routes for scheduling calls and managing scheduled calls
+		v1.POST("/calls/scheduled", ctl.ScheduleCall)
+		v1.GET("/calls/scheduled", ctl.ListScheduledCalls)
+		v1.GET("/calls/scheduled/:scheduled_id", ctl.GetScheduledCall)
+		v1.PATCH("/calls/scheduled/:scheduled_id", ctl.RescheduleCall)
+		v1.DELETE("/calls/scheduled/:scheduled_id", ctl.CancelScheduledCall)
+		// Define the routes for managing the do-not-call list and its audit of blocked calls
+		v1.GET("/dnc", ctl.ListDNC)
+		v1.POST("/dnc", ctl.AddDNC)
+		v1.POST("/dnc/import", ctl.ImportDNC)
+		v1.DELETE("/dnc/:phone_number", ctl.RemoveDNC)
+		v1.GET("/dnc/blocks", ctl.ListDNCBlocks)
 		// Define the route for analyzing call
-		v1.POST("call/:call_id/analyze", controller.AnalyzeCall)
-		// Define the route for getting call details
-	    v1.GET("/calls/:call_id", controller.GetCallDetails)
+		v1.POST("call/:call_id/analyze", ctl.AnalyzeCall)
+		// Define the routes for managing analysis schemas and analyzing calls with them
+		v1.POST("/analysis/schemas", ctl.CreateAnalysisSchema)
+		v1.GET("/analysis/schemas", ctl.ListAnalysisSchemas)
+		v1.GET("/analysis/schemas/:schema_id", ctl.GetAnalysisSchema)
+		v1.PUT("/analysis/schemas/:schema_id", ctl.UpdateAnalysisSchema)
+		v1.DELETE("/analysis/schemas/:schema_id", ctl.DeleteAnalysisSchema)
+		v1.POST("call/:call_id/analyze/:schema_id", ctl.AnalyzeCallWithSchema)
+		// Define the routes for managing automatic post-call analysis rules
+		v1.POST("/analysis/rules", ctl.CreateAutoAnalysisRule)
+		v1.GET("/analysis/rules", ctl.ListAutoAnalysisRules)
+		v1.GET("/analysis/rules/:rule_id", ctl.GetAutoAnalysisRule)
+		v1.PUT("/analysis/rules/:rule_id", ctl.UpdateAutoAnalysisRule)
+		v1.DELETE("/analysis/rules/:rule_id", ctl.DeleteAutoAnalysisRule)
+		// Define the routes for analyzing many calls and reading the results
+		v1.POST("/analysis/jobs", ctl.StartAnalysisJob)
+		v1.GET("/analysis/jobs/:job_id", ctl.GetAnalysisJob)
+		v1.GET("/analysis/jobs/:job_id/results", ctl.GetAnalysisJobResults)
+		// Define the routes for listing the call history and getting call details
+		v1.GET("/calls", ctl.ListCalls)
+		v1.GET("/calls/:call_id", ctl.GetCallDetails)
+		// Define the route for waiting until a call completes
+		v1.GET("/calls/:call_id/wait", ctl.WaitForCall)
+		// Define the route for exporting a call transcript
+		v1.GET("/calls/:call_id/transcript", ctl.GetTranscript)
+		// Define the route for streaming a call recording
+		v1.GET("/calls/:call_id/recording", ctl.GetRecording)
+		// Define the route for ending an active call
+		v1.POST("/calls/:call_id/stop", ctl.StopCall)
 		// Define the route for creating a folder
-	   v1.POST("/folders", controller.CreateFolder)
-	   // Define the route that creates the pathway and move to specfic folder
-	   v1.POST("/pathways/create-and-move", controller.CreateAndMovePathway)
-	   // Define the route for creating a chat to test AI bots
-	   v1.POST("/pathways/chat/create", controller.CreateChat)
-	   v1.GET("/convo_pathway/:pathway_id", controller.GetPathwayInfo)
-	   v1.POST("/pathway/update/:pathway_id", controller.UpdatePathway)
-	   v1.DELETE("/delete/convo_pathway/:pathway_id", controller.DeletePathway)
-	   v1.POST("/pathways/chat/:chat_id/send", controller.SendMessageToChat)
-    }
+		v1.POST("/folders", ctl.CreateFolder)
+		// Define the route that creates the pathway and move to specfic folder
+		v1.POST("/pathways/create-and-move", ctl.CreateAndMovePathway)
+		// Define the route for creating a chat to test AI bots
+		v1.POST("/pathways/chat/create", ctl.CreateChat)
+		v1.GET("/convo_pathway/:pathway_id", ctl.GetPathwayInfo)
+		v1.POST("/pathway/update/:pathway_id", ctl.UpdatePathway)
+		// Define the route for validating pathway nodes and edges without updating the pathway
+		v1.POST("/pathways/validate", ctl.ValidatePathway)
+		// Define the route for comparing a pathway with another version
+		v1.GET("/pathways/:pathway_id/diff", ctl.GetPathwayDiff)
+		// Define the routes for listing pathway snapshots and restoring a pathway to one
+		v1.GET("/pathways/:pathway_id/snapshots", ctl.ListPathwaySnapshots)
+		v1.GET("/pathways/:pathway_id/snapshots/:snapshot_id", ctl.GetPathwaySnapshot)
+		v1.POST("/pathways/:pathway_id/snapshots/:snapshot_id/restore", ctl.RestorePathwaySnapshot)
+		// Define the routes for exporting a pathway as a document and importing one
+		v1.GET("/pathways/:pathway_id/export", ctl.ExportPathway)
+		v1.POST("/pathways/import", ctl.ImportPathway)
+		// Define the route for copying a pathway, possibly into another account
+		v1.POST("/pathways/:pathway_id/clone", ctl.ClonePathway)
+		v1.DELETE("/delete/convo_pathway/:pathway_id", ctl.DeletePathway)
+		v1.POST("/pathways/chat/:chat_id/send", ctl.SendMessageToChat)
+		// Define the route that receives call callbacks from Bland
+		v1.POST("/webhooks/bland", ctl.ReceiveBlandWebhook)
+		// Define the routes for managing event subscriptions and their deliveries
+		v1.POST("/webhooks/subscriptions", ctl.CreateWebhookSubscription)
+		v1.GET("/webhooks/subscriptions", ctl.ListWebhookSubscriptions)
+		v1.DELETE("/webhooks/subscriptions/:subscription_id", ctl.DeleteWebhookSubscription)
+		v1.GET("/webhooks/deliveries", ctl.ListWebhookDeliveries)
+		v1.GET("/webhooks/dead-letters", ctl.ListWebhookDeadLetters)
+		v1.POST("/webhooks/deliveries/:delivery_id/redeliver", ctl.RedeliverWebhook)
+	}
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	r.Run("0.0.0.0:8080")
+	return r
 }
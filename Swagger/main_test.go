@@ -0,0 +1,1431 @@
+package main
+
+import (
+	"bland/blandtest"
+	"bland/config"
+	"bland/controller"
+	"bland/model"
+	"bland/pathway"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter returns the API router of a controller that talks to a fake
+// Bland API and keeps its state in a temporary directory. Each configure
+// function may adjust the configuration first.
+func newTestRouter(t *testing.T, configure ...func(*config.Config)) (*gin.Engine, *blandtest.Server) {
+	t.Helper()
+	ctl, srv := newTestController(t, configure...)
+	return setupRouter(ctl), srv
+}
+
+// newTestController returns the controller behind newTestRouter, for tests
+// that need its background workers started.
+func newTestController(t *testing.T, configure ...func(*config.Config)) (*controller.Controller, *blandtest.Server) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	srv := blandtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	cfg := srv.Config()
+	cfg.DataDir = t.TempDir()
+	cfg.SecretKey = strings.Repeat("ab", 32)
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+	ctl, err := controller.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Let background work finish writing before the data directory is removed
+	t.Cleanup(ctl.Wait)
+	return ctl, srv
+}
+
+// request sends body as JSON with the given Authorization token, decodes the
+// response into out when given and returns the status code.
+func request(t *testing.T, r http.Handler, method, path, token string, body, out interface{}) int {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decoding %s: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+// upload sends fields and a file named file as a multipart form with the given
+// Authorization token, decodes the response into out when given and returns
+// the status code.
+func upload(t *testing.T, r http.Handler, path, token string, fields map[string]string, file string, out interface{}) int {
+	t.Helper()
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	part, err := form.CreateFormFile("file", "upload.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(part, file)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &buf)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("POST %s: decoding %s: %v", path, w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+func TestAuthorizationRequired(t *testing.T) {
+	r, _ := newTestRouter(t)
+	tests := []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/calls"},
+		{http.MethodGet, "/api/v1/calls/call-1"},
+		{http.MethodPost, "/api/v1/calls/batch/batch-1/stop"},
+		{http.MethodGet, "/api/v1/pathways/pathway-1/snapshots"},
+		{http.MethodGet, "/api/v1/pathways/pathway-1/snapshots/snap-1"},
+		{http.MethodPost, "/api/v1/pathways/pathway-1/snapshots/snap-1/restore"},
+		{http.MethodGet, "/api/v1/pathways/pathway-1/diff?snapshot_id=snap-1"},
+		{http.MethodGet, "/api/v1/analysis/schemas"},
+		{http.MethodGet, "/api/v1/webhooks/subscriptions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			var response model.ErrorResponse
+			if code := request(t, r, tt.method, tt.path, "", nil, &response); code != http.StatusUnauthorized || response.Code != model.ErrCodeUnauthorized {
+				t.Errorf("got %d %+v, want 401 %s", code, response, model.ErrCodeUnauthorized)
+			}
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	r, srv := newTestRouter(t)
+	prompt := "Hi"
+	pathwayID := srv.AddPathway(model.GetPathwayResponse{Name: "Support", Nodes: []model.Node{
+		{ID: "1", Type: "End Call", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}},
+	}})
+
+	t.Run("calls", func(t *testing.T) {
+		var call model.CallResponse
+		if code := request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": pathwayID}, &call); code != http.StatusOK || call.CallID == "" {
+			t.Fatalf("send call = %d %+v", code, call)
+		}
+		var detail model.CallDetail
+		if code := request(t, r, http.MethodGet, "/api/v1/calls/"+call.CallID, "token", nil, &detail); code != http.StatusOK || detail.CallID != call.CallID {
+			t.Errorf("call details = %d %+v", code, detail)
+		}
+		var analysis model.AnalyzeCallResponse
+		analyze := map[string]interface{}{"goal": "Qualify the lead", "questions": [][]string{{"Is the caller interested?", "boolean"}}}
+		if code := request(t, r, http.MethodPost, "/api/v1/call/"+call.CallID+"/analyze", "token", analyze, &analysis); code != http.StatusOK || len(analysis.Answers) != 1 {
+			t.Errorf("analyze = %d %+v", code, analysis)
+		}
+	})
+
+	t.Run("folders", func(t *testing.T) {
+		var folder model.CreateFolderData
+		if code := request(t, r, http.MethodPost, "/api/v1/folders", "token", map[string]string{"name": "Sales"}, &folder); code != http.StatusOK || folder.FolderID == "" {
+			t.Fatalf("create folder = %d %+v", code, folder)
+		}
+		var moved model.MovePathwayData
+		if code := request(t, r, http.MethodPost, "/api/v1/pathways/create-and-move?folder_id="+folder.FolderID, "token", map[string]string{"name": "Outbound"}, &moved); code != http.StatusOK ||
+			moved.NewFolderID == nil || *moved.NewFolderID != folder.FolderID {
+			t.Errorf("create and move = %d %+v, want the pathway in folder %s", code, moved, folder.FolderID)
+		}
+	})
+
+	t.Run("pathways", func(t *testing.T) {
+		var got model.GetPathwayResponse
+		if code := request(t, r, http.MethodGet, "/api/v1/convo_pathway/"+pathwayID, "token", nil, &got); code != http.StatusOK || got.Name != "Support" {
+			t.Fatalf("get pathway = %d %+v", code, got)
+		}
+		update := map[string]interface{}{"name": "Support v2", "nodes": []map[string]interface{}{
+			{"id": "1", "type": "End Call", "data": map[string]interface{}{"name": "Start", "isStart": true, "prompt": "Hello"}},
+		}}
+		if code := request(t, r, http.MethodPost, "/api/v1/pathway/update/"+pathwayID, "token", update, nil); code != http.StatusOK {
+			t.Errorf("update pathway = %d", code)
+		}
+		if p, _ := srv.Pathway(pathwayID); p.Name != "Support v2" {
+			t.Errorf("name after update = %s, want Support v2", p.Name)
+		}
+	})
+
+	t.Run("chat", func(t *testing.T) {
+		var chat model.CreateChatResponse
+		if code := request(t, r, http.MethodPost, "/api/v1/pathways/chat/create", "token", map[string]string{"pathway_id": pathwayID, "start_node_id": "1"}, &chat); code != http.StatusOK || chat.Data.ChatID == "" {
+			t.Fatalf("create chat = %d %+v", code, chat)
+		}
+		var reply model.SendMessageResponse
+		if code := request(t, r, http.MethodPost, "/api/v1/pathways/chat/"+chat.Data.ChatID+"/send", "token", map[string]string{"message": "Hello"}, &reply); code != http.StatusOK || len(reply.Data.ChatHistory) != 2 {
+			t.Errorf("send message = %d %+v", code, reply)
+		}
+	})
+
+	t.Run("delete pathway", func(t *testing.T) {
+		if code := request(t, r, http.MethodDelete, "/api/v1/delete/convo_pathway/"+pathwayID, "token", nil, nil); code != http.StatusOK {
+			t.Fatalf("delete pathway = %d", code)
+		}
+		if _, ok := srv.Pathway(pathwayID); ok {
+			t.Error("pathway still exists after delete")
+		}
+	})
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	r, srv := newTestRouter(t)
+	pathwayID := srv.AddPathway(model.GetPathwayResponse{Name: "Support"})
+	srv.Fail(blandtest.RouteGetPathway, blandtest.Failure{Status: http.StatusServiceUnavailable, Body: `{"message":"Service unavailable"}`, Times: 1})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/convo_pathway/"+pathwayID, nil)
+	req.Header.Set("Authorization", "token")
+	req.Header.Set(controller.RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusBadGateway || response.Code != model.ErrCodeUpstreamError || response.UpstreamStatus != http.StatusServiceUnavailable {
+		t.Errorf("got %d %+v, want 502 upstream_error with upstream status 503", w.Code, response)
+	}
+	if response.Message != "Service unavailable" || response.RequestID != "req-1" || w.Header().Get(controller.RequestIDHeader) != "req-1" {
+		t.Errorf("got %+v and header %q, want Bland's message and request ID req-1", response, w.Header().Get(controller.RequestIDHeader))
+	}
+
+	if code := request(t, r, http.MethodPost, "/api/v1/folders", "token", map[string]string{}, &response); code != http.StatusBadRequest || response.Code != model.ErrCodeInvalidRequest || response.RequestID == "" {
+		t.Errorf("invalid folder = %d %+v, want 400 invalid_request with a request ID", code, response)
+	}
+}
+
+func TestSendCallOptions(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	call := map[string]interface{}{
+		"phone_number": "+14155552671",
+		"task":         "Confirm the appointment",
+		"voice":        "maya",
+		"record":       true,
+		"max_duration": 12,
+		"metadata":     map[string]interface{}{"campaign": "spring"},
+		"request_data": map[string]interface{}{"name": "Jane"},
+	}
+	var response model.CallResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", call, &response); code != http.StatusOK {
+		t.Fatalf("send call = %d", code)
+	}
+
+	requests := srv.Requests()
+	var sent map[string]interface{}
+	if err := json.Unmarshal(requests[len(requests)-1].Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]interface{}{"task": "Confirm the appointment", "voice": "maya", "record": true, "max_duration": 12.0} {
+		if sent[key] != want {
+			t.Errorf("%s = %v, want %v", key, sent[key], want)
+		}
+	}
+	for _, key := range []string{"pathway_id", "first_sentence", "transfer_phone_number"} {
+		if _, ok := sent[key]; ok {
+			t.Errorf("unset option %s was sent to Bland", key)
+		}
+	}
+	if detail, _ := srv.Call(response.CallID); detail.Metadata["campaign"] != "spring" || detail.Variables["name"] != "Jane" {
+		t.Errorf("metadata %v and variables %v did not reach Bland", detail.Metadata, detail.Variables)
+	}
+
+	var errResponse model.ErrorResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671"}, &errResponse); code != http.StatusBadRequest || errResponse.Code != model.ErrCodeInvalidRequest {
+		t.Errorf("call without pathway or task = %d %+v, want 400 invalid_request", code, errResponse)
+	}
+}
+
+func TestSendCall(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		phone  string
+		status int
+		want   string // Number sent to Bland
+	}{
+		{"E.164", "+14155552671", http.StatusOK, "+14155552671"},
+		{"national number in the default region", "(415) 555-2671", http.StatusOK, "+14155552671"},
+		{"short code", "55555", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response model.CallResponse
+			code := request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": tt.phone, "pathway_id": "pathway-1"}, &response)
+			if code != tt.status {
+				t.Fatalf("status = %d, want %d", code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if detail, ok := srv.Call(response.CallID); !ok || detail.To != tt.want {
+				t.Errorf("Bland got call %q to %q, want a call to %s", response.CallID, detail.To, tt.want)
+			}
+
+			var detail model.CallDetail
+			if code := request(t, r, http.MethodGet, "/api/v1/calls/"+response.CallID, "token", nil, &detail); code != http.StatusOK || detail.To != tt.want {
+				t.Errorf("GET call = %d %+v, want the call to %s", code, detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchCalls(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	contacts := []map[string]interface{}{
+		{"phone_number": "+14155552671", "variables": map[string]string{"name": "Jane"}},
+		{"phone_number": "55555"},
+		{"phone_number": "+14155552672", "metadata": map[string]string{"crm_id": "42"}},
+	}
+	var batch model.Batch
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch", "token", map[string]interface{}{"pathway_id": "pathway-1", "contacts": contacts}, &batch); code != http.StatusAccepted {
+		t.Fatalf("dispatch batch = %d", code)
+	}
+	if batch.Total != 3 || len(batch.Results) != 3 {
+		t.Fatalf("batch = %+v, want three contacts", batch)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); batch.Status != model.BatchStatusCompleted; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch still %s", batch.Status)
+		}
+		if code := request(t, r, http.MethodGet, "/api/v1/calls/batch/"+batch.BatchID, "token", nil, &batch); code != http.StatusOK {
+			t.Fatalf("get batch = %d", code)
+		}
+	}
+	if batch.Dispatched != 2 || batch.Failed != 1 || batch.Results[1].Status != model.BatchContactFailed || batch.Results[1].Error == nil {
+		t.Errorf("batch = %+v, want the short code to fail and the others to be dispatched", batch)
+	}
+	detail, _ := srv.Call(batch.Results[2].CallID)
+	if detail.Metadata["batch_id"] != batch.BatchID || detail.Metadata["crm_id"] != "42" {
+		t.Errorf("metadata = %v, want the batch ID and the contact's metadata", detail.Metadata)
+	}
+	if detail, _ := srv.Call(batch.Results[0].CallID); detail.Variables["name"] != "Jane" {
+		t.Errorf("variables = %v, want the contact's variables", detail.Variables)
+	}
+
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/batch/batch-unknown", "token", nil, nil); code != http.StatusNotFound {
+		t.Errorf("unknown batch = %d, want 404", code)
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	r, srv := newTestRouter(t)
+	file := "phone_number,name,metadata.crm_id\n+14155552671,Jane,42\n123,Bob,43\n"
+
+	var dryRun model.CSVImportResponse
+	if code := upload(t, r, "/api/v1/calls/batch/csv", "token", map[string]string{"pathway_id": "pathway-1", "dry_run": "true"}, file, &dryRun); code != http.StatusOK {
+		t.Fatalf("dry run = %d", code)
+	}
+	if dryRun.TotalRows != 2 || dryRun.ValidRows != 1 || len(dryRun.RowErrors) != 1 || dryRun.RowErrors[0].Row != 3 || dryRun.Batch != nil {
+		t.Errorf("dry run = %+v, want one valid row, an error on line 3 and no batch", dryRun)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("dry run sent %d requests to Bland", len(srv.Requests()))
+	}
+
+	var imported model.CSVImportResponse
+	if code := upload(t, r, "/api/v1/calls/batch/csv", "token", map[string]string{"pathway_id": "pathway-1"}, file, &imported); code != http.StatusAccepted {
+		t.Fatalf("import = %d", code)
+	}
+	if imported.Batch == nil || imported.Batch.Total != 1 {
+		t.Fatalf("import = %+v, want a batch of the valid row", imported)
+	}
+
+	var response model.ErrorResponse
+	if code := upload(t, r, "/api/v1/calls/batch/csv", "token", map[string]string{"pathway_id": "pathway-1"}, "phone_number\n123\n", &response); code != http.StatusBadRequest || len(response.Fields) != 1 {
+		t.Errorf("file without valid rows = %d %+v, want 400 with the row error", code, response)
+	}
+}
+
+func TestBatchesSurviveARestart(t *testing.T) {
+	dataDir := t.TempDir()
+	useDataDir := func(cfg *config.Config) { cfg.DataDir = dataDir }
+	r, srv := newTestRouter(t, useDataDir)
+
+	file := "phone_number,metadata.batch_id\n+14155552671,batch-spoofed\n"
+	var imported model.CSVImportResponse
+	if code := upload(t, r, "/api/v1/calls/batch/csv", "token", map[string]string{"pathway_id": "pathway-1"}, file, &imported); code != http.StatusAccepted {
+		t.Fatalf("import = %d", code)
+	}
+	batchID := imported.Batch.BatchID
+	var batch model.Batch
+	for deadline := time.Now().Add(5 * time.Second); batch.Status != model.BatchStatusCompleted; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch still %s", batch.Status)
+		}
+		request(t, r, http.MethodGet, "/api/v1/calls/batch/"+batchID, "token", nil, &batch)
+	}
+	var sent model.SendCall
+	for _, req := range srv.Requests() {
+		if req.Route == blandtest.RouteSendCall {
+			json.Unmarshal(req.Body, &sent)
+		}
+	}
+	if sent.Metadata["batch_id"] != batchID {
+		t.Errorf("batch_id sent to Bland = %v, want %s", sent.Metadata["batch_id"], batchID)
+	}
+
+	restarted, _ := newTestRouter(t, useDataDir)
+	var got model.Batch
+	if code := request(t, restarted, http.MethodGet, "/api/v1/calls/batch/"+batchID, "token", nil, &got); code != http.StatusOK || got.Dispatched != 1 {
+		t.Errorf("batch after a restart = %d %+v, want the dispatched batch", code, got)
+	}
+	if code := request(t, restarted, http.MethodGet, "/api/v1/calls/batch/"+batchID, "other-token", nil, nil); code != http.StatusNotFound {
+		t.Errorf("batch of another token after a restart = %d, want 404", code)
+	}
+}
+
+func TestScheduledCalls(t *testing.T) {
+	r, _ := newTestRouter(t)
+	window := map[string]interface{}{"start": "00:00", "end": "23:59", "days": []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}}
+	at := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Minute)
+
+	var scheduled model.ScheduledCall
+	schedule := map[string]interface{}{
+		"call":         map[string]string{"phone_number": "(415) 555-2671", "pathway_id": "pathway-1"},
+		"scheduled_at": at.Format(time.RFC3339),
+		"timezone":     "UTC",
+		"window":       window,
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/scheduled", "token", schedule, &scheduled); code != http.StatusCreated {
+		t.Fatalf("schedule = %d", code)
+	}
+	if scheduled.Status != model.ScheduledStatusScheduled || scheduled.Call.PhoneNumber != "+14155552671" {
+		t.Errorf("scheduled call = %+v, want a scheduled call to +14155552671", scheduled)
+	}
+
+	var list []model.ScheduledCall
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/scheduled?status=scheduled", "token", nil, &list); code != http.StatusOK || len(list) != 1 {
+		t.Errorf("list = %d %+v, want the scheduled call", code, list)
+	}
+
+	later := at.Add(24 * time.Hour)
+	if code := request(t, r, http.MethodPatch, "/api/v1/calls/scheduled/"+scheduled.ID, "token", map[string]string{"scheduled_at": later.Format(time.RFC3339)}, &scheduled); code != http.StatusOK {
+		t.Errorf("reschedule = %d", code)
+	}
+	var got model.ScheduledCall
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/scheduled/"+scheduled.ID, "token", nil, &got); code != http.StatusOK || got.ScheduledAt != later.Format(time.RFC3339) {
+		t.Errorf("get = %d %+v, want it scheduled at %s", code, got, later.Format(time.RFC3339))
+	}
+
+	if code := request(t, r, http.MethodDelete, "/api/v1/calls/scheduled/"+scheduled.ID, "token", nil, &got); code != http.StatusOK || got.Status != model.ScheduledStatusCancelled {
+		t.Errorf("cancel = %d %+v, want it cancelled", code, got)
+	}
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodDelete, "/api/v1/calls/scheduled/"+scheduled.ID, "token", nil, &response); code != http.StatusConflict || response.Code != model.ErrCodeConflict {
+		t.Errorf("second cancel = %d %+v, want 409 conflict", code, response)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/scheduled/sched-unknown", "token", nil, nil); code != http.StatusNotFound {
+		t.Errorf("unknown scheduled call = %d, want 404", code)
+	}
+}
+
+func TestDoNotCallList(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var added []model.DNCEntry
+	if code := request(t, r, http.MethodPost, "/api/v1/dnc", "token", map[string]interface{}{"phone_numbers": []string{"(415) 555-2671"}, "reason": "Asked not to be called again"}, &added); code != http.StatusCreated || len(added) != 1 || added[0].PhoneNumber != "+14155552671" {
+		t.Fatalf("add = %d %+v, want +14155552671 added", code, added)
+	}
+	var imported model.DNCImportResponse
+	if code := upload(t, r, "/api/v1/dnc/import", "token", nil, "phone_number\n+14155552672\n123\n", &imported); code != http.StatusOK || imported.Added != 1 || len(imported.RowErrors) != 1 {
+		t.Errorf("import = %d %+v, want one number added and one row error", code, imported)
+	}
+	var list []model.DNCEntry
+	if code := request(t, r, http.MethodGet, "/api/v1/dnc", "token", nil, &list); code != http.StatusOK || len(list) != 2 {
+		t.Errorf("list = %d %+v, want both numbers", code, list)
+	}
+
+	var response model.ErrorResponse
+	call := map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", call, &response); code != http.StatusForbidden || response.Code != model.ErrCodeDoNotCall {
+		t.Errorf("call to a listed number = %d %+v, want 403 do_not_call", code, response)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("blocked call sent %d requests to Bland", len(srv.Requests()))
+	}
+	var blocks []model.DNCBlock
+	if code := request(t, r, http.MethodGet, "/api/v1/dnc/blocks?phone_number=%2B14155552671", "token", nil, &blocks); code != http.StatusOK || len(blocks) != 1 || blocks[0].Reason != "Asked not to be called again" {
+		t.Errorf("blocks = %d %+v, want the blocked call with the reason", code, blocks)
+	}
+
+	if code := request(t, r, http.MethodDelete, "/api/v1/dnc/+14155552671", "token", nil, nil); code != http.StatusOK {
+		t.Errorf("remove = %d", code)
+	}
+	if code := request(t, r, http.MethodDelete, "/api/v1/dnc/+14155552671", "token", nil, nil); code != http.StatusNotFound {
+		t.Errorf("second remove = %d, want 404", code)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", call, nil); code != http.StatusOK {
+		t.Errorf("call after removal = %d, want 200", code)
+	}
+}
+
+func TestDoNotCallListIsShared(t *testing.T) {
+	r, srv := newTestRouter(t)
+	if code := request(t, r, http.MethodPost, "/api/v1/dnc", "alice", map[string]interface{}{"phone_numbers": []string{"+14155552671"}}, nil); code != http.StatusCreated {
+		t.Fatalf("add = %d", code)
+	}
+	var list []model.DNCEntry
+	if code := request(t, r, http.MethodGet, "/api/v1/dnc", "bob", nil, &list); code != http.StatusOK || len(list) != 1 {
+		t.Errorf("list of bob = %d %+v, want the number alice added", code, list)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		call  map[string]string
+		field string
+	}{
+		{"dialled", map[string]string{"phone_number": "(415) 555-2671", "pathway_id": "pathway-1"}, "phone_number"},
+		{"transfer", map[string]string{"phone_number": "+14155552672", "transfer_phone_number": "(415) 555-2671", "pathway_id": "pathway-1"}, "transfer_phone_number"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var response model.ErrorResponse
+			if code := request(t, r, http.MethodPost, "/api/v1/call", "bob", tt.call, &response); code != http.StatusForbidden ||
+				response.Code != model.ErrCodeDoNotCall || len(response.Fields) != 1 || response.Fields[0].Field != tt.field {
+				t.Errorf("call by bob = %d %+v, want 403 do_not_call on %s", code, response, tt.field)
+			}
+		})
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("blocked calls sent %d requests to Bland", len(srv.Requests()))
+	}
+	var blocks []model.DNCBlock
+	if code := request(t, r, http.MethodGet, "/api/v1/dnc/blocks", "bob", nil, &blocks); code != http.StatusOK || len(blocks) != 2 || blocks[0].PhoneNumber != "+14155552671" {
+		t.Errorf("blocks of bob = %d %+v, want both refused calls", code, blocks)
+	}
+
+	if code := request(t, r, http.MethodDelete, "/api/v1/dnc/+14155552671", "bob", nil, nil); code != http.StatusOK {
+		t.Errorf("remove by bob = %d, want 200", code)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/dnc", "alice", nil, &list); code != http.StatusOK || len(list) != 0 {
+		t.Errorf("list of alice = %d %+v, want it empty", code, list)
+	}
+}
+
+func TestCallHistory(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var first, second model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &first)
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552672", "pathway_id": "pathway-2"}, &second)
+
+	var list model.CallListResponse
+	if code := request(t, r, http.MethodGet, "/api/v1/calls?pathway_id=pathway-2", "token", nil, &list); code != http.StatusOK || list.Total != 1 || list.Calls[0].CallID != second.CallID {
+		t.Errorf("calls of pathway-2 = %d %+v, want the second call", code, list)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/calls?limit=1&offset=1", "token", nil, &list); code != http.StatusOK || list.Total != 2 || len(list.Calls) != 1 {
+		t.Errorf("second page = %d %+v, want one of two calls", code, list)
+	}
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodGet, "/api/v1/calls?limit=0&start_date=yesterday", "token", nil, &response); code != http.StatusBadRequest || len(response.Fields) != 2 {
+		t.Errorf("invalid filters = %d %+v, want 400 with both fields", code, response)
+	}
+
+	if err := srv.CompleteCall(first.CallID); err != nil {
+		t.Fatal(err)
+	}
+	var detail model.CallDetail
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+first.CallID, "token", nil, &detail); code != http.StatusOK || !detail.Completed {
+		t.Fatalf("call details = %d %+v, want the completed call", code, detail)
+	}
+	srv.Fail(blandtest.RouteGetCall, blandtest.Failure{Status: http.StatusInternalServerError})
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+first.CallID, "token", nil, &detail); code != http.StatusOK || detail.CallID != first.CallID {
+		t.Errorf("cached call details = %d %+v, want them served without Bland", code, detail)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/calls?status=completed", "token", nil, &list); code != http.StatusOK || list.Total != 1 || list.Calls[0].Detail != nil {
+		t.Errorf("completed calls = %d %+v, want the first call without its detail", code, list)
+	}
+}
+
+func TestBlandWebhook(t *testing.T) {
+	r, _ := newTestRouter(t, func(cfg *config.Config) { cfg.Webhooks.BlandSecret = "secret" })
+	var sent model.CallResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent); code != http.StatusOK {
+		t.Fatalf("send call = %d", code)
+	}
+
+	body := []byte(`{"call_id":"` + sent.CallID + `","status":"completed","completed":true,"to":"+14155552671"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	for _, tt := range []struct {
+		signature string
+		want      int
+	}{
+		{"", http.StatusUnauthorized},
+		{hex.EncodeToString([]byte("forged")), http.StatusUnauthorized},
+		{"sha256=" + hex.EncodeToString(mac.Sum(nil)), http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/bland", bytes.NewReader(body))
+		req.Header.Set(controller.BlandSignatureHeader, tt.signature)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("callback signed %q = %d, want %d", tt.signature, w.Code, tt.want)
+		}
+	}
+
+	var list model.CallListResponse
+	if code := request(t, r, http.MethodGet, "/api/v1/calls?status=completed", "token", nil, &list); code != http.StatusOK || list.Total != 1 {
+		t.Errorf("calls = %d %+v, want the call completed by the callback", code, list)
+	}
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var response model.ErrorResponse
+	invalid := map[string]interface{}{"url": "https://crm.example.com/hooks", "events": []string{"call.unknown"}, "secret": "0123456789abcdef"}
+	if code := request(t, r, http.MethodPost, "/api/v1/webhooks/subscriptions", "token", invalid, &response); code != http.StatusBadRequest {
+		t.Errorf("unknown event type = %d %+v, want 400", code, response)
+	}
+	for _, url := range []string{"http://crm.example.com/hooks", "https://169.254.169.254/latest/meta-data"} {
+		private := map[string]interface{}{"url": url, "events": []string{"pathway.deleted"}, "secret": "0123456789abcdef"}
+		if code := request(t, r, http.MethodPost, "/api/v1/webhooks/subscriptions", "token", private, &response); code != http.StatusBadRequest || len(response.Fields) != 1 || response.Fields[0].Field != "url" {
+			t.Errorf("subscribe to %s = %d %+v, want 400 on url", url, code, response)
+		}
+	}
+	var subscription model.WebhookSubscription
+	subscribe := map[string]interface{}{"url": "https://crm.example.com/hooks", "events": []string{"pathway.deleted"}, "secret": "0123456789abcdef"}
+	if code := request(t, r, http.MethodPost, "/api/v1/webhooks/subscriptions", "token", subscribe, &subscription); code != http.StatusCreated {
+		t.Fatalf("subscribe = %d", code)
+	}
+	var subscriptions []model.WebhookSubscription
+	if code := request(t, r, http.MethodGet, "/api/v1/webhooks/subscriptions", "token", nil, &subscriptions); code != http.StatusOK || len(subscriptions) != 1 {
+		t.Errorf("subscriptions = %d %+v, want the subscription", code, subscriptions)
+	}
+
+	pathwayID := srv.AddPathway(model.GetPathwayResponse{Name: "Support"})
+	if code := request(t, r, http.MethodDelete, "/api/v1/delete/convo_pathway/"+pathwayID, "token", nil, nil); code != http.StatusOK {
+		t.Fatalf("delete pathway = %d", code)
+	}
+	var deliveries []model.WebhookDelivery
+	if code := request(t, r, http.MethodGet, "/api/v1/webhooks/deliveries?status=pending", "token", nil, &deliveries); code != http.StatusOK || len(deliveries) != 1 || deliveries[0].Event.Type != "pathway.deleted" {
+		t.Fatalf("pending deliveries = %d %+v, want the pathway.deleted event", code, deliveries)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/webhooks/deliveries/"+deliveries[0].ID+"/redeliver", "token", nil, &response); code != http.StatusConflict {
+		t.Errorf("redeliver pending = %d %+v, want 409", code, response)
+	}
+
+	if code := request(t, r, http.MethodDelete, "/api/v1/webhooks/subscriptions/"+subscription.ID, "token", nil, nil); code != http.StatusOK {
+		t.Errorf("unsubscribe = %d", code)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/webhooks/dead-letters", "token", nil, &deliveries); code != http.StatusOK || len(deliveries) != 0 {
+		t.Errorf("dead letters = %d %+v, want none before the delivery is attempted", code, deliveries)
+	}
+	if code := request(t, r, http.MethodDelete, "/api/v1/webhooks/subscriptions/"+subscription.ID, "token", nil, nil); code != http.StatusNotFound {
+		t.Errorf("second unsubscribe = %d, want 404", code)
+	}
+}
+
+func TestWaitForCall(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var sent model.CallResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent); code != http.StatusOK {
+		t.Fatalf("send call = %d", code)
+	}
+	if err := srv.CompleteCall(sent.CallID); err != nil {
+		t.Fatal(err)
+	}
+	var detail model.CallDetail
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+sent.CallID+"/wait?timeout=5", "token", nil, &detail); code != http.StatusOK || !detail.Completed {
+		t.Errorf("wait = %d %+v, want the completed call", code, detail)
+	}
+
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+sent.CallID+"/wait?timeout=0", "token", nil, &response); code != http.StatusBadRequest || response.Fields[0].Field != "timeout" {
+		t.Errorf("wait with timeout 0 = %d %+v, want 400 on timeout", code, response)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/call-unknown/wait?timeout=5", "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("wait for an unknown call = %d %+v, want 404", code, response)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var sent model.CallResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent); code != http.StatusOK {
+		t.Fatalf("send call = %d", code)
+	}
+	if err := srv.CompleteCall(sent.CallID, model.Transcript{ID: 1, User: "assistant", Text: "Hello"}, model.Transcript{ID: 2, User: "user", Text: "Hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query, accept string
+		status        int
+		contentType   string
+	}{
+		{"", "", http.StatusOK, "text/plain; charset=utf-8"},
+		{"?format=vtt", "", http.StatusOK, "text/vtt; charset=utf-8"},
+		{"", "text/csv", http.StatusOK, "text/csv; charset=utf-8"},
+		{"?format=pdf", "", http.StatusBadRequest, "application/json; charset=utf-8"},
+		{"", "application/pdf", http.StatusNotAcceptable, "application/json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/calls/"+sent.CallID+"/transcript"+tt.query, nil)
+		req.Header.Set("Authorization", "token")
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("transcript%s with Accept %q = %d %s, want %d %s", tt.query, tt.accept, w.Code, w.Header().Get("Content-Type"), tt.status, tt.contentType)
+		}
+		if w.Code == http.StatusOK && !strings.Contains(w.Body.String(), "Hello") {
+			t.Errorf("transcript%s = %q, want the assistant's line", tt.query, w.Body.String())
+		}
+	}
+}
+
+func TestRecording(t *testing.T) {
+	r, srv := newTestRouter(t)
+	srv.Recording = []byte("ID3 recording")
+
+	var recorded, unrecorded model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]interface{}{"phone_number": "+14155552671", "pathway_id": "pathway-1", "record": true}, &recorded)
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552672", "pathway_id": "pathway-1"}, &unrecorded)
+	for _, callID := range []string{recorded.CallID, unrecorded.CallID} {
+		if err := srv.CompleteCall(callID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for rangeHeader, want := range map[string]struct {
+		status int
+		body   string
+	}{
+		"":          {http.StatusOK, "ID3 recording"},
+		"bytes=0-2": {http.StatusPartialContent, "ID3"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/calls/"+recorded.CallID+"/recording", nil)
+		req.Header.Set("Authorization", "token")
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want.status || w.Body.String() != want.body || w.Header().Get("Content-Type") != "audio/mpeg" {
+			t.Errorf("recording with Range %q = %d %q (%s), want %d %q", rangeHeader, w.Code, w.Body.String(), w.Header().Get("Content-Type"), want.status, want.body)
+		}
+	}
+
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+unrecorded.CallID+"/recording", "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("recording of an unrecorded call = %d %+v, want 404", code, response)
+	}
+}
+
+func TestRecordingArchive(t *testing.T) {
+	archiveDir := t.TempDir()
+	ctl, srv := newTestController(t, func(cfg *config.Config) {
+		cfg.Recordings.ArchiveDir = archiveDir
+		cfg.Recordings.ArchiveToken = "archive-key"
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	ctl.Start(ctx)
+	r := setupRouter(ctl)
+	srv.Recording = []byte("ID3 recording")
+
+	var sent model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]interface{}{"phone_number": "+14155552671", "pathway_id": "pathway-1", "record": true}, &sent)
+	if err := srv.CompleteCall(sent.CallID); err != nil {
+		t.Fatal(err)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+sent.CallID, "token", nil, nil); code != http.StatusOK {
+		t.Fatalf("get call = %d", code)
+	}
+
+	// The archive worker downloads the recording of the completed call
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if entries, _ := os.ReadDir(archiveDir); len(entries) == 1 && entries[0].Name() == sent.CallID+".mp3" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the recording was not archived")
+		}
+	}
+	downloads := func() int {
+		n := 0
+		for _, req := range srv.Requests() {
+			if req.Route == blandtest.RouteGetRecording {
+				n++
+			}
+		}
+		return n
+	}
+	before := downloads()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/calls/"+sent.CallID+"/recording", nil)
+	req.Header.Set("Authorization", "token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "ID3 recording" || downloads() != before {
+		t.Errorf("recording = %d %q, want the archived recording without a new download", w.Code, w.Body.String())
+	}
+}
+
+func TestAnalysisSchemas(t *testing.T) {
+	r, srv := newTestRouter(t)
+	srv.AnalysisAnswers = []string{"Yes", "about five thousand"}
+
+	questions := []map[string]string{
+		{"name": "interested", "question": "Is the customer interested?", "type": "boolean"},
+		{"name": "budget", "question": "What is the budget?", "type": "number"},
+	}
+	var response model.ErrorResponse
+	invalid := map[string]interface{}{"name": "Leads", "goal": "Qualify", "questions": []map[string]string{{"name": "interested", "type": "date"}}}
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/schemas", "token", invalid, &response); code != http.StatusBadRequest || len(response.Fields) != 2 {
+		t.Errorf("invalid schema = %d %+v, want 400 on the question and the type", code, response)
+	}
+	var schema model.AnalysisSchema
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/schemas", "token", map[string]interface{}{"name": "Leads", "goal": "Qualify", "questions": questions}, &schema); code != http.StatusCreated {
+		t.Fatalf("create schema = %d", code)
+	}
+	if code := request(t, r, http.MethodPut, "/api/v1/analysis/schemas/"+schema.ID, "token", map[string]interface{}{"name": "Sales leads", "goal": "Qualify", "questions": questions}, &schema); code != http.StatusOK || schema.Name != "Sales leads" {
+		t.Errorf("update schema = %d %+v", code, schema)
+	}
+	var schemas []model.AnalysisSchema
+	if code := request(t, r, http.MethodGet, "/api/v1/analysis/schemas", "token", nil, &schemas); code != http.StatusOK || len(schemas) != 1 {
+		t.Errorf("list schemas = %d %+v, want the schema", code, schemas)
+	}
+
+	var sent model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent)
+	var result model.AnalysisResult
+	if code := request(t, r, http.MethodPost, "/api/v1/call/"+sent.CallID+"/analyze/"+schema.ID, "token", nil, &result); code != http.StatusOK {
+		t.Fatalf("analyze = %d", code)
+	}
+	if result.Answers["interested"] != true || result.Answers["budget"] != nil || result.Valid || len(result.Issues) != 1 || result.Issues[0].Code != "invalid_number" {
+		t.Errorf("result = %+v, want a typed boolean and an invalid number", result)
+	}
+
+	if code := request(t, r, http.MethodDelete, "/api/v1/analysis/schemas/"+schema.ID, "token", nil, nil); code != http.StatusOK {
+		t.Errorf("delete schema = %d", code)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/analysis/schemas/"+schema.ID, "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("deleted schema = %d %+v, want 404", code, response)
+	}
+}
+
+func TestAutoAnalysisRules(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/rules", "token", map[string]string{"pathway_id": "pathway-1"}, &response); code != http.StatusBadRequest {
+		t.Errorf("rule without schema or questions = %d %+v, want 400", code, response)
+	}
+	var rule model.AutoAnalysisRule
+	create := map[string]interface{}{"pathway_id": "pathway-1", "goal": "Qualify", "questions": [][]string{{"Is the customer interested?", "boolean"}}}
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/rules", "token", create, &rule); code != http.StatusCreated || !rule.Enabled {
+		t.Fatalf("create rule = %d %+v, want an enabled rule", code, rule)
+	}
+	var rules []model.AutoAnalysisRule
+	if code := request(t, r, http.MethodGet, "/api/v1/analysis/rules?pathway_id=pathway-1", "token", nil, &rules); code != http.StatusOK || len(rules) != 1 {
+		t.Errorf("rules of pathway-1 = %d %+v, want the rule", code, rules)
+	}
+
+	var sent model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent)
+	if err := srv.CompleteCall(sent.CallID); err != nil {
+		t.Fatal(err)
+	}
+	var detail model.CallDetail
+	for deadline := time.Now().Add(5 * time.Second); len(detail.Analyses) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the call was not analyzed")
+		}
+		if code := request(t, r, http.MethodGet, "/api/v1/calls/"+sent.CallID, "token", nil, &detail); code != http.StatusOK {
+			t.Fatalf("call details = %d", code)
+		}
+	}
+	if analysis := detail.Analyses[0]; analysis.RuleID != rule.ID || analysis.Response == nil || analysis.Error != nil {
+		t.Errorf("analysis = %+v, want the answers of the rule", analysis)
+	}
+
+	disabled := map[string]interface{}{"pathway_id": "pathway-1", "goal": "Qualify", "questions": [][]string{{"Is the customer interested?", "boolean"}}, "enabled": false}
+	if code := request(t, r, http.MethodPut, "/api/v1/analysis/rules/"+rule.ID, "token", disabled, &rule); code != http.StatusOK || rule.Enabled {
+		t.Errorf("disable rule = %d %+v", code, rule)
+	}
+	if code := request(t, r, http.MethodDelete, "/api/v1/analysis/rules/"+rule.ID, "token", nil, nil); code != http.StatusOK {
+		t.Errorf("delete rule = %d", code)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/analysis/rules/"+rule.ID, "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("deleted rule = %d %+v, want 404", code, response)
+	}
+}
+
+func TestAnalysisJobs(t *testing.T) {
+	r, srv := newTestRouter(t, func(cfg *config.Config) { cfg.Analysis.RequestsPerMinute = 6000 })
+
+	var first, second model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &first)
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552672", "pathway_id": "pathway-1"}, &second)
+	if err := srv.CompleteCall(first.CallID); err != nil {
+		t.Fatal(err)
+	}
+	request(t, r, http.MethodGet, "/api/v1/calls/"+first.CallID, "token", nil, nil)
+
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/jobs", "token", map[string]interface{}{"filter": map[string]string{"pathway_id": "pathway-2"}, "goal": "Qualify", "questions": [][]string{{"Interested?", "boolean"}}}, &response); code != http.StatusBadRequest {
+		t.Errorf("job without matching calls = %d %+v, want 400", code, response)
+	}
+	var job model.AnalysisJob
+	start := map[string]interface{}{"call_ids": []string{"call-unknown"}, "filter": map[string]string{"pathway_id": "pathway-1"}, "goal": "Qualify", "questions": [][]string{{"Interested?", "boolean"}}}
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/jobs", "token", start, &job); code != http.StatusAccepted || job.Total != 2 {
+		t.Fatalf("start job = %d %+v, want the unknown call and the completed call", code, job)
+	}
+	for deadline := time.Now().Add(5 * time.Second); job.Status != model.AnalysisJobCompleted; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("job still %s", job.Status)
+		}
+		if code := request(t, r, http.MethodGet, "/api/v1/analysis/jobs/"+job.JobID, "token", nil, &job); code != http.StatusOK {
+			t.Fatalf("get job = %d", code)
+		}
+	}
+	if job.Analyzed != 1 || job.Failed != 1 || job.Results[1].CallID != first.CallID || job.Results[1].Status != model.AnalysisJobCallAnalyzed {
+		t.Errorf("job = %+v, want the completed call analyzed and the unknown one failed", job)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analysis/jobs/"+job.JobID+"/results?format=csv", nil)
+	req.Header.Set("Authorization", "token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil || w.Code != http.StatusOK || len(rows) != 3 {
+		t.Errorf("CSV results = %d %v (%v), want a header and a row per call", w.Code, rows, err)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/analysis/jobs/ajob-unknown/results", "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("results of an unknown job = %d %+v, want 404", code, response)
+	}
+}
+
+func TestStopCalls(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var sent model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "token", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent)
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/"+sent.CallID+"/stop", "token", nil, nil); code != http.StatusOK {
+		t.Errorf("stop call = %d", code)
+	}
+	if detail, _ := srv.Call(sent.CallID); !detail.Completed || detail.CallEndedBy != "API" {
+		t.Errorf("call after stop = %+v, want it ended by the API", detail)
+	}
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/"+sent.CallID+"/stop", "token", nil, &response); code != http.StatusBadRequest {
+		t.Errorf("second stop = %d %+v, want 400 from Bland", code, response)
+	}
+
+	var batch model.Batch
+	contacts := []map[string]string{{"phone_number": "+14155552672"}, {"phone_number": "+14155552673"}}
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch", "token", map[string]interface{}{"pathway_id": "pathway-1", "contacts": contacts}, &batch); code != http.StatusAccepted {
+		t.Fatalf("dispatch batch = %d", code)
+	}
+	for deadline := time.Now().Add(5 * time.Second); batch.Status != model.BatchStatusCompleted; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch still %s", batch.Status)
+		}
+		request(t, r, http.MethodGet, "/api/v1/calls/batch/"+batch.BatchID, "token", nil, &batch)
+	}
+	var stopped model.StopBatchResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch/"+batch.BatchID+"/stop", "token", nil, &stopped); code != http.StatusOK || stopped.Stopped != 2 || stopped.Cancelled != 0 {
+		t.Errorf("stop batch = %d %+v, want both calls stopped", code, stopped)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch/batch-unknown/stop", "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("stop an unknown batch = %d %+v, want 404", code, response)
+	}
+}
+
+func TestValidatePathway(t *testing.T) {
+	r, srv := newTestRouter(t)
+	pathwayID := srv.AddPathway(model.GetPathwayResponse{Name: "Support"})
+
+	valid := map[string]interface{}{
+		"nodes": []map[string]interface{}{
+			{"id": "1", "type": "Default", "data": map[string]interface{}{"name": "Start", "isStart": true, "prompt": "Hello"}},
+			{"id": "2", "type": "End Call", "data": map[string]interface{}{"name": "Bye", "prompt": "Goodbye"}},
+		},
+		"edges": []map[string]string{{"id": "e1", "source": "1", "target": "2"}},
+	}
+	invalid := map[string]interface{}{
+		"nodes": []map[string]interface{}{
+			{"id": "1", "type": "Default", "data": map[string]interface{}{"name": "Start", "isStart": true, "prompt": "Hello"}},
+		},
+		"edges": []map[string]string{{"id": "e1", "source": "1", "target": "missing"}},
+	}
+
+	var validation model.PathwayValidation
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/validate", "token", valid, &validation); code != http.StatusOK || !validation.Valid {
+		t.Errorf("validate a valid pathway = %d %+v", code, validation)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/validate", "token", invalid, &validation); code != http.StatusOK || validation.Valid || len(validation.Issues) == 0 {
+		t.Errorf("validate an invalid pathway = %d %+v, want issues", code, validation)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("validation sent %d requests to Bland", len(srv.Requests()))
+	}
+
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/pathway/update/"+pathwayID, "token", invalid, &response); code != http.StatusBadRequest || len(response.Fields) == 0 {
+		t.Errorf("invalid update = %d %+v, want 400 with the issues", code, response)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathway/update/"+pathwayID+"?force=true", "token", invalid, nil); code != http.StatusOK {
+		t.Errorf("forced invalid update = %d, want 200", code)
+	}
+}
+
+func TestPathwayDiff(t *testing.T) {
+	r, srv := newTestRouter(t)
+	prompt, changed := "Hello", "Hello there"
+	current := srv.AddPathway(model.GetPathwayResponse{Name: "Support", Nodes: []model.Node{{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &changed}}}})
+	other := srv.AddPathway(model.GetPathwayResponse{Name: "Support", Nodes: []model.Node{{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}}}})
+
+	var diff model.PathwayDiff
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+current+"/diff?other_pathway_id="+other, "token", nil, &diff); code != http.StatusOK || diff.Identical || len(diff.Nodes) != 1 || diff.Against != "pathway" {
+		t.Errorf("diff with a pathway = %d %+v, want the changed prompt", code, diff)
+	}
+	body := model.GetPathwayResponse{Name: "Support", Nodes: []model.Node{{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &changed}}}}
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+current+"/diff", "token", body, &diff); code != http.StatusOK || !diff.Identical || diff.Against != "body" {
+		t.Errorf("diff with an identical body = %d %+v, want identical", code, diff)
+	}
+
+	var response model.ErrorResponse
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+current+"/diff", "token", nil, &response); code != http.StatusBadRequest {
+		t.Errorf("diff with nothing = %d %+v, want 400", code, response)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/pathway-unknown/diff?other_pathway_id="+other, "token", nil, &response); code != http.StatusNotFound {
+		t.Errorf("diff of an unknown pathway = %d %+v, want 404", code, response)
+	}
+}
+
+func TestPathwaySnapshots(t *testing.T) {
+	r, srv := newTestRouter(t)
+	prompt := "v1"
+	pathwayID := srv.AddPathway(model.GetPathwayResponse{Name: "Support", Nodes: []model.Node{
+		{ID: "1", Type: "End Call", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}},
+	}})
+	update := map[string]interface{}{"name": "Support", "nodes": []map[string]interface{}{
+		{"id": "1", "type": "End Call", "data": map[string]interface{}{"name": "Start", "isStart": true, "prompt": "v2"}},
+	}}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathway/update/"+pathwayID, "token", update, nil); code != http.StatusOK {
+		t.Fatalf("update = %d", code)
+	}
+
+	var snapshots []model.PathwaySnapshot
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/snapshots", "token", nil, &snapshots); code != http.StatusOK || len(snapshots) != 1 {
+		t.Fatalf("snapshots = %d %+v, want the one taken before the update", code, snapshots)
+	}
+	snapshotID := snapshots[0].SnapshotID
+	var snapshot model.PathwaySnapshot
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/snapshots/"+snapshotID, "token", nil, &snapshot); code != http.StatusOK || snapshot.Pathway == nil || *snapshot.Pathway.Nodes[0].Data.Prompt != "v1" {
+		t.Fatalf("snapshot = %d %+v, want the pathway before the update", code, snapshot)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/snapshots/snap-unknown", "token", nil, nil); code != http.StatusNotFound {
+		t.Errorf("unknown snapshot = %d, want 404", code)
+	}
+
+	var restored model.RestorePathwayResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/"+pathwayID+"/snapshots/"+snapshotID+"/restore", "token", nil, &restored); code != http.StatusOK || restored.Recreated {
+		t.Fatalf("restore = %d %+v", code, restored)
+	}
+	if p, _ := srv.Pathway(pathwayID); *p.Nodes[0].Data.Prompt != "v1" {
+		t.Errorf("prompt = %s after the restore, want v1", *p.Nodes[0].Data.Prompt)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/snapshots", "token", nil, &snapshots); code != http.StatusOK || len(snapshots) != 2 {
+		t.Errorf("snapshots = %d %+v, want another one taken before the restore", code, snapshots)
+	}
+
+	if code := request(t, r, http.MethodDelete, "/api/v1/delete/convo_pathway/"+pathwayID, "token", nil, nil); code != http.StatusOK {
+		t.Fatalf("delete = %d", code)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/"+pathwayID+"/snapshots/"+snapshotID+"/restore", "token", nil, &restored); code != http.StatusOK || !restored.Recreated || restored.PathwayID == pathwayID {
+		t.Fatalf("restore of the deleted pathway = %d %+v, want it re-created", code, restored)
+	}
+	if p, ok := srv.Pathway(restored.PathwayID); !ok || *p.Nodes[0].Data.Prompt != "v1" {
+		t.Errorf("re-created pathway = %+v, want prompt v1", p)
+	}
+}
+
+func TestClonePathway(t *testing.T) {
+	r, srv := newTestRouter(t)
+	prompt := "Hi"
+	sourceID := srv.AddPathway(model.GetPathwayResponse{Name: "Support",
+		Nodes: []model.Node{
+			{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}},
+			{ID: "2", Type: "End Call", Data: model.NodeData{Name: "Goodbye", Prompt: &prompt}},
+		},
+		Edges: []model.Edge{{ID: "e1", Source: "1", Target: "2"}},
+	})
+	var folder model.CreateFolderData
+	if code := request(t, r, http.MethodPost, "/api/v1/folders", "token", map[string]string{"name": "Billing"}, &folder); code != http.StatusOK {
+		t.Fatalf("create folder = %d", code)
+	}
+
+	var clone model.ClonePathwayResponse
+	body := map[string]string{"name": "Support (copy)", "folder_id": folder.FolderID}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/"+sourceID+"/clone", "token", body, &clone); code != http.StatusCreated {
+		t.Fatalf("clone = %d", code)
+	}
+	if clone.PathwayID == sourceID || clone.SourcePathwayID != sourceID || clone.CrossAccount || srv.PathwayFolder(clone.PathwayID) != folder.FolderID {
+		t.Errorf("clone = %+v, want a new pathway in folder %s", clone, folder.FolderID)
+	}
+	p, _ := srv.Pathway(clone.PathwayID)
+	if p.Name != "Support (copy)" || len(p.Nodes) != 2 || len(p.Edges) != 1 ||
+		p.Edges[0].Source != clone.NodeIDs["1"] || p.Edges[0].Target != clone.NodeIDs["2"] {
+		t.Errorf("cloned pathway = %+v, want the nodes and edges of the source with new IDs %v", p, clone.NodeIDs)
+	}
+
+	data, _ := json.Marshal(map[string]string{"name": "Support (other account)"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pathways/"+sourceID+"/clone", bytes.NewReader(data))
+	req.Header.Set("Authorization", "token")
+	req.Header.Set("X-Target-Authorization", "other-token")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &clone); err != nil || w.Code != http.StatusCreated || !clone.CrossAccount {
+		t.Fatalf("clone to another account = %d %s", w.Code, w.Body.String())
+	}
+	requests := srv.Requests()
+	if last := requests[len(requests)-1]; last.Authorization != "other-token" {
+		t.Errorf("copy finished with token %q, want other-token", last.Authorization)
+	}
+
+	before := len(srv.Requests())
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/"+sourceID+"/clone", "token", map[string]string{"name": "Lost", "folder_id": "folder-unknown"}, nil); code != http.StatusNotFound {
+		t.Errorf("clone into an unknown folder = %d, want 404", code)
+	}
+	var move model.MovePathwayRequest
+	for _, req := range srv.Requests()[before:] {
+		if req.Route == blandtest.RouteMovePathway {
+			json.Unmarshal(req.Body, &move)
+		}
+	}
+	if _, ok := srv.Pathway(move.PathwayID); move.PathwayID == "" || ok {
+		t.Errorf("copy %q is left behind in the root folder", move.PathwayID)
+	}
+
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/pathway-unknown/clone", "token", map[string]string{"name": "Copy"}, nil); code != http.StatusNotFound {
+		t.Errorf("clone of an unknown pathway = %d, want 404", code)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/"+sourceID+"/clone", "token", map[string]string{}, nil); code != http.StatusBadRequest {
+		t.Errorf("clone without a name = %d, want 400", code)
+	}
+}
+
+func TestCallHistoryBelongsToItsToken(t *testing.T) {
+	r, _ := newTestRouter(t)
+
+	var sent model.CallResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/call", "alice", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &sent); code != http.StatusOK {
+		t.Fatalf("send call = %d", code)
+	}
+	for token, want := range map[string]int{"alice": 1, "bob": 0} {
+		var list model.CallListResponse
+		if code := request(t, r, http.MethodGet, "/api/v1/calls", token, nil, &list); code != http.StatusOK || list.Total != want {
+			t.Errorf("calls of %s = %d %+v, want %d", token, code, list, want)
+		}
+	}
+}
+
+func TestSnapshotsBelongToTheirToken(t *testing.T) {
+	r, srv := newTestRouter(t)
+	prompt := "v1"
+	pathwayID := srv.AddPathway(model.GetPathwayResponse{Name: "Support", Nodes: []model.Node{
+		{ID: "1", Type: "End Call", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}},
+	}})
+	update := map[string]interface{}{"name": "Support", "nodes": []map[string]interface{}{
+		{"id": "1", "type": "End Call", "data": map[string]interface{}{"name": "Start", "isStart": true, "prompt": "v2"}},
+	}}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathway/update/"+pathwayID, "alice", update, nil); code != http.StatusOK {
+		t.Fatalf("update = %d", code)
+	}
+
+	var snapshots []model.PathwaySnapshot
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/snapshots", "alice", nil, &snapshots); code != http.StatusOK || len(snapshots) != 1 {
+		t.Fatalf("snapshots of alice = %d %+v, want one", code, snapshots)
+	}
+	snapshotID := snapshots[0].SnapshotID
+	var diff model.PathwayDiff
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/diff?snapshot_id="+snapshotID, "alice", nil, &diff); code != http.StatusOK || diff.Identical {
+		t.Errorf("diff of alice = %d %+v, want the prompt change", code, diff)
+	}
+
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayID+"/snapshots", "bob", nil, &snapshots); code != http.StatusOK || len(snapshots) != 0 {
+		t.Errorf("snapshots of bob = %d %+v, want none", code, snapshots)
+	}
+	for _, tt := range []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/pathways/" + pathwayID + "/snapshots/" + snapshotID},
+		{http.MethodPost, "/api/v1/pathways/" + pathwayID + "/snapshots/" + snapshotID + "/restore"},
+		{http.MethodGet, "/api/v1/pathways/" + pathwayID + "/diff?snapshot_id=" + snapshotID},
+	} {
+		if code := request(t, r, tt.method, tt.path, "bob", nil, nil); code != http.StatusNotFound {
+			t.Errorf("%s %s by bob = %d, want 404", tt.method, tt.path, code)
+		}
+	}
+	if p, _ := srv.Pathway(pathwayID); *p.Nodes[0].Data.Prompt != "v2" {
+		t.Errorf("prompt = %s after bob's restore, want v2", *p.Nodes[0].Data.Prompt)
+	}
+}
+
+func TestStopBatchOfAnotherToken(t *testing.T) {
+	r, _ := newTestRouter(t)
+
+	contacts := []map[string]string{}
+	for i := 0; i < 3; i++ {
+		contacts = append(contacts, map[string]string{"phone_number": fmt.Sprintf("+1415555267%d", i)})
+	}
+	var batch model.Batch
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch", "alice", map[string]interface{}{"pathway_id": "pathway-1", "contacts": contacts}, &batch); code != http.StatusAccepted && code != http.StatusOK {
+		t.Fatalf("dispatch batch = %d", code)
+	}
+
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch/"+batch.BatchID+"/stop", "bob", nil, nil); code != http.StatusNotFound {
+		t.Errorf("stop by bob = %d, want 404", code)
+	}
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/batch/"+batch.BatchID, "alice", nil, &batch); code != http.StatusOK || batch.Status == model.BatchStatusStopped || batch.Cancelled != 0 {
+		t.Errorf("batch after bob's stop = %d %+v, want it untouched", code, batch)
+	}
+	if code := request(t, r, http.MethodPost, "/api/v1/calls/batch/"+batch.BatchID+"/stop", "alice", nil, nil); code != http.StatusOK {
+		t.Errorf("stop by alice = %d, want 200", code)
+	}
+}
+
+func TestExportImportKeepsPathways(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	var doc model.PathwayDocument
+	source := `{"version":1,"name":"Outbound","folder_path":"Sales/Q1\\/Q2","nodes":[` +
+		`{"id":"1","type":"Default","position":{"x":10,"y":20},"data":{"name":"Start","isStart":true,"prompt":"Hi","kb":"kb-1","modelOptions":{"temperature":0.3,"interruptionThreshold":500}}},` +
+		`{"id":"2","type":"End Call","data":{"name":"Goodbye","text":"Thanks for calling!"}}],` +
+		`"edges":[{"id":"e1","source":"1","target":"2","label":"done","data":{"isHighlighted":false}}]}`
+	if err := json.Unmarshal([]byte(source), &doc); err != nil {
+		t.Fatal(err)
+	}
+	var first model.ImportPathwayResponse
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/import", "token", doc, &first); code != http.StatusCreated {
+		t.Fatalf("import = %d", code)
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pathways/"+first.PathwayID+"/export?format="+format, nil)
+			req.Header.Set("Authorization", "token")
+			exported := httptest.NewRecorder()
+			if r.ServeHTTP(exported, req); exported.Code != http.StatusOK {
+				t.Fatalf("export = %d %s", exported.Code, exported.Body.String())
+			}
+			exportedDoc, err := pathway.Unmarshal(exported.Body.Bytes(), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := `Sales/Q1\/Q2`; exportedDoc.FolderPath != want {
+				t.Errorf("folder path = %s, want %s", exportedDoc.FolderPath, want)
+			}
+
+			req = httptest.NewRequest(http.MethodPost, "/api/v1/pathways/import", bytes.NewReader(exported.Body.Bytes()))
+			req.Header.Set("Authorization", "token")
+			req.Header.Set("Content-Type", "application/"+format)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			var imported model.ImportPathwayResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &imported); err != nil || w.Code != http.StatusCreated {
+				t.Fatalf("import = %d %s", w.Code, w.Body.String())
+			}
+			if imported.FolderID != first.FolderID {
+				t.Errorf("imported into folder %s, want the existing folder %s", imported.FolderID, first.FolderID)
+			}
+
+			p, _ := srv.Pathway(imported.PathwayID)
+			start, end, edge := p.Nodes[0], p.Nodes[1], p.Edges[0]
+			if string(start.Extra["position"]) != `{"x":10,"y":20}` || string(start.Data.Extra["kb"]) != `"kb-1"` ||
+				string(start.Data.ModelOptions.Extra["interruptionThreshold"]) != "500" || string(edge.Extra["data"]) != `{"isHighlighted":false}` {
+				t.Errorf("unmodelled fields were not kept: %+v %+v", start, edge)
+			}
+			if end.Data.Text == nil || *end.Data.Text != "Thanks for calling!" {
+				t.Errorf("text of the End Call node = %v, want Thanks for calling!", end.Data.Text)
+			}
+			if edge.Source != imported.NodeIDs[exportedDoc.Nodes[0].ID] || edge.Target != imported.NodeIDs[exportedDoc.Nodes[1].ID] {
+				t.Errorf("edge %s -> %s does not follow the new node IDs %v", edge.Source, edge.Target, imported.NodeIDs)
+			}
+		})
+	}
+}
+
+func TestExportFindsTheFolderAmongMany(t *testing.T) {
+	r, srv := newTestRouter(t)
+
+	prompt := "Hi"
+	var pathwayIDs []string
+	for i := 0; i < 10; i++ {
+		doc := model.PathwayDocument{Version: 1, Name: "Outbound", FolderPath: fmt.Sprintf("Team %d", i), Nodes: []model.Node{
+			{ID: "1", Type: "End Call", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}},
+		}}
+		var imported model.ImportPathwayResponse
+		if code := request(t, r, http.MethodPost, "/api/v1/pathways/import", "token", doc, &imported); code != http.StatusCreated {
+			t.Fatalf("import = %d", code)
+		}
+		pathwayIDs = append(pathwayIDs, imported.PathwayID)
+	}
+
+	for _, i := range []int{0, 7, 9} {
+		var doc model.PathwayDocument
+		if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayIDs[i]+"/export", "token", nil, &doc); code != http.StatusOK || doc.FolderPath != fmt.Sprintf("Team %d", i) {
+			t.Errorf("export of the pathway in Team %d = %d, folder path %q", i, code, doc.FolderPath)
+		}
+	}
+
+	var response model.ErrorResponse
+	srv.Fail(blandtest.RouteFolderPathways, blandtest.Failure{Status: http.StatusServiceUnavailable, Body: `{"message":"Service unavailable"}`})
+	if code := request(t, r, http.MethodGet, "/api/v1/pathways/"+pathwayIDs[7]+"/export", "token", nil, &response); code != http.StatusBadGateway {
+		t.Errorf("export while folders cannot be listed = %d %+v, want 502", code, response)
+	}
+}
+
+func TestImportRemovesThePathwayWhenBlandRejectsIt(t *testing.T) {
+	r, srv := newTestRouter(t)
+	srv.Fail(blandtest.RouteUpdatePathway, blandtest.Failure{Status: http.StatusBadRequest, Body: `{"status":"error","message":"Invalid node"}`})
+
+	prompt := "Hi"
+	doc := model.PathwayDocument{Version: 1, Name: "Outbound", FolderPath: "Sales/Q1", Nodes: []model.Node{
+		{ID: "1", Type: "End Call", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}},
+	}}
+	if code := request(t, r, http.MethodPost, "/api/v1/pathways/import", "token", doc, nil); code != http.StatusBadRequest {
+		t.Fatalf("import = %d, want 400", code)
+	}
+	if folders := srv.Folders(); len(folders) != 0 {
+		t.Errorf("folders %+v created for the import are left behind", folders)
+	}
+
+	var deletedID string
+	for _, req := range srv.Requests() {
+		if req.Route == blandtest.RouteDeletePathway {
+			deletedID = strings.TrimPrefix(req.Path, "/v1/convo_pathway/")
+		}
+	}
+	if deletedID == "" {
+		t.Fatal("the pathway created for the import was not deleted")
+	}
+	if _, ok := srv.Pathway(deletedID); ok {
+		t.Errorf("pathway %s is left behind", deletedID)
+	}
+}
+
+func TestFailedMoveRemovesThePathway(t *testing.T) {
+	prompt := "Hi"
+	nodes := []model.Node{{ID: "1", Type: "End Call", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: &prompt}}}
+	for _, tt := range []struct {
+		name string
+		path func(srv *blandtest.Server) string
+		body interface{}
+	}{
+		{"create and move", func(*blandtest.Server) string { return "/api/v1/pathways/create-and-move?folder_id=folder-unknown" }, map[string]string{"name": "Outbound"}},
+		{"import", func(*blandtest.Server) string { return "/api/v1/pathways/import?folder_id=folder-unknown" }, model.PathwayDocument{Version: 1, Name: "Outbound", Nodes: nodes}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r, srv := newTestRouter(t)
+			if code := request(t, r, http.MethodPost, tt.path(srv), "token", tt.body, nil); code != http.StatusNotFound {
+				t.Fatalf("POST = %d, want 404 for the unknown folder", code)
+			}
+
+			var createdID string
+			for _, req := range srv.Requests() {
+				if req.Route == blandtest.RouteMovePathway {
+					var move model.MovePathwayRequest
+					if err := json.Unmarshal(req.Body, &move); err != nil {
+						t.Fatal(err)
+					}
+					createdID = move.PathwayID
+				}
+			}
+			if createdID == "" {
+				t.Fatal("no pathway was created")
+			}
+			if _, ok := srv.Pathway(createdID); ok {
+				t.Errorf("pathway %s is left behind in the root folder", createdID)
+			}
+		})
+	}
+}
+
+func TestAutoAnalysisRulesOnlyRunOnCallsOfTheirToken(t *testing.T) {
+	r, srv := newTestRouter(t)
+	create := map[string]interface{}{"pathway_id": "pathway-1", "goal": "Qualify", "questions": [][]string{{"Is the customer interested?", "boolean"}}}
+	if code := request(t, r, http.MethodPost, "/api/v1/analysis/rules", "alice", create, nil); code != http.StatusCreated {
+		t.Fatalf("create rule = %d", code)
+	}
+
+	var ofBob, ofAlice model.CallResponse
+	request(t, r, http.MethodPost, "/api/v1/call", "bob", map[string]string{"phone_number": "+14155552671", "pathway_id": "pathway-1"}, &ofBob)
+	request(t, r, http.MethodPost, "/api/v1/call", "alice", map[string]string{"phone_number": "+14155552672", "pathway_id": "pathway-1"}, &ofAlice)
+	for _, callID := range []string{ofBob.CallID, ofAlice.CallID} {
+		if err := srv.CompleteCall(callID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	request(t, r, http.MethodGet, "/api/v1/calls/"+ofBob.CallID, "bob", nil, nil)
+	var detail model.CallDetail
+	for deadline := time.Now().Add(5 * time.Second); len(detail.Analyses) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the call of alice was not analyzed")
+		}
+		request(t, r, http.MethodGet, "/api/v1/calls/"+ofAlice.CallID, "alice", nil, &detail)
+	}
+
+	var detailOfBob model.CallDetail
+	if code := request(t, r, http.MethodGet, "/api/v1/calls/"+ofBob.CallID, "bob", nil, &detailOfBob); code != http.StatusOK || len(detailOfBob.Analyses) != 0 {
+		t.Errorf("call of bob = %d %+v, want no analysis by the rule of alice", code, detailOfBob.Analyses)
+	}
+	for _, req := range srv.Requests() {
+		if req.Route == blandtest.RouteAnalyzeCall && strings.Contains(req.Path, ofBob.CallID) {
+			t.Errorf("call of bob was analyzed with token %s", req.Authorization)
+		}
+	}
+}
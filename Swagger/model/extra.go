@@ -0,0 +1,186 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ExtraFields holds the JSON fields of a Bland object that the proxy does not
+// model, such as the position of a node in the editor, so they survive a
+// round trip through the typed structs unchanged.
+type ExtraFields map[string]json.RawMessage
+
+// MarshalJSON writes the node with its unmodelled fields.
+func (n Node) MarshalJSON() ([]byte, error) {
+	type plain Node
+	return marshalWithExtra(plain(n), n.Extra)
+}
+
+// UnmarshalJSON reads the node and keeps its unmodelled fields in Extra.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	type plain Node
+	var p plain
+	extra, err := unmarshalWithExtra(data, &p)
+	if err != nil {
+		return err
+	}
+	*n = Node(p)
+	n.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes the node data with its unmodelled fields.
+func (d NodeData) MarshalJSON() ([]byte, error) {
+	type plain NodeData
+	return marshalWithExtra(plain(d), d.Extra)
+}
+
+// UnmarshalJSON reads the node data and keeps its unmodelled fields in Extra.
+func (d *NodeData) UnmarshalJSON(data []byte) error {
+	type plain NodeData
+	var p plain
+	extra, err := unmarshalWithExtra(data, &p)
+	if err != nil {
+		return err
+	}
+	*d = NodeData(p)
+	d.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes the model options with their unmodelled fields.
+func (o ModelOptions) MarshalJSON() ([]byte, error) {
+	type plain ModelOptions
+	return marshalWithExtra(plain(o), o.Extra)
+}
+
+// UnmarshalJSON reads the model options and keeps their unmodelled fields in Extra.
+func (o *ModelOptions) UnmarshalJSON(data []byte) error {
+	type plain ModelOptions
+	var p plain
+	extra, err := unmarshalWithExtra(data, &p)
+	if err != nil {
+		return err
+	}
+	*o = ModelOptions(p)
+	o.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes the edge with its unmodelled fields.
+func (e Edge) MarshalJSON() ([]byte, error) {
+	type plain Edge
+	return marshalWithExtra(plain(e), e.Extra)
+}
+
+// UnmarshalJSON reads the edge and keeps its unmodelled fields in Extra.
+func (e *Edge) UnmarshalJSON(data []byte) error {
+	type plain Edge
+	var p plain
+	extra, err := unmarshalWithExtra(data, &p)
+	if err != nil {
+		return err
+	}
+	*e = Edge(p)
+	e.Extra = extra
+	return nil
+}
+
+// marshalWithExtra encodes the struct v and appends the fields of extra that v
+// does not model, sorted by key.
+func marshalWithExtra(v interface{}, extra ExtraFields) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	names := fieldNames(reflect.TypeOf(v))
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if !names.has(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		if value := extra[key]; len(value) > 0 {
+			buf.Write(value)
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalWithExtra decodes data into the struct pointed to by v and returns
+// the fields of data that the struct does not model, or nil when there are none.
+func unmarshalWithExtra(data []byte, v interface{}) (ExtraFields, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var all ExtraFields
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	names := fieldNames(reflect.TypeOf(v).Elem())
+	for key := range all {
+		if names.has(key) {
+			delete(all, key)
+		}
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// jsonNames lists the JSON field names of a struct type.
+type jsonNames []string
+
+// has reports whether key is one of the names. Like encoding/json, it ignores
+// case, so a key that decoded into a field is never kept as an extra as well.
+func (names jsonNames) has(key string) bool {
+	for _, name := range names {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames returns the JSON names of the exported fields of struct type t.
+func fieldNames(t reflect.Type) jsonNames {
+	var names jsonNames
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPathwayJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "modelled fields only",
+			in:   `{"name":"Support","nodes":[{"id":"1","data":{"name":"Start","active":true,"prompt":"Hi","modelOptions":{"modelType":"smart","temperature":0.2,"skipUserResponse":false,"block_interruptions":false},"isStart":true},"type":"Default"}],"edges":[{"id":"e1","label":"next","source":"1","target":"2"}]}`,
+		},
+		{
+			name: "unmodelled node, data, model option and edge fields",
+			in: `{"name":"Support","nodes":[{"id":"1","data":{"name":"Start","active":true,"modelOptions":{"modelType":"smart","temperature":0.2,"skipUserResponse":false,"block_interruptions":false,"interruptionThreshold":500},` +
+				`"isStart":true,"extractVars":[["name","string","The caller's name"]],"kb":"kb-1","text":"Hello there"},"type":"Default","position":{"x":120.5,"y":-40},"width":320,"selected":false}],` +
+				`"edges":[{"id":"e1","label":"next","source":"1","target":"2","type":"custom","data":{"label":"next","isHighlighted":false},"animated":true}]}`,
+		},
+		{
+			name: "large numbers and nulls",
+			in:   `{"name":"Support","nodes":[{"id":"1","data":{"name":"Start","active":false,"modelOptions":{"modelType":"","temperature":0,"skipUserResponse":false,"block_interruptions":false},"isStart":false,"kbId":12345678901234567890,"webhook":null},"type":"Webhook"}],"edges":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pathway GetPathwayResponse
+			if err := json.Unmarshal([]byte(tt.in), &pathway); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			out, err := json.Marshal(pathway)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if !sameJSON(t, tt.in, string(out)) {
+				t.Errorf("round trip changed the pathway\n got: %s\nwant: %s", out, tt.in)
+			}
+		})
+	}
+}
+
+func TestExtraFieldsDoNotShadowModelledFields(t *testing.T) {
+	node := Node{ID: "1", Type: "Default", Extra: ExtraFields{"id": json.RawMessage(`"other"`), "position": json.RawMessage(`{"x":1}`)}}
+	out, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got := string(decoded["id"]); got != `"1"` {
+		t.Errorf("id = %s, want \"1\"", got)
+	}
+	if got := string(decoded["position"]); got != `{"x":1}` {
+		t.Errorf("position = %s, want {\"x\":1}", got)
+	}
+
+	var back Node
+	if err := json.Unmarshal([]byte(`{"ID":"2","type":"End"}`), &back); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if back.ID != "2" || back.Extra != nil {
+		t.Errorf("decoded %+v, want ID 2 and no extra fields", back)
+	}
+}
+
+// sameJSON reports whether a and b encode the same value, ignoring key order
+// and whitespace.
+func sameJSON(t *testing.T, a, b string) bool {
+	t.Helper()
+	var va, vb interface{}
+	da := json.NewDecoder(bytes.NewReader([]byte(a)))
+	da.UseNumber()
+	db := json.NewDecoder(bytes.NewReader([]byte(b)))
+	db.UseNumber()
+	if err := da.Decode(&va); err != nil {
+		t.Fatalf("decoding %s: %v", a, err)
+	}
+	if err := db.Decode(&vb); err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
@@ -1,13 +1,73 @@
 package model
 
+// Error codes returned in ErrorResponse.Code
+const (
+	ErrCodeInvalidRequest      = "invalid_request"      // The request body or parameters failed validation
+	ErrCodeUnauthorized        = "unauthorized"         // The Authorization header is missing or was rejected
+	ErrCodeNotFound            = "not_found"            // The requested resource does not exist
+	ErrCodeNotAcceptable       = "not_acceptable"       // None of the media types in the Accept header can be produced
+	ErrCodeConflict            = "conflict"             // The resource is in a state that does not allow the operation
+	ErrCodeDoNotCall           = "do_not_call"          // The phone number is on the do-not-call list
+	ErrCodeUpstreamRejected    = "upstream_rejected"    // Bland rejected the request with a 4xx status
+	ErrCodeUpstreamError       = "upstream_error"       // Bland failed with a 5xx status or reported a failure in its body
+	ErrCodeUpstreamUnavailable = "upstream_unavailable" // Bland could not be reached or returned an unreadable response
+	ErrCodeUpstreamTimeout     = "upstream_timeout"     // Bland did not answer in time
+	ErrCodeWaitTimeout         = "wait_timeout"         // The call did not complete before the wait timeout
+	ErrCodeInternal            = "internal_error"       // The proxy failed for an unexpected reason
+)
+
 // ErrorResponse defines the structure for error responses
 type ErrorResponse struct {
-    Message string `json:"message"`
+	Code           string       `json:"code" example:"upstream_error"`
+	Message        string       `json:"message" example:"Pathway creation failed"`
+	UpstreamStatus int          `json:"upstream_status,omitempty" example:"500"`
+	UpstreamBody   interface{}  `json:"upstream_body,omitempty" swaggertype:"object"` // Parsed as JSON when possible, otherwise the raw string
+	RequestID      string       `json:"request_id" example:"5f2b6c0e9a8d4e1f"`
+	Fields         []FieldError `json:"fields,omitempty"` // Field-level validation errors
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field" example:"phone_number"`
+	Code    string `json:"code" example:"invalid_length"`
+	Message string `json:"message" example:"phone number is a short code"`
+}
+
+// Error implements the error interface
+func (e *ErrorResponse) Error() string {
+	return e.Code + ": " + e.Message
 }
 
+// Voicemail actions accepted in SendCall.VoicemailAction
+const (
+	VoicemailActionHangup       = "hangup"
+	VoicemailActionLeaveMessage = "leave_message"
+	VoicemailActionIgnore       = "ignore"
+)
+
+// StartTimeLayout is the layout of SendCall.StartTime
+const StartTimeLayout = "2006-01-02 15:04:05 -07:00"
+
+// SendCall represents the request body for sending a call. Either PathwayID or Task is required.
 type SendCall struct {
-	PhoneNumber string `json:"phone_number" binding:"required"`
-	PathwayID   string `json:"pathway_id" binding:"required"`
+	PhoneNumber         string                 `json:"phone_number" binding:"required" example:"+14155552671"`                                                            // Number to call
+	PathwayID           string                 `json:"pathway_id,omitempty" binding:"required_without=Task" example:"a6b2c3d4-pathway"`                                   // Pathway that drives the conversation
+	Task                string                 `json:"task,omitempty" binding:"required_without=PathwayID" example:"Confirm the appointment"`                             // Prompt used instead of a pathway
+	Voice               string                 `json:"voice,omitempty" example:"maya"`                                                                                    // Voice name or ID
+	FirstSentence       string                 `json:"first_sentence,omitempty" example:"Hi, this is Maya from Acme."`                                                    // First thing the agent says
+	WaitForGreeting     bool                   `json:"wait_for_greeting,omitempty" example:"true"`                                                                        // Wait for the callee to speak first
+	MaxDuration         int                    `json:"max_duration,omitempty" binding:"omitempty,min=1,max=720" example:"12"`                                             // Maximum call length in minutes
+	Record              bool                   `json:"record,omitempty" example:"true"`                                                                                   // Record the call audio
+	Language            string                 `json:"language,omitempty" binding:"omitempty,bcp47_language_tag" example:"en-US"`                                         // Language of the conversation
+	Webhook             string                 `json:"webhook,omitempty" binding:"omitempty,url" example:"https://example.com/bland/callback"`                            // URL notified when the call ends
+	Metadata            map[string]interface{} `json:"metadata,omitempty" swaggertype:"object,string" example:"campaign:spring"`                                          // Arbitrary data returned with the call
+	RequestData         map[string]interface{} `json:"request_data,omitempty" swaggertype:"object,string" example:"name:Jane"`                                            // Variables available to the agent
+	TransferPhoneNumber string                 `json:"transfer_phone_number,omitempty" example:"+14155552672"`                                                            // Number to transfer the call to
+	From                string                 `json:"from,omitempty" example:"+14155552673"`                                                                             // Caller ID, must be a number owned by the account
+	StartTime           string                 `json:"start_time,omitempty" binding:"omitempty,datetime=2006-01-02 15:04:05 -07:00" example:"2024-09-26 12:00:00 -05:00"` // Schedule the call for later
+	VoicemailMessage    string                 `json:"voicemail_message,omitempty" binding:"required_if=VoicemailAction leave_message" example:"Sorry we missed you."`    // Message left when a voicemail is reached
+	VoicemailAction     string                 `json:"voicemail_action,omitempty" binding:"omitempty,oneof=hangup leave_message ignore" example:"leave_message"`          // What to do when a voicemail is reached
+	AnsweredByEnabled   bool                   `json:"answered_by_enabled,omitempty" example:"true"`                                                                      // Detect whether a human or a voicemail answered
 }
 
 type CallResponse struct {
@@ -29,12 +89,18 @@ type AnalyzeCallResponse struct {
 	CreditsUsed float64  `json:"credits_used"`
 }
 
+// StopCallResponse represents the response from Bland when a call is ended
+type StopCallResponse struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"Call ended successfully."`
+}
 
 // CallDetail represents the structure of the call details response
 type CallDetail struct {
 	CallID               string             `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
 	CallLength           float64            `json:"call_length" example:"120.5"`
 	BatchID              *string            `json:"batch_id,omitempty" example:"batch123"`
+	PathwayID            *string            `json:"pathway_id,omitempty" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`
 	To                   string             `json:"to" example:"+14155552671"`
 	From                 string             `json:"from" example:"+14155552672"`
 	RequestData          RequestData        `json:"request_data"`
@@ -63,6 +129,7 @@ type CallDetail struct {
 	Status               string             `json:"status" example:"completed"`
 	CorrectedDuration    string             `json:"corrected_duration" example:"2m 30s"`
 	EndAt                string             `json:"end_at" example:"2024-09-26T12:36:56Z"`
+	Analyses             []CallAnalysis     `json:"analyses,omitempty"` // Automatic post-call analyses stored by the proxy; not returned by Bland
 }
 
 // RequestData represents the structure for the request data field in the response
@@ -99,6 +166,30 @@ type CreateFolderResponse struct {
 	Errors *string          `json:"errors,omitempty"`
 }
 
+// DeleteFolderResponse represents the response body after deleting a folder
+type DeleteFolderResponse struct {
+	Data   CreateFolderData `json:"data"`
+	Errors *string          `json:"errors,omitempty"`
+}
+
+// ListFoldersResponse represents the folders of the account, including nested folders
+type ListFoldersResponse struct {
+	Data   []CreateFolderData `json:"data"`
+	Errors *string            `json:"errors,omitempty"`
+}
+
+// FolderPathway represents a pathway listed in a folder
+type FolderPathway struct {
+	PathwayID string `json:"pathway_id"`
+	Name      string `json:"name"`
+}
+
+// FolderPathwaysResponse represents the pathways directly inside a folder
+type FolderPathwaysResponse struct {
+	Data   []FolderPathway `json:"data"`
+	Errors *string          `json:"errors,omitempty"`
+}
+
 // CreatePathwayRequest represents the structure of the request body for creating a pathway
 type CreatePathwayRequest struct {
 	Name        string `json:"name" binding:"required"`
@@ -136,7 +227,6 @@ type CombinedResponse struct {
 	MovePathwayData
 }
 
-
 // CreateChatRequest represents the structure of the request body for creating a chat
 type CreateChatRequest struct {
 	PathwayID   string `json:"pathway_id" binding:"required"`
@@ -154,6 +244,7 @@ type CreateChatResponse struct {
 	Data   CreateChatResponseData `json:"data"`
 	Errors *string                `json:"errors,omitempty"`
 }
+
 // GetPathwayResponse represents the structure of the response body for getting pathway information
 type GetPathwayResponse struct {
 	Name                   string        `json:"name"`
@@ -169,6 +260,8 @@ type Node struct {
 	ID   string    `json:"id"`
 	Data NodeData  `json:"data"`
 	Type string    `json:"type"`
+
+	Extra ExtraFields `json:"-"` // Fields Bland sends that are not modelled, such as position; kept as they are
 }
 
 // NodeData represents the data of a node
@@ -176,6 +269,7 @@ type NodeData struct {
 	Name           string       `json:"name"`
 	Active         bool         `json:"active"`
 	Prompt         *string      `json:"prompt,omitempty"`
+	Text           *string      `json:"text,omitempty"` // Static text spoken as is; used instead of prompt
 	GlobalPrompt   *string      `json:"globalPrompt,omitempty"`
 	Condition      *string      `json:"condition,omitempty"`
 	ModelOptions   ModelOptions `json:"modelOptions"`
@@ -183,6 +277,8 @@ type NodeData struct {
 	IsGlobal       bool         `json:"isGlobal,omitempty"`
 	GlobalLabel    *string      `json:"globalLabel,omitempty"`
 	GlobalDescription *string   `json:"globalDescription,omitempty"`
+
+	Extra ExtraFields `json:"-"` // Fields Bland sends that are not modelled; kept as they are
 }
 
 // ModelOptions represents model options inside node data
@@ -191,6 +287,8 @@ type ModelOptions struct {
 	Temperature      float64 `json:"temperature"`
 	SkipUserResponse bool    `json:"skipUserResponse"`
 	BlockInterruptions bool  `json:"block_interruptions"`
+
+	Extra ExtraFields `json:"-"` // Fields Bland sends that are not modelled; kept as they are
 }
 
 // Edge represents an edge in the pathway
@@ -200,8 +298,9 @@ type Edge struct {
 	Description *string `json:"description,omitempty"`
 	Source      string  `json:"source"`
 	Target      string  `json:"target"`
-}
 
+	Extra ExtraFields `json:"-"` // Fields Bland sends that are not modelled, such as type or data; kept as they are
+}
 
 // UpdatePathwayRequest represents the request body for updating a pathway
 type UpdatePathwayRequest struct {
@@ -211,6 +310,128 @@ type UpdatePathwayRequest struct {
     Edges       []Edge `json:"edges" extensions:"x-order=4"`
 }
 
+// Pathway issue severities
+const (
+	PathwayIssueError   = "error"
+	PathwayIssueWarning = "warning"
+)
+
+// PathwayIssue describes a problem found in the nodes and edges of a pathway
+type PathwayIssue struct {
+	Code     string `json:"code" example:"unreachable_node"`
+	Severity string `json:"severity" example:"error"` // error, or warning for problems that do not make the pathway invalid
+	Field    string `json:"field" example:"nodes[3]"` // Path of the offending value in the request body
+	NodeID   string `json:"node_id,omitempty" example:"node-4"`
+	EdgeID   string `json:"edge_id,omitempty" example:"edge-2"`
+	Message  string `json:"message" example:"node Goodbye cannot be reached from the start node"`
+}
+
+// PathwayValidation represents the outcome of validating the nodes and edges of a pathway
+type PathwayValidation struct {
+	Valid  bool           `json:"valid" example:"false"` // True when no issue is an error
+	Issues []PathwayIssue `json:"issues"`
+}
+
+// PathwayFieldChange describes a field whose value differs between two versions of a pathway
+type PathwayFieldChange struct {
+	Field string      `json:"field" example:"data.prompt"`
+	From  interface{} `json:"from" swaggertype:"string" example:"Greet the caller"`       // Value in the compared version, null when unset
+	To    interface{} `json:"to" swaggertype:"string" example:"Greet the caller by name"` // Value in the current pathway, null when unset
+}
+
+// PathwayNodeChange describes a node that was added, removed or modified
+type PathwayNodeChange struct {
+	NodeID string               `json:"node_id" example:"node-1"`
+	Name   string               `json:"name" example:"Greeting"`
+	Change string               `json:"change" example:"modified"` // added, removed or modified
+	Fields []PathwayFieldChange `json:"fields,omitempty"`          // Changed fields of a modified node
+}
+
+// PathwayEdgeChange describes an edge that was added, removed or modified
+type PathwayEdgeChange struct {
+	EdgeID string               `json:"edge_id" example:"edge-1"`
+	Source string               `json:"source" example:"node-1"`
+	Target string               `json:"target" example:"node-2"`
+	Change string               `json:"change" example:"added"` // added, removed or modified
+	Fields []PathwayFieldChange `json:"fields,omitempty"`       // Changed fields of a modified edge
+}
+
+// PathwayDiff represents the changes that turn a compared version of a pathway into the current pathway
+type PathwayDiff struct {
+	PathwayID string               `json:"pathway_id" example:"a6b2c3d4-pathway"`
+	Against   string               `json:"against" example:"pathway"`                       // body, pathway or snapshot
+	AgainstID string               `json:"against_id,omitempty" example:"b7c3d4e5-pathway"` // ID of the compared pathway or snapshot
+	Identical bool                 `json:"identical" example:"false"`
+	Fields    []PathwayFieldChange `json:"fields"` // Changes to the name and description
+	Nodes     []PathwayNodeChange  `json:"nodes"`
+	Edges     []PathwayEdgeChange  `json:"edges"`
+}
+
+// Pathway snapshot reasons
+const (
+	PathwaySnapshotUpdate  = "update"
+	PathwaySnapshotDelete  = "delete"
+	PathwaySnapshotRestore = "restore"
+)
+
+// PathwaySnapshot is a copy of a pathway taken before the proxy changed or deleted it
+type PathwaySnapshot struct {
+	SnapshotID string              `json:"snapshot_id" example:"snap_5f2b6c0e9a8d4e1f"`
+	PathwayID  string              `json:"pathway_id" example:"a6b2c3d4-pathway"`
+	Version    int                 `json:"version" example:"3"`     // Position in the pathway's history, starting at 1
+	Reason     string              `json:"reason" example:"update"` // update, delete or restore: the operation the snapshot was taken before
+	Name       string              `json:"name" example:"Customer Support"`
+	CreatedAt  string              `json:"created_at" example:"2024-09-26T12:34:56Z"`
+	Pathway    *GetPathwayResponse `json:"pathway,omitempty"` // Full pathway; omitted from listings
+}
+
+// RestorePathwayResponse represents the outcome of restoring a pathway to a snapshot
+type RestorePathwayResponse struct {
+	PathwayID  string      `json:"pathway_id" example:"a6b2c3d4-pathway"` // New ID when the pathway was re-created
+	SnapshotID string      `json:"snapshot_id" example:"snap_5f2b6c0e9a8d4e1f"`
+	Recreated  bool        `json:"recreated" example:"false"` // True when the pathway had been deleted and was created again
+	Pathway    PathwayData `json:"pathway"`
+}
+
+// PathwayDocument is a portable, self-contained copy of a pathway that can be kept
+// in version control and imported into any account
+type PathwayDocument struct {
+	Version     int    `json:"version" example:"1"` // Format version of the document
+	Name        string `json:"name" example:"Customer Support"`
+	Description string `json:"description,omitempty" example:"Handles inbound support calls"`
+	FolderPath  string `json:"folder_path,omitempty" example:"Support/Inbound"` // Folder names from the root, separated by /, with / and \ in names escaped as \/ and \\; empty for the root
+	Nodes       []Node `json:"nodes"`
+	Edges       []Edge `json:"edges"`
+	ExportedAt  string `json:"exported_at,omitempty" example:"2024-09-26T12:34:56Z"`
+}
+
+// ImportPathwayResponse represents the pathway created from a PathwayDocument
+type ImportPathwayResponse struct {
+	PathwayID string            `json:"pathway_id" example:"a6b2c3d4-pathway"`
+	Name      string            `json:"name" example:"Customer Support"`
+	FolderID  string            `json:"folder_id,omitempty" example:"folder_123"`
+	NodeIDs   map[string]string `json:"node_ids" swaggertype:"object,string" example:"node-1:node_5f2b6c0e9a8d4e1f"` // New ID of each node, by its ID in the document
+	Pathway   PathwayData       `json:"pathway"`
+}
+
+// ClonePathwayRequest represents the request body for copying a pathway
+type ClonePathwayRequest struct {
+	Name        string `json:"name" binding:"required" example:"Customer Support (copy)"`
+	Description string `json:"description,omitempty" example:"Starting point for the billing line"` // Defaults to the description of the source pathway
+	FolderID    string `json:"folder_id,omitempty" example:"folder_123"`                            // Folder of the copy; the root folder when empty
+}
+
+// ClonePathwayResponse represents the copy of a pathway
+type ClonePathwayResponse struct {
+	PathwayID       string            `json:"pathway_id" example:"b7c3d4e5-pathway"`
+	SourcePathwayID string            `json:"source_pathway_id" example:"a6b2c3d4-pathway"`
+	Name            string            `json:"name" example:"Customer Support (copy)"`
+	FolderID        string            `json:"folder_id,omitempty" example:"folder_123"`
+	CrossAccount    bool              `json:"cross_account" example:"false"`                                               // Whether the copy was made with a different Authorization token
+	NodeIDs         map[string]string `json:"node_ids" swaggertype:"object,string" example:"node-1:node_5f2b6c0e9a8d4e1f"` // New ID of each node, by its ID in the source pathway
+	Pathway         PathwayData       `json:"pathway"`
+}
+
 // UpdatePathwayResponse represents the response body after updating a pathway
 type UpdatePathwayResponse struct {
     Status      string      `json:"status"`
@@ -226,7 +447,6 @@ type PathwayData struct {
     Edges       []Edge  `json:"edges"`
 }
 
-
 // DeletePathwayResponse represents the structure of the response body after deleting a pathway
 type DeletePathwayResponse struct {
     Status    string `json:"status"`    // Status of the operation (success or error)
@@ -235,7 +455,6 @@ type DeletePathwayResponse struct {
 
 }
 
-
 // SendMessageRequest represents the request body for sending a message to the chat
 type SendMessageRequest struct {
 	Message string `json:"message" binding:"required"`
@@ -263,3 +482,410 @@ type SendMessageResponse struct {
 	Data   SendMessageResponseData `json:"data"`   // The main data of the response
 	Errors *string                 `json:"errors"` // Any errors encountered during the request (optional)
 }
+
+// Batch statuses
+const (
+	BatchStatusRunning   = "running"
+	BatchStatusCompleted = "completed"
+	BatchStatusStopped   = "stopped"
+)
+
+// Batch contact statuses
+const (
+	BatchContactPending     = "pending"
+	BatchContactDispatching = "dispatching"
+	BatchContactDispatched  = "dispatched"
+	BatchContactFailed      = "failed"
+	BatchContactCancelled   = "cancelled"
+)
+
+// BatchContact represents a single contact to call in a batch
+type BatchContact struct {
+	PhoneNumber string                 `json:"phone_number" binding:"required" example:"+14155552671"`
+	Variables   map[string]interface{} `json:"variables,omitempty" swaggertype:"object,string" example:"name:Jane"`   // Sent to Bland as request_data
+	Metadata    map[string]interface{} `json:"metadata,omitempty" swaggertype:"object,string" example:"crm_id:12345"` // Returned with the call
+}
+
+// BatchCallRequest represents the request body for dispatching calls to many contacts
+type BatchCallRequest struct {
+	PathwayID string         `json:"pathway_id" binding:"required" example:"a6b2c3d4-pathway"`
+	Contacts  []BatchContact `json:"contacts" binding:"required,min=1,dive"`
+}
+
+// BatchContactResult represents the dispatch outcome for a single contact
+type BatchContactResult struct {
+	Index       int            `json:"index" example:"0"` // Position of the contact in the request
+	PhoneNumber string         `json:"phone_number" example:"+14155552671"`
+	Status      string         `json:"status" example:"dispatched"` // pending, dispatching, dispatched, failed or cancelled
+	CallID      string         `json:"call_id,omitempty" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	Error       *ErrorResponse `json:"error,omitempty"`
+}
+
+// Batch represents a batch of calls and the outcome for each contact
+type Batch struct {
+	BatchID     string               `json:"batch_id" example:"batch_5f2b6c0e9a8d4e1f"`
+	PathwayID   string               `json:"pathway_id" example:"a6b2c3d4-pathway"`
+	Status      string               `json:"status" example:"running"` // running, completed or stopped
+	CreatedAt   string               `json:"created_at" example:"2024-09-26T12:34:56Z"`
+	CompletedAt string               `json:"completed_at,omitempty" example:"2024-09-26T12:35:56Z"`
+	Total       int                  `json:"total" example:"100"`
+	Dispatched  int                  `json:"dispatched" example:"97"`
+	Failed      int                  `json:"failed" example:"3"`
+	Cancelled   int                  `json:"cancelled" example:"0"` // Contacts not called because the batch was stopped
+	Results     []BatchContactResult `json:"results"`
+}
+
+// Stop call outcomes
+const (
+	StopCallStopped = "stopped"
+	StopCallFailed  = "failed"
+)
+
+// StopCallResult represents the outcome of stopping a single call of a batch
+type StopCallResult struct {
+	CallID string         `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	Status string         `json:"status" example:"stopped"` // stopped or failed
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+// StopBatchResponse represents the outcome of stopping the active calls of a batch
+type StopBatchResponse struct {
+	BatchID   string           `json:"batch_id" example:"batch_5f2b6c0e9a8d4e1f"`
+	Cancelled int              `json:"cancelled" example:"20"` // Contacts that were still pending and will not be called
+	Stopped   int              `json:"stopped" example:"12"`
+	Failed    int              `json:"failed" example:"1"`
+	Results   []StopCallResult `json:"results"`
+}
+
+// CSVRowError represents the validation errors of a single CSV row
+type CSVRowError struct {
+	Row         int          `json:"row" example:"3"` // Line number in the file, the header being line 1
+	PhoneNumber string       `json:"phone_number" example:"555-0100"`
+	Errors      []FieldError `json:"errors"`
+}
+
+// CSVImportResponse represents the outcome of importing a CSV contact list
+type CSVImportResponse struct {
+	TotalRows int           `json:"total_rows" example:"100"`
+	ValidRows int           `json:"valid_rows" example:"98"`
+	DryRun    bool          `json:"dry_run" example:"false"`
+	RowErrors []CSVRowError `json:"row_errors"`
+	Batch     *Batch        `json:"batch,omitempty"` // Batch the valid rows were queued in, absent for a dry run
+}
+
+// Scheduled call statuses
+const (
+	ScheduledStatusScheduled   = "scheduled"
+	ScheduledStatusDispatching = "dispatching"
+	ScheduledStatusDispatched  = "dispatched"
+	ScheduledStatusFailed      = "failed"
+	ScheduledStatusCancelled   = "cancelled"
+)
+
+// CallingWindow represents the local hours and days during which a contact may be called
+type CallingWindow struct {
+	Start string   `json:"start" example:"09:00"`                  // Local opening time, HH:MM
+	End   string   `json:"end" example:"20:00"`                    // Local closing time, HH:MM, exclusive
+	Days  []string `json:"days" example:"mon,tue,wed,thu,fri,sat"` // Allowed weekdays: mon, tue, wed, thu, fri, sat, sun
+}
+
+// ScheduleCallRequest represents the request body for scheduling a call
+type ScheduleCallRequest struct {
+	Call        SendCall       `json:"call" binding:"required"`
+	ScheduledAt string         `json:"scheduled_at" binding:"required" example:"2024-09-26T10:00:00-04:00"` // RFC 3339 time; moved to the next window opening when outside the window
+	Timezone    string         `json:"timezone,omitempty" example:"America/New_York"`                       // IANA time zone of the contact; the configured default when empty
+	Window      *CallingWindow `json:"window,omitempty"`                                                    // Overrides the configured calling window
+}
+
+// RescheduleCallRequest represents the request body for moving a scheduled call
+type RescheduleCallRequest struct {
+	ScheduledAt string `json:"scheduled_at" binding:"required" example:"2024-09-27T10:00:00-04:00"` // RFC 3339 time
+	Timezone    string `json:"timezone,omitempty" example:"America/Chicago"`                        // Changes the contact's time zone when set
+}
+
+// ScheduledCall represents a call stored for later dispatch
+type ScheduledCall struct {
+	ID           string         `json:"id" example:"sched_5f2b6c0e9a8d4e1f"`
+	Status       string         `json:"status" example:"scheduled"` // scheduled, dispatching, dispatched, failed or cancelled
+	Call         SendCall       `json:"call"`
+	Timezone     string         `json:"timezone" example:"America/New_York"`
+	Window       CallingWindow  `json:"window"`
+	ScheduledAt  string         `json:"scheduled_at" example:"2024-09-26T07:00:00-04:00"` // Time requested by the caller
+	DueAt        string         `json:"due_at" example:"2024-09-26T09:00:00-04:00"`       // Time the call will be dispatched, inside the calling window
+	CreatedAt    string         `json:"created_at" example:"2024-09-25T12:34:56Z"`
+	UpdatedAt    string         `json:"updated_at" example:"2024-09-25T12:34:56Z"`
+	DispatchedAt string         `json:"dispatched_at,omitempty" example:"2024-09-26T13:00:01Z"`
+	CallID       string         `json:"call_id,omitempty" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	Error        *ErrorResponse `json:"error,omitempty"`
+}
+
+// DNCEntry represents a phone number on the do-not-call list
+type DNCEntry struct {
+	PhoneNumber string `json:"phone_number" example:"+14155552671"` // E.164
+	Reason      string `json:"reason,omitempty" example:"Asked not to be called again"`
+	AddedAt     string `json:"added_at" example:"2024-09-25T12:34:56Z"`
+}
+
+// AddDNCRequest represents the request body for adding numbers to the do-not-call list
+type AddDNCRequest struct {
+	PhoneNumbers []string `json:"phone_numbers" binding:"required,min=1,dive,required" example:"+14155552671,(212) 555-0123"`
+	Reason       string   `json:"reason,omitempty" example:"Asked not to be called again"`
+}
+
+// DNCImportResponse represents the outcome of importing numbers to the do-not-call list
+type DNCImportResponse struct {
+	TotalRows int           `json:"total_rows" example:"100"`
+	Added     int           `json:"added" example:"98"`
+	RowErrors []CSVRowError `json:"row_errors"`
+}
+
+// DNCBlock is the audit record of a call refused because its number is on the do-not-call list
+type DNCBlock struct {
+	ID          string `json:"id" example:"block_5f2b6c0e9a8d4e1f"`
+	PhoneNumber string `json:"phone_number" example:"+14155552671"`
+	Field       string `json:"field" example:"phone_number"`                           // Field of the call holding the listed number: phone_number or transfer_phone_number
+	Reason      string `json:"reason,omitempty" example:"Asked not to be called again"` // Reason the number was listed
+	PathwayID   string `json:"pathway_id,omitempty" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`
+	BatchID     string `json:"batch_id,omitempty" example:"batch_5f2b6c0e9a8d4e1f"` // Set when the call was part of a batch
+	BlockedAt   string `json:"blocked_at" example:"2024-09-25T12:34:56.789Z"`
+}
+
+// CallRecord is the locally stored history of a call
+type CallRecord struct {
+	CallID      string                 `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	PhoneNumber string                 `json:"phone_number" example:"+14155552671"`
+	PathwayID   string                 `json:"pathway_id,omitempty" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`
+	BatchID     string                 `json:"batch_id,omitempty" example:"batch_5f2b6c0e9a8d4e1f"`
+	Status      string                 `json:"status" example:"completed"` // Last status reported by Bland, queued until then, stopped once stopped through the proxy
+	AnsweredBy  string                 `json:"answered_by,omitempty" example:"human"`
+	Completed   bool                   `json:"completed" example:"true"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt   string                 `json:"created_at" example:"2024-09-26T12:34:56Z"` // When the call was dispatched
+	UpdatedAt   string                 `json:"updated_at" example:"2024-09-26T12:36:56Z"`
+	StoppedAt   string                 `json:"stopped_at,omitempty" example:"2024-09-26T12:35:30Z"` // When the call was stopped through the proxy
+	Detail      *CallDetail            `json:"detail,omitempty"`                                    // Snapshot cached once the call completed; omitted from listings
+	Analyses    []CallAnalysis         `json:"analyses,omitempty"`                                  // Automatic post-call analyses, one per rule
+}
+
+// CallListResponse represents a page of the local call history
+type CallListResponse struct {
+	Total  int          `json:"total" example:"250"` // Number of calls matching the filters
+	Offset int          `json:"offset" example:"0"`
+	Limit  int          `json:"limit" example:"100"`
+	Calls  []CallRecord `json:"calls"`
+}
+
+// Event describes something that happened to a call, analysis or pathway
+type Event struct {
+	ID        string      `json:"id" example:"evt_5f2b6c0e9a8d4e1f"`
+	Type      string      `json:"type" example:"call.completed"`
+	CreatedAt string      `json:"created_at" example:"2024-09-26T12:36:56Z"`
+	Data      interface{} `json:"data" swaggertype:"object"` // CallDetail for call events
+	Owner     string      `json:"-"`                         // Owner of the token the event happened with; only its subscriptions are notified
+}
+
+// WebhookReceipt acknowledges a callback received from Bland
+type WebhookReceipt struct {
+	Status string `json:"status" example:"received"`
+	CallID string `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+}
+
+// AnalysisEvent is the data of an analysis.completed event
+type AnalysisEvent struct {
+	CallID   string              `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	Request  AnalyzeCallRequest  `json:"request"`
+	Response AnalyzeCallResponse `json:"response"`
+	Result   *AnalysisResult     `json:"result,omitempty"` // Set when the analysis used a schema
+}
+
+// PathwayEvent is the data of pathway.created, pathway.updated and pathway.deleted events
+type PathwayEvent struct {
+	PathwayID string `json:"pathway_id" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`
+	Name      string `json:"name,omitempty" example:"Customer Support"`
+	FolderID  string `json:"folder_id,omitempty" example:"folder_123"` // Set on pathway.created when the pathway was moved to a folder
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead" // Every attempt failed; listed as a dead letter until redelivered
+)
+
+// CreateWebhookSubscriptionRequest represents the request body for subscribing to events
+type CreateWebhookSubscriptionRequest struct {
+	URL         string   `json:"url" binding:"required,url" example:"https://crm.example.com/hooks/bland"`
+	Events      []string `json:"events" binding:"required,min=1" example:"call.completed,analysis.completed"` // call.completed, analysis.completed, pathway.created, pathway.updated, pathway.deleted
+	Secret      string   `json:"secret" binding:"required,min=16" example:"9c1f0b7e2a4d4c6f8e0a1b3c5d7e9f11"` // Key of the X-Webhook-Signature HMAC; never returned
+	Description string   `json:"description,omitempty" example:"CRM sync"`
+}
+
+// WebhookSubscription represents a downstream endpoint notified of events
+type WebhookSubscription struct {
+	ID          string   `json:"id" example:"sub_5f2b6c0e9a8d4e1f"`
+	URL         string   `json:"url" example:"https://crm.example.com/hooks/bland"`
+	Events      []string `json:"events" example:"call.completed,analysis.completed"`
+	Description string   `json:"description,omitempty" example:"CRM sync"`
+	CreatedAt   string   `json:"created_at" example:"2024-09-25T12:34:56Z"`
+}
+
+// WebhookDelivery represents the delivery of one event to one subscription
+type WebhookDelivery struct {
+	ID             string `json:"id" example:"dlv_5f2b6c0e9a8d4e1f"`
+	SubscriptionID string `json:"subscription_id" example:"sub_5f2b6c0e9a8d4e1f"`
+	URL            string `json:"url" example:"https://crm.example.com/hooks/bland"`
+	Event          Event  `json:"event"`
+	Status         string `json:"status" example:"pending"` // pending, delivered or dead
+	Attempts       int    `json:"attempts" example:"2"`     // Attempts since the delivery was created or last redelivered
+	NextAttemptAt  string `json:"next_attempt_at,omitempty" example:"2024-09-26T12:37:16Z"`
+	LastAttemptAt  string `json:"last_attempt_at,omitempty" example:"2024-09-26T12:36:56Z"`
+	LastStatusCode int    `json:"last_status_code,omitempty" example:"503"`
+	LastError      string `json:"last_error,omitempty" example:"target answered 503 Service Unavailable"`
+	DeliveredAt    string `json:"delivered_at,omitempty" example:"2024-09-26T12:37:17Z"`
+	CreatedAt      string `json:"created_at" example:"2024-09-26T12:36:56Z"`
+}
+
+// Analysis question types
+const (
+	AnalysisTypeBoolean = "boolean"
+	AnalysisTypeNumber  = "number"
+	AnalysisTypeEnum    = "enum"
+	AnalysisTypeString  = "string"
+)
+
+// AnalysisQuestion is one question of an analysis schema
+type AnalysisQuestion struct {
+	Name        string   `json:"name" example:"interested"`                                        // Key of the answer in the result; letters, digits and underscores
+	Question    string   `json:"question" example:"Is the customer interested in the offer?"`      // Question asked about the call
+	Type        string   `json:"type" example:"boolean"`                                           // boolean, number, enum or string
+	Description string   `json:"description,omitempty" example:"True when a follow-up was agreed"` // Guidance sent to Bland with the question
+	Options     []string `json:"options,omitempty" example:"morning,afternoon,evening"`            // Allowed answers of an enum question
+}
+
+// AnalysisSchemaRequest represents the request body for creating or replacing an analysis schema
+type AnalysisSchemaRequest struct {
+	Name        string             `json:"name" binding:"required" example:"Lead qualification"`
+	Description string             `json:"description,omitempty" example:"Questions asked after every sales call"`
+	Goal        string             `json:"goal" binding:"required" example:"Qualify the lead for the sales team"`
+	Questions   []AnalysisQuestion `json:"questions" binding:"required,min=1"`
+}
+
+// AnalysisSchema is a named, reusable set of typed analysis questions
+type AnalysisSchema struct {
+	ID          string             `json:"id" example:"schema_5f2b6c0e9a8d4e1f"`
+	Name        string             `json:"name" example:"Lead qualification"`
+	Description string             `json:"description,omitempty" example:"Questions asked after every sales call"`
+	Goal        string             `json:"goal" example:"Qualify the lead for the sales team"`
+	Questions   []AnalysisQuestion `json:"questions"`
+	CreatedAt   string             `json:"created_at" example:"2024-09-25T12:34:56Z"`
+	UpdatedAt   string             `json:"updated_at" example:"2024-09-25T12:34:56Z"`
+}
+
+// AnalysisIssue reports an answer that does not match the type of its question
+type AnalysisIssue struct {
+	Question string `json:"question" example:"budget"`     // Question name
+	Code     string `json:"code" example:"invalid_number"` // missing, invalid_boolean, invalid_number or invalid_option
+	Message  string `json:"message" example:"answer is not a number"`
+	Answer   string `json:"answer,omitempty" example:"about five thousand"` // Answer returned by Bland
+}
+
+// AnalysisResult is the outcome of analyzing a call with a schema
+type AnalysisResult struct {
+	CallID      string                 `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	SchemaID    string                 `json:"schema_id" example:"schema_5f2b6c0e9a8d4e1f"`
+	Answers     map[string]interface{} `json:"answers" swaggertype:"object,string" example:"interested:true"`    // Typed answer of each question keyed by name; null when the answer is invalid
+	RawAnswers  map[string]string      `json:"raw_answers" swaggertype:"object,string" example:"interested:Yes"` // Answers as returned by Bland
+	Valid       bool                   `json:"valid" example:"true"`                                             // False when any answer is missing or invalid
+	Issues      []AnalysisIssue        `json:"issues,omitempty"`
+	CreditsUsed float64                `json:"credits_used" example:"0.3"`
+	AnalyzedAt  string                 `json:"analyzed_at" example:"2024-09-26T12:40:00Z"`
+}
+
+// AutoAnalysisRuleRequest represents the request body for creating or replacing an automatic analysis rule.
+// Either SchemaID or Goal and Questions are required.
+type AutoAnalysisRuleRequest struct {
+	PathwayID string     `json:"pathway_id" binding:"required" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`                     // Calls of this pathway are analyzed when they complete
+	SchemaID  string     `json:"schema_id,omitempty" example:"schema_5f2b6c0e9a8d4e1f"`                                            // Analysis schema to use
+	Goal      string     `json:"goal,omitempty" binding:"required_without=SchemaID" example:"Qualify the lead for the sales team"` // Goal used without a schema
+	Questions [][]string `json:"questions,omitempty" binding:"required_without=SchemaID"`                                          // Questions used without a schema
+	Enabled   *bool      `json:"enabled,omitempty" example:"true"`                                                                 // Defaults to true
+}
+
+// AutoAnalysisRule runs an analysis on every completed call of a pathway
+type AutoAnalysisRule struct {
+	ID        string     `json:"id" example:"rule_5f2b6c0e9a8d4e1f"`
+	PathwayID string     `json:"pathway_id" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`
+	SchemaID  string     `json:"schema_id,omitempty" example:"schema_5f2b6c0e9a8d4e1f"`
+	Goal      string     `json:"goal,omitempty" example:"Qualify the lead for the sales team"`
+	Questions [][]string `json:"questions,omitempty"`
+	Enabled   bool       `json:"enabled" example:"true"`
+	CreatedAt string     `json:"created_at" example:"2024-09-25T12:34:56.789Z"`
+	UpdatedAt string     `json:"updated_at" example:"2024-09-25T12:34:56.789Z"`
+}
+
+// CallAnalysis is the outcome of an automatic analysis rule for a call
+type CallAnalysis struct {
+	RuleID     string               `json:"rule_id" example:"rule_5f2b6c0e9a8d4e1f"`
+	Request    AnalyzeCallRequest   `json:"request"`
+	Response   *AnalyzeCallResponse `json:"response,omitempty"` // Set when the analysis succeeded
+	Result     *AnalysisResult      `json:"result,omitempty"`   // Typed answers, set when the rule uses a schema
+	Error      *ErrorResponse       `json:"error,omitempty"`    // Set when the analysis failed
+	AnalyzedAt string               `json:"analyzed_at" example:"2024-09-26T12:40:00Z"`
+}
+
+// Analysis job statuses
+const (
+	AnalysisJobRunning   = "running"
+	AnalysisJobCompleted = "completed"
+)
+
+// Analysis job call statuses
+const (
+	AnalysisJobCallPending  = "pending"
+	AnalysisJobCallAnalyzed = "analyzed"
+	AnalysisJobCallFailed   = "failed"
+)
+
+// AnalysisJobFilter selects completed calls from the local call history
+type AnalysisJobFilter struct {
+	PathwayID string `json:"pathway_id,omitempty" example:"3c90c3cc-0d44-4b50-8888-8dd25736052a"`
+	BatchID   string `json:"batch_id,omitempty" example:"batch_5f2b6c0e9a8d4e1f"`
+	StartDate string `json:"start_date,omitempty" example:"2024-09-01"` // RFC 3339 time or YYYY-MM-DD day
+	EndDate   string `json:"end_date,omitempty" example:"2024-09-30"`   // RFC 3339 time or YYYY-MM-DD day, inclusive
+}
+
+// AnalysisJobRequest represents the request body for analyzing many calls.
+// Calls are given by CallIDs, Filter or both; the analysis by SchemaID or by Goal and Questions.
+type AnalysisJobRequest struct {
+	CallIDs           []string           `json:"call_ids,omitempty" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	Filter            *AnalysisJobFilter `json:"filter,omitempty"`
+	SchemaID          string             `json:"schema_id,omitempty" example:"schema_5f2b6c0e9a8d4e1f"`
+	Goal              string             `json:"goal,omitempty" binding:"required_without=SchemaID" example:"Qualify the lead for the sales team"`
+	Questions         [][]string         `json:"questions,omitempty" binding:"required_without=SchemaID"`
+	RequestsPerMinute int                `json:"requests_per_minute,omitempty" binding:"omitempty,min=1" example:"30"` // Lowers the configured rate limit for this job
+}
+
+// AnalysisJobCallResult represents the analysis outcome for a single call of a job
+type AnalysisJobCallResult struct {
+	CallID   string               `json:"call_id" example:"f0300301-b066-47a0-83ce-895cb1b63a9a"`
+	Status   string               `json:"status" example:"analyzed"` // pending, analyzed or failed
+	Response *AnalyzeCallResponse `json:"response,omitempty"`
+	Result   *AnalysisResult      `json:"result,omitempty"` // Typed answers, set when the job uses a schema
+	Error    *ErrorResponse       `json:"error,omitempty"`
+}
+
+// AnalysisJob represents the analysis of many calls and the outcome for each call
+type AnalysisJob struct {
+	JobID       string                  `json:"job_id" example:"ajob_5f2b6c0e9a8d4e1f"`
+	Request     AnalyzeCallRequest      `json:"request"` // Goal and questions sent for every call
+	SchemaID    string                  `json:"schema_id,omitempty" example:"schema_5f2b6c0e9a8d4e1f"`
+	Questions   []string                `json:"questions,omitempty" example:"interested,budget"` // Names of the schema questions, in order
+	Status      string                  `json:"status" example:"running"`                        // running or completed
+	CreatedAt   string                  `json:"created_at" example:"2024-09-26T12:34:56Z"`
+	CompletedAt string                  `json:"completed_at,omitempty" example:"2024-09-26T12:40:56Z"`
+	Total       int                     `json:"total" example:"100"`
+	Analyzed    int                     `json:"analyzed" example:"97"`
+	Failed      int                     `json:"failed" example:"3"`
+	CreditsUsed float64                 `json:"credits_used" example:"29.1"` // Sum over the analyzed calls
+	Results     []AnalysisJobCallResult `json:"results"`
+}
@@ -0,0 +1,188 @@
+package pathway
+
+import (
+	"bland/model"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change kinds reported by Diff.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// field reads one comparable value of a node or edge. Unset pointers read as nil.
+type field[T any] struct {
+	name  string
+	value func(T) interface{}
+}
+
+var pathwayFields = []field[model.GetPathwayResponse]{
+	{"name", func(p model.GetPathwayResponse) interface{} { return p.Name }},
+	{"description", func(p model.GetPathwayResponse) interface{} { return deref(p.Description) }},
+}
+
+var nodeFields = []field[model.Node]{
+	{"type", func(n model.Node) interface{} { return n.Type }},
+	{"data.name", func(n model.Node) interface{} { return n.Data.Name }},
+	{"data.active", func(n model.Node) interface{} { return n.Data.Active }},
+	{"data.isStart", func(n model.Node) interface{} { return n.Data.IsStart }},
+	{"data.isGlobal", func(n model.Node) interface{} { return n.Data.IsGlobal }},
+	{"data.prompt", func(n model.Node) interface{} { return deref(n.Data.Prompt) }},
+	{"data.text", func(n model.Node) interface{} { return deref(n.Data.Text) }},
+	{"data.globalPrompt", func(n model.Node) interface{} { return deref(n.Data.GlobalPrompt) }},
+	{"data.condition", func(n model.Node) interface{} { return deref(n.Data.Condition) }},
+	{"data.globalLabel", func(n model.Node) interface{} { return deref(n.Data.GlobalLabel) }},
+	{"data.globalDescription", func(n model.Node) interface{} { return deref(n.Data.GlobalDescription) }},
+	{"data.modelOptions.modelType", func(n model.Node) interface{} { return n.Data.ModelOptions.ModelType }},
+	{"data.modelOptions.temperature", func(n model.Node) interface{} { return n.Data.ModelOptions.Temperature }},
+	{"data.modelOptions.skipUserResponse", func(n model.Node) interface{} { return n.Data.ModelOptions.SkipUserResponse }},
+	{"data.modelOptions.block_interruptions", func(n model.Node) interface{} { return n.Data.ModelOptions.BlockInterruptions }},
+}
+
+var edgeFields = []field[model.Edge]{
+	{"source", func(e model.Edge) interface{} { return e.Source }},
+	{"target", func(e model.Edge) interface{} { return e.Target }},
+	{"label", func(e model.Edge) interface{} { return deref(e.Label) }},
+	{"description", func(e model.Edge) interface{} { return deref(e.Description) }},
+}
+
+// Diff reports the changes that turn the pathway from into the pathway to.
+// Nodes and edges are matched by ID; when an ID is used twice, the first use
+// counts. Changes are listed in the order of to, followed by removals in the
+// order of from.
+func Diff(from, to model.GetPathwayResponse) model.PathwayDiff {
+	diff := model.PathwayDiff{
+		Fields: compare(pathwayFields, from, to),
+		Nodes:  []model.PathwayNodeChange{},
+		Edges:  []model.PathwayEdgeChange{},
+	}
+
+	fromNodes := index(from.Nodes, func(n model.Node) string { return n.ID })
+	toNodes := index(to.Nodes, func(n model.Node) string { return n.ID })
+	for _, node := range unique(to.Nodes, func(n model.Node) string { return n.ID }) {
+		old, ok := fromNodes[node.ID]
+		if !ok {
+			diff.Nodes = append(diff.Nodes, model.PathwayNodeChange{NodeID: node.ID, Name: node.Data.Name, Change: ChangeAdded})
+		} else if fields := nodeChanges(old, node); len(fields) > 0 {
+			diff.Nodes = append(diff.Nodes, model.PathwayNodeChange{NodeID: node.ID, Name: node.Data.Name, Change: ChangeModified, Fields: fields})
+		}
+	}
+	for _, node := range unique(from.Nodes, func(n model.Node) string { return n.ID }) {
+		if _, ok := toNodes[node.ID]; !ok {
+			diff.Nodes = append(diff.Nodes, model.PathwayNodeChange{NodeID: node.ID, Name: node.Data.Name, Change: ChangeRemoved})
+		}
+	}
+
+	fromEdges := index(from.Edges, func(e model.Edge) string { return e.ID })
+	toEdges := index(to.Edges, func(e model.Edge) string { return e.ID })
+	for _, edge := range unique(to.Edges, func(e model.Edge) string { return e.ID }) {
+		old, ok := fromEdges[edge.ID]
+		if !ok {
+			diff.Edges = append(diff.Edges, model.PathwayEdgeChange{EdgeID: edge.ID, Source: edge.Source, Target: edge.Target, Change: ChangeAdded})
+		} else if fields := edgeChanges(old, edge); len(fields) > 0 {
+			diff.Edges = append(diff.Edges, model.PathwayEdgeChange{EdgeID: edge.ID, Source: edge.Source, Target: edge.Target, Change: ChangeModified, Fields: fields})
+		}
+	}
+	for _, edge := range unique(from.Edges, func(e model.Edge) string { return e.ID }) {
+		if _, ok := toEdges[edge.ID]; !ok {
+			diff.Edges = append(diff.Edges, model.PathwayEdgeChange{EdgeID: edge.ID, Source: edge.Source, Target: edge.Target, Change: ChangeRemoved})
+		}
+	}
+
+	diff.Identical = len(diff.Fields) == 0 && len(diff.Nodes) == 0 && len(diff.Edges) == 0
+	return diff
+}
+
+// nodeChanges returns the modelled and unmodelled fields that differ between
+// two versions of a node.
+func nodeChanges(from, to model.Node) []model.PathwayFieldChange {
+	changes := compare(nodeFields, from, to)
+	changes = append(changes, extraChanges("", from.Extra, to.Extra)...)
+	changes = append(changes, extraChanges("data.", from.Data.Extra, to.Data.Extra)...)
+	return append(changes, extraChanges("data.modelOptions.", from.Data.ModelOptions.Extra, to.Data.ModelOptions.Extra)...)
+}
+
+// edgeChanges returns the modelled and unmodelled fields that differ between
+// two versions of an edge.
+func edgeChanges(from, to model.Edge) []model.PathwayFieldChange {
+	return append(compare(edgeFields, from, to), extraChanges("", from.Extra, to.Extra)...)
+}
+
+// extraChanges returns the unmodelled fields whose decoded values differ
+// between from and to, sorted by key. Missing fields read as nil.
+func extraChanges(prefix string, from, to model.ExtraFields) []model.PathwayFieldChange {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []model.PathwayFieldChange
+	for _, key := range keys {
+		if before, after := decode(from[key]), decode(to[key]); !reflect.DeepEqual(before, after) {
+			changes = append(changes, model.PathwayFieldChange{Field: prefix + key, From: before, To: after})
+		}
+	}
+	return changes
+}
+
+// decode returns the value of a raw JSON field, or nil when it is unset or invalid.
+func decode(raw json.RawMessage) interface{} {
+	var value interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &value) != nil {
+		return nil
+	}
+	return value
+}
+
+// compare returns the fields whose values differ between from and to.
+func compare[T any](fields []field[T], from, to T) []model.PathwayFieldChange {
+	changes := []model.PathwayFieldChange{}
+	for _, f := range fields {
+		if before, after := f.value(from), f.value(to); before != after {
+			changes = append(changes, model.PathwayFieldChange{Field: f.name, From: before, To: after})
+		}
+	}
+	return changes
+}
+
+// index maps the ID of each item to its first occurrence.
+func index[T any](items []T, id func(T) string) map[string]T {
+	out := make(map[string]T, len(items))
+	for _, item := range items {
+		if _, ok := out[id(item)]; !ok {
+			out[id(item)] = item
+		}
+	}
+	return out
+}
+
+// unique returns the first occurrence of each ID, in order.
+func unique[T any](items []T, id func(T) string) []T {
+	seen := make(map[string]bool, len(items))
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if !seen[id(item)] {
+			seen[id(item)] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// deref returns the value of s, or nil when s is unset.
+func deref(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
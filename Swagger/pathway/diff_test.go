@@ -0,0 +1,82 @@
+package pathway
+
+import (
+	"bland/model"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	str := func(s string) *string { return &s }
+	from := model.GetPathwayResponse{
+		Name: "Support",
+		Nodes: []model.Node{
+			{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: str("Greet the caller")}},
+			{ID: "2", Type: "End Call", Data: model.NodeData{Name: "Bye", Prompt: str("Goodbye")}},
+		},
+		Edges: []model.Edge{{ID: "e1", Source: "1", Target: "2"}},
+	}
+	to := model.GetPathwayResponse{
+		Name:        "Support v2",
+		Description: str("Inbound support"),
+		Nodes: []model.Node{
+			{ID: "1", Type: "Default", Data: model.NodeData{Name: "Start", IsStart: true, Prompt: str("Greet the caller by name")}},
+			{ID: "3", Type: "Transfer Call", Data: model.NodeData{Name: "Transfer"}},
+		},
+		Edges: []model.Edge{{ID: "e2", Source: "1", Target: "3", Label: str("wants an agent")}},
+	}
+
+	diff := Diff(from, to)
+	if diff.Identical {
+		t.Fatal("Diff of different versions is identical")
+	}
+	wantFields := []model.PathwayFieldChange{
+		{Field: "name", From: "Support", To: "Support v2"},
+		{Field: "description", From: nil, To: "Inbound support"},
+	}
+	if !reflect.DeepEqual(diff.Fields, wantFields) {
+		t.Errorf("fields = %+v, want %+v", diff.Fields, wantFields)
+	}
+	wantNodes := []model.PathwayNodeChange{
+		{NodeID: "1", Name: "Start", Change: ChangeModified, Fields: []model.PathwayFieldChange{{Field: "data.prompt", From: "Greet the caller", To: "Greet the caller by name"}}},
+		{NodeID: "3", Name: "Transfer", Change: ChangeAdded},
+		{NodeID: "2", Name: "Bye", Change: ChangeRemoved},
+	}
+	if !reflect.DeepEqual(diff.Nodes, wantNodes) {
+		t.Errorf("nodes = %+v, want %+v", diff.Nodes, wantNodes)
+	}
+	wantEdges := []model.PathwayEdgeChange{
+		{EdgeID: "e2", Source: "1", Target: "3", Change: ChangeAdded},
+		{EdgeID: "e1", Source: "1", Target: "2", Change: ChangeRemoved},
+	}
+	if !reflect.DeepEqual(diff.Edges, wantEdges) {
+		t.Errorf("edges = %+v, want %+v", diff.Edges, wantEdges)
+	}
+
+	if diff := Diff(from, from); !diff.Identical || len(diff.Nodes) != 0 {
+		t.Errorf("Diff of a pathway with itself = %+v, want identical", diff)
+	}
+}
+
+func TestDiffReportsUnmodelledFields(t *testing.T) {
+	from := model.GetPathwayResponse{Nodes: []model.Node{{ID: "1", Extra: model.ExtraFields{"position": json.RawMessage(`{"x":1,"y":2}`)}}}}
+	to := model.GetPathwayResponse{Nodes: []model.Node{{ID: "1", Extra: model.ExtraFields{"position": json.RawMessage(`{ "y": 2, "x": 3 }`)},
+		Data: model.NodeData{Extra: model.ExtraFields{"kb": json.RawMessage(`"kb-1"`)}}}}}
+
+	diff := Diff(from, to)
+	if len(diff.Nodes) != 1 {
+		t.Fatalf("got %d node changes, want 1", len(diff.Nodes))
+	}
+	want := []model.PathwayFieldChange{
+		{Field: "position", From: map[string]interface{}{"x": 1.0, "y": 2.0}, To: map[string]interface{}{"x": 3.0, "y": 2.0}},
+		{Field: "data.kb", From: nil, To: "kb-1"},
+	}
+	if !reflect.DeepEqual(diff.Nodes[0].Fields, want) {
+		t.Errorf("fields = %#v, want %#v", diff.Nodes[0].Fields, want)
+	}
+
+	if diff := Diff(to, to); !diff.Identical {
+		t.Errorf("Diff of a pathway with itself = %+v, want identical", diff)
+	}
+}
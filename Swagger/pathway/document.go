@@ -0,0 +1,151 @@
+package pathway
+
+import (
+	"bland/model"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DocumentVersion is the format version of the documents written by Marshal.
+const DocumentVersion = 1
+
+// Document formats.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Marshal encodes a pathway document as indented JSON or as YAML. YAML
+// documents use the same keys, in the same order, as JSON, so either can be
+// converted to the other.
+func Marshal(doc model.PathwayDocument, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatJSON:
+		return append(data, '\n'), nil
+	case FormatYAML:
+		// JSON is YAML, so decoding it into a node keeps the order of the keys
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		blockStyle(&node)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("pathway: unknown document format %q", format)
+	}
+}
+
+// Unmarshal decodes a pathway document written as JSON or YAML.
+func Unmarshal(data []byte, format string) (model.PathwayDocument, error) {
+	var doc model.PathwayDocument
+	switch format {
+	case FormatJSON:
+	case FormatYAML:
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return doc, err
+		}
+		converted, err := json.Marshal(generic)
+		if err != nil {
+			return doc, err
+		}
+		data = converted
+	default:
+		return doc, fmt.Errorf("pathway: unknown document format %q", format)
+	}
+	err := json.Unmarshal(data, &doc)
+	return doc, err
+}
+
+// JoinFolderPath joins folder names into a document folder path. Names are
+// separated by "/"; a "/" or "\" inside a name is escaped with a backslash.
+func JoinFolderPath(names []string) string {
+	escaped := make([]string, len(names))
+	for i, name := range names {
+		escaped[i] = folderNameEscaper.Replace(name)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// SplitFolderPath returns the folder names of a document folder path written
+// by JoinFolderPath. Names are trimmed and empty names are dropped, so
+// "Support//Inbound/" is the same path as "Support/Inbound".
+func SplitFolderPath(path string) []string {
+	var names []string
+	var name strings.Builder
+	flush := func() {
+		if trimmed := strings.TrimSpace(name.String()); trimmed != "" {
+			names = append(names, trimmed)
+		}
+		name.Reset()
+	}
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			name.WriteByte(path[i])
+		case path[i] == '/':
+			flush()
+		default:
+			name.WriteByte(path[i])
+		}
+	}
+	flush()
+	return names
+}
+
+var folderNameEscaper = strings.NewReplacer(`\`, `\\`, "/", `\/`)
+
+// blockStyle clears the JSON flow and quoting styles of a node and its
+// children, so they are written in the usual block style.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// RemapIDs returns copies of nodes and edges with a new ID from newID for every
+// node and edge, and edges pointing at the new node IDs. Edges whose source or
+// target is not a node keep it. The returned map gives the new ID of each node.
+func RemapIDs(nodes []model.Node, edges []model.Edge, newID func() string) ([]model.Node, []model.Edge, map[string]string) {
+	ids := make(map[string]string, len(nodes))
+	remapped := make([]model.Node, len(nodes))
+	for i, node := range nodes {
+		if _, ok := ids[node.ID]; !ok {
+			ids[node.ID] = newID()
+		}
+		node.ID = ids[node.ID]
+		remapped[i] = node
+	}
+
+	remappedEdges := make([]model.Edge, len(edges))
+	for i, edge := range edges {
+		edge.ID = newID()
+		if id, ok := ids[edge.Source]; ok {
+			edge.Source = id
+		}
+		if id, ok := ids[edge.Target]; ok {
+			edge.Target = id
+		}
+		remappedEdges[i] = edge
+	}
+	return remapped, remappedEdges, ids
+}
@@ -0,0 +1,127 @@
+package pathway
+
+import (
+	"bland/model"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	prompt, label := "Greet the caller", "next"
+	doc := model.PathwayDocument{
+		Version:    DocumentVersion,
+		Name:       "Support",
+		FolderPath: "Support/Inbound",
+		Nodes: []model.Node{
+			{
+				ID:   "1",
+				Type: "Default",
+				Data: model.NodeData{
+					Name:         "Start",
+					Prompt:       &prompt,
+					IsStart:      true,
+					ModelOptions: model.ModelOptions{ModelType: "smart", Temperature: 0.2, Extra: model.ExtraFields{"interruptionThreshold": json.RawMessage(`500`)}},
+					Extra:        model.ExtraFields{"extractVars": json.RawMessage(`[["name","string","The caller's name"]]`)},
+				},
+				Extra: model.ExtraFields{"position": json.RawMessage(`{"x":120.5,"y":-40}`)},
+			},
+			{ID: "2", Type: "End Call", Data: model.NodeData{Name: "Goodbye"}},
+		},
+		Edges: []model.Edge{
+			{ID: "e1", Source: "1", Target: "2", Label: &label, Extra: model.ExtraFields{"data": json.RawMessage(`{"isHighlighted":false}`), "animated": json.RawMessage(`true`)}},
+		},
+	}
+
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Marshal(doc, format)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			back, err := Unmarshal(data, format)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got, want := mustJSON(t, back), mustJSON(t, doc); got != want {
+				t.Errorf("round trip changed the document\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestMarshalUnknownFormat(t *testing.T) {
+	if _, err := Marshal(model.PathwayDocument{}, "xml"); err == nil {
+		t.Error("Marshal with format xml succeeded, want an error")
+	}
+	if _, err := Unmarshal([]byte("<pathway/>"), "xml"); err == nil {
+		t.Error("Unmarshal with format xml succeeded, want an error")
+	}
+}
+
+func TestFolderPath(t *testing.T) {
+	tests := []struct {
+		names []string
+		path  string
+	}{
+		{nil, ""},
+		{[]string{"Support"}, "Support"},
+		{[]string{"Support", "Inbound"}, "Support/Inbound"},
+		{[]string{"Sales", "Q1/Q2"}, `Sales/Q1\/Q2`},
+		{[]string{`C:\Calls`, "EU"}, `C:\\Calls/EU`},
+		{[]string{`ends with \`, "next"}, `ends with \\/next`},
+	}
+	for _, tt := range tests {
+		if got := JoinFolderPath(tt.names); got != tt.path {
+			t.Errorf("JoinFolderPath(%q) = %q, want %q", tt.names, got, tt.path)
+		}
+		if got := SplitFolderPath(tt.path); !reflect.DeepEqual(got, tt.names) {
+			t.Errorf("SplitFolderPath(%q) = %q, want %q", tt.path, got, tt.names)
+		}
+	}
+
+	// Paths written by hand are read leniently
+	for path, want := range map[string][]string{
+		" Support // Inbound/": {"Support", "Inbound"},
+		`Sales\`:               {`Sales\`},
+		"/":                    nil,
+	} {
+		if got := SplitFolderPath(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("SplitFolderPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRemapIDs(t *testing.T) {
+	nodes := []model.Node{{ID: "a"}, {ID: "b"}, {ID: "a"}}
+	edges := []model.Edge{{ID: "e1", Source: "a", Target: "b"}, {ID: "e2", Source: "b", Target: "outside"}}
+
+	n := 0
+	newID := func() string { n++; return fmt.Sprintf("id-%d", n) }
+	gotNodes, gotEdges, ids := RemapIDs(nodes, edges, newID)
+
+	wantNodes := []model.Node{{ID: "id-1"}, {ID: "id-2"}, {ID: "id-1"}}
+	wantEdges := []model.Edge{{ID: "id-3", Source: "id-1", Target: "id-2"}, {ID: "id-4", Source: "id-2", Target: "outside"}}
+	if !reflect.DeepEqual(gotNodes, wantNodes) {
+		t.Errorf("nodes = %+v, want %+v", gotNodes, wantNodes)
+	}
+	if !reflect.DeepEqual(gotEdges, wantEdges) {
+		t.Errorf("edges = %+v, want %+v", gotEdges, wantEdges)
+	}
+	if want := map[string]string{"a": "id-1", "b": "id-2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if nodes[0].ID != "a" || edges[0].Source != "a" {
+		t.Error("RemapIDs changed its arguments")
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,171 @@
+// Package pathway checks conversational pathway graphs before they are sent
+// to Bland, compares versions of a pathway and reads and writes pathways as
+// portable documents.
+//
+// A pathway is valid when it has exactly one start node, unique node and edge
+// IDs, edges between existing nodes, every node reachable from the start node
+// and a prompt or static text on every node that speaks. Nodes other than End Call and
+// Transfer Call nodes without outgoing edges are reported as dead ends; they
+// are warnings and do not make a pathway invalid. Global nodes are entered
+// from anywhere in the conversation, so they are exempt from the reachability
+// and dead-end checks.
+package pathway
+
+import (
+	"bland/model"
+	"fmt"
+	"strings"
+)
+
+// Issue codes reported by Validate.
+const (
+	IssueNoStartNode        = "no_start_node"
+	IssueMultipleStartNodes = "multiple_start_nodes"
+	IssueMissingID          = "missing_id"
+	IssueDuplicateNodeID    = "duplicate_node_id"
+	IssueDuplicateEdgeID    = "duplicate_edge_id"
+	IssueUnknownSource      = "unknown_source"
+	IssueUnknownTarget      = "unknown_target"
+	IssueUnreachable        = "unreachable_node"
+	IssueDeadEnd            = "dead_end"
+	IssueMissingPrompt      = "missing_prompt"
+)
+
+// Node types with a special meaning for validation.
+const (
+	TypeDefault      = "Default"
+	TypeEndCall      = "End Call"
+	TypeTransferCall = "Transfer Call"
+)
+
+// Validate checks a pathway graph and returns its issues, errors and warnings
+// in the order of the nodes and edges they concern.
+func Validate(nodes []model.Node, edges []model.Edge) []model.PathwayIssue {
+	var issues []model.PathwayIssue
+	add := func(severity, code, field, nodeID, edgeID, message string) {
+		issues = append(issues, model.PathwayIssue{Code: code, Severity: severity, Field: field, NodeID: nodeID, EdgeID: edgeID, Message: message})
+	}
+
+	// Node IDs, start nodes and prompts
+	nodeIndex := make(map[string]int, len(nodes))
+	var starts []int
+	for i, node := range nodes {
+		field := fmt.Sprintf("nodes[%d]", i)
+		switch _, seen := nodeIndex[node.ID]; {
+		case node.ID == "":
+			add(model.PathwayIssueError, IssueMissingID, field+".id", "", "", "node has no id")
+		case seen:
+			add(model.PathwayIssueError, IssueDuplicateNodeID, field+".id", node.ID, "", fmt.Sprintf("node id %q is used by another node", node.ID))
+		default:
+			nodeIndex[node.ID] = i
+		}
+		if node.Data.IsStart {
+			starts = append(starts, i)
+		}
+		if speaks(node) && blank(node.Data.Prompt) && blank(node.Data.Text) {
+			add(model.PathwayIssueError, IssueMissingPrompt, field+".data.prompt", node.ID, "", fmt.Sprintf("node %q has no prompt or text", name(node)))
+		}
+	}
+	switch {
+	case len(starts) == 0:
+		add(model.PathwayIssueError, IssueNoStartNode, "nodes", "", "", "no node is marked as the start node")
+	case len(starts) > 1:
+		for _, i := range starts {
+			add(model.PathwayIssueError, IssueMultipleStartNodes, fmt.Sprintf("nodes[%d].data.isStart", i), nodes[i].ID, "",
+				fmt.Sprintf("node %q is one of %d start nodes", name(nodes[i]), len(starts)))
+		}
+	}
+
+	// Edge IDs and endpoints
+	edgeIDs := make(map[string]bool, len(edges))
+	outgoing := make(map[string][]string, len(nodes))
+	for i, edge := range edges {
+		field := fmt.Sprintf("edges[%d]", i)
+		switch {
+		case edge.ID == "":
+			add(model.PathwayIssueError, IssueMissingID, field+".id", "", "", "edge has no id")
+		case edgeIDs[edge.ID]:
+			add(model.PathwayIssueError, IssueDuplicateEdgeID, field+".id", "", edge.ID, fmt.Sprintf("edge id %q is used by another edge", edge.ID))
+		}
+		edgeIDs[edge.ID] = true
+
+		_, sourceOK := nodeIndex[edge.Source]
+		_, targetOK := nodeIndex[edge.Target]
+		if !sourceOK {
+			add(model.PathwayIssueError, IssueUnknownSource, field+".source", "", edge.ID, fmt.Sprintf("edge source %q is not a node", edge.Source))
+		}
+		if !targetOK {
+			add(model.PathwayIssueError, IssueUnknownTarget, field+".target", "", edge.ID, fmt.Sprintf("edge target %q is not a node", edge.Target))
+		}
+		if sourceOK && targetOK {
+			outgoing[edge.Source] = append(outgoing[edge.Source], edge.Target)
+		}
+	}
+
+	// Reachability from the start node and dead ends
+	reached := make(map[string]bool, len(nodes))
+	queue := make([]string, 0, len(nodes))
+	for _, i := range starts {
+		if nodes[i].ID != "" && !reached[nodes[i].ID] {
+			reached[nodes[i].ID] = true
+			queue = append(queue, nodes[i].ID)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, target := range outgoing[id] {
+			if !reached[target] {
+				reached[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	for i, node := range nodes {
+		if node.ID == "" || nodeIndex[node.ID] != i || node.Data.IsGlobal {
+			continue
+		}
+		field := fmt.Sprintf("nodes[%d]", i)
+		if len(starts) > 0 && !reached[node.ID] {
+			add(model.PathwayIssueError, IssueUnreachable, field, node.ID, "", fmt.Sprintf("node %q cannot be reached from the start node", name(node)))
+		}
+		if len(outgoing[node.ID]) == 0 && !terminal(node) {
+			add(model.PathwayIssueWarning, IssueDeadEnd, field, node.ID, "", fmt.Sprintf("node %q has no outgoing edges and does not end the call", name(node)))
+		}
+	}
+	return issues
+}
+
+// Valid reports whether issues contain no errors.
+func Valid(issues []model.PathwayIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == model.PathwayIssueError {
+			return false
+		}
+	}
+	return true
+}
+
+// speaks reports whether a node talks to the caller and therefore needs a
+// prompt or static text.
+func speaks(node model.Node) bool {
+	return node.Type == "" || node.Type == TypeDefault || node.Type == TypeEndCall
+}
+
+// blank reports whether s is unset or only white space.
+func blank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
+
+// terminal reports whether a node ends the conversation on this agent.
+func terminal(node model.Node) bool {
+	return node.Type == TypeEndCall || node.Type == TypeTransferCall
+}
+
+// name returns the name of a node for messages, or its ID when it has none.
+func name(node model.Node) string {
+	if node.Data.Name != "" {
+		return node.Data.Name
+	}
+	return node.ID
+}
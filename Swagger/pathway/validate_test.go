@@ -0,0 +1,116 @@
+package pathway
+
+import (
+	"bland/model"
+	"testing"
+)
+
+func str(s string) *string { return &s }
+
+func TestValidate(t *testing.T) {
+	start := model.Node{ID: "1", Type: TypeDefault, Data: model.NodeData{Name: "Start", IsStart: true, Prompt: str("Greet the caller")}}
+	end := model.Node{ID: "2", Type: TypeEndCall, Data: model.NodeData{Name: "Goodbye", Prompt: str("Say goodbye")}}
+	toEnd := model.Edge{ID: "e1", Source: "1", Target: "2"}
+
+	tests := []struct {
+		name  string
+		nodes []model.Node
+		edges []model.Edge
+		want  []string // Issue codes, in any order
+		valid bool
+	}{
+		{name: "valid", nodes: []model.Node{start, end}, edges: []model.Edge{toEnd}, valid: true},
+		{name: "empty", want: []string{IssueNoStartNode}},
+		{
+			name:  "static text instead of a prompt",
+			nodes: []model.Node{start, {ID: "2", Type: TypeEndCall, Data: model.NodeData{Name: "Goodbye", Text: str("Thanks for calling!")}}},
+			edges: []model.Edge{toEnd},
+			valid: true,
+		},
+		{
+			name:  "neither prompt nor text",
+			nodes: []model.Node{start, {ID: "2", Type: TypeEndCall, Data: model.NodeData{Name: "Goodbye", Prompt: str(" "), Text: str("")}}},
+			edges: []model.Edge{toEnd},
+			want:  []string{IssueMissingPrompt},
+		},
+		{
+			name:  "nodes that do not speak need no prompt",
+			nodes: []model.Node{start, {ID: "2", Type: TypeTransferCall, Data: model.NodeData{Name: "Transfer"}}},
+			edges: []model.Edge{{ID: "e1", Source: "1", Target: "2"}},
+			valid: true,
+		},
+		{
+			name:  "two start nodes",
+			nodes: []model.Node{start, {ID: "2", Type: TypeEndCall, Data: model.NodeData{Name: "Goodbye", IsStart: true, Prompt: str("Bye")}}},
+			edges: []model.Edge{toEnd},
+			want:  []string{IssueMultipleStartNodes, IssueMultipleStartNodes},
+		},
+		{
+			name:  "duplicate and missing IDs",
+			nodes: []model.Node{start, end, {ID: "2", Type: TypeEndCall, Data: model.NodeData{Prompt: str("Bye")}}, {Type: TypeEndCall, Data: model.NodeData{Prompt: str("Bye")}}},
+			edges: []model.Edge{toEnd, {ID: "e1", Source: "1", Target: "2"}},
+			want:  []string{IssueDuplicateNodeID, IssueMissingID, IssueDuplicateEdgeID},
+		},
+		{
+			name:  "edges to unknown nodes",
+			nodes: []model.Node{start, end},
+			edges: []model.Edge{toEnd, {ID: "e2", Source: "x", Target: "y"}},
+			want:  []string{IssueUnknownSource, IssueUnknownTarget},
+		},
+		{
+			name:  "unreachable node and dead end",
+			nodes: []model.Node{start, end, {ID: "3", Type: TypeDefault, Data: model.NodeData{Name: "Orphan", Prompt: str("Hello?")}}, {ID: "4", Type: "Webhook", Data: model.NodeData{Name: "Hook"}}},
+			edges: []model.Edge{toEnd, {ID: "e2", Source: "1", Target: "4"}},
+			want:  []string{IssueUnreachable, IssueDeadEnd, IssueDeadEnd},
+		},
+		{
+			name:  "global nodes are exempt from reachability",
+			nodes: []model.Node{start, end, {ID: "g", Type: TypeDefault, Data: model.NodeData{Name: "Help", IsGlobal: true, Prompt: str("Offer help")}}},
+			edges: []model.Edge{toEnd},
+			valid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Validate(tt.nodes, tt.edges)
+			var codes []string
+			for _, issue := range issues {
+				codes = append(codes, issue.Code)
+			}
+			if !sameCodes(codes, tt.want) {
+				t.Errorf("issues = %+v, want codes %v", issues, tt.want)
+			}
+			if got := Valid(issues); got != tt.valid {
+				t.Errorf("Valid() = %v, want %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+// sameCodes reports whether got and want hold the same codes, in any order.
+func sameCodes(got, want []string) bool {
+	count := make(map[string]int)
+	for _, code := range got {
+		count[code]++
+	}
+	for _, code := range want {
+		count[code]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestValidIgnoresWarnings(t *testing.T) {
+	issues := []model.PathwayIssue{{Code: IssueDeadEnd, Severity: model.PathwayIssueWarning}}
+	if !Valid(issues) {
+		t.Error("Valid() = false for warnings only, want true")
+	}
+	if issues = append(issues, model.PathwayIssue{Code: IssueUnreachable, Severity: model.PathwayIssueError}); Valid(issues) {
+		t.Error("Valid() = true with an error, want false")
+	}
+}
@@ -0,0 +1,194 @@
+// Package phone validates and normalizes phone numbers to E.164.
+//
+// Numbers written in international form (+44..., 0044...) are parsed using
+// the calling code. Numbers written in national form (415-555-2671,
+// 020 7946 0018) are interpreted in a default region. Premium-rate ranges and
+// short codes are rejected because outbound calls to them are never wanted.
+package phone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error codes returned in Error.Code.
+const (
+	CodeEmpty         = "empty"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidLength = "invalid_length"
+	CodeUnknownRegion = "unknown_region"
+	CodeShortCode     = "short_code"
+	CodePremiumRate   = "premium_rate"
+)
+
+// Error describes why a phone number was rejected.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// region holds the numbering rules for a country.
+type region struct {
+	callingCode string
+	trunkPrefix string   // national prefix dropped when converting to E.164
+	minLength   int      // minimum national significant number length
+	maxLength   int      // maximum national significant number length
+	premium     []string // national number prefixes of premium-rate ranges
+}
+
+// nanp covers the countries sharing calling code 1.
+var nanp = region{callingCode: "1", minLength: 10, maxLength: 10, premium: []string{"900", "976"}}
+
+// regions maps ISO 3166-1 alpha-2 codes to numbering rules.
+var regions = map[string]region{
+	"US": nanp,
+	"CA": nanp,
+	"PR": nanp,
+	"GB": {callingCode: "44", trunkPrefix: "0", minLength: 9, maxLength: 10, premium: []string{"9", "118", "871", "872", "873"}},
+	"IE": {callingCode: "353", trunkPrefix: "0", minLength: 7, maxLength: 9, premium: []string{"15"}},
+	"AU": {callingCode: "61", trunkPrefix: "0", minLength: 9, maxLength: 9, premium: []string{"19"}},
+	"NZ": {callingCode: "64", trunkPrefix: "0", minLength: 8, maxLength: 10, premium: []string{"900"}},
+	"DE": {callingCode: "49", trunkPrefix: "0", minLength: 6, maxLength: 13, premium: []string{"900", "137", "118"}},
+	"FR": {callingCode: "33", trunkPrefix: "0", minLength: 9, maxLength: 9, premium: []string{"89", "81", "82"}},
+	"ES": {callingCode: "34", minLength: 9, maxLength: 9, premium: []string{"80", "90"}},
+	"IT": {callingCode: "39", minLength: 6, maxLength: 11, premium: []string{"89"}},
+	"NL": {callingCode: "31", trunkPrefix: "0", minLength: 9, maxLength: 9, premium: []string{"906", "909"}},
+	"MX": {callingCode: "52", minLength: 10, maxLength: 10, premium: []string{"900"}},
+	"BR": {callingCode: "55", trunkPrefix: "0", minLength: 10, maxLength: 11, premium: []string{"900"}},
+	"IN": {callingCode: "91", trunkPrefix: "0", minLength: 10, maxLength: 10},
+	"ZA": {callingCode: "27", trunkPrefix: "0", minLength: 9, maxLength: 9, premium: []string{"86", "90"}},
+}
+
+// byCallingCode maps calling codes to numbering rules.
+var byCallingCode = func() map[string]region {
+	out := make(map[string]region)
+	for _, r := range regions {
+		out[r.callingCode] = r
+	}
+	return out
+}()
+
+// minShortCodeLength is the number of digits below which a national number is treated as a short code.
+const minShortCodeLength = 7
+
+// SupportedRegion reports whether region can be used as a default region.
+func SupportedRegion(code string) bool {
+	_, ok := regions[strings.ToUpper(code)]
+	return ok
+}
+
+// Normalize validates raw and returns it in E.164 form, e.g. +14155552671.
+// defaultRegion is the ISO 3166-1 alpha-2 code used for numbers in national form.
+// The returned error is always an *Error.
+func Normalize(raw, defaultRegion string) (string, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return "", err
+	}
+
+	if !international {
+		r, ok := regions[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return "", newError(CodeUnknownRegion, "phone number %q has no country code and default region %q is not supported", raw, defaultRegion)
+		}
+		// NANP callers often dial 011 for international numbers.
+		if r.callingCode == "1" && strings.HasPrefix(digits, "011") {
+			return normalizeInternational(raw, digits[3:])
+		}
+		return normalizeNational(raw, digits, r)
+	}
+	return normalizeInternational(raw, digits)
+}
+
+// clean strips formatting characters and reports whether the number was in international form.
+func clean(raw string) (string, bool, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", false, newError(CodeEmpty, "phone number is required")
+	}
+
+	international := strings.HasPrefix(s, "+")
+	s = strings.TrimPrefix(s, "+")
+
+	var b strings.Builder
+	for _, ch := range s {
+		switch {
+		case ch >= '0' && ch <= '9':
+			b.WriteRune(ch)
+		case ch == ' ' || ch == '-' || ch == '.' || ch == '(' || ch == ')':
+		default:
+			return "", false, newError(CodeInvalidFormat, "phone number %q contains invalid character %q", raw, ch)
+		}
+	}
+	digits := b.String()
+	if digits == "" {
+		return "", false, newError(CodeInvalidFormat, "phone number %q contains no digits", raw)
+	}
+
+	if !international && strings.HasPrefix(digits, "00") {
+		return digits[2:], true, nil
+	}
+	return digits, international, nil
+}
+
+func normalizeNational(raw, digits string, r region) (string, error) {
+	if len(digits) < minShortCodeLength {
+		return "", newError(CodeShortCode, "phone number %q is a short code", raw)
+	}
+	if r.callingCode == "1" && len(digits) == 11 && digits[0] == '1' {
+		digits = digits[1:]
+	} else if r.trunkPrefix != "" {
+		digits = strings.TrimPrefix(digits, r.trunkPrefix)
+	}
+	return finish(raw, digits, r)
+}
+
+func normalizeInternational(raw, digits string) (string, error) {
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		if r, ok := byCallingCode[digits[:n]]; ok {
+			return finish(raw, digits[n:], r)
+		}
+	}
+
+	// Unknown calling code: only the generic E.164 length rules apply.
+	if len(digits) < 8 || len(digits) > 15 {
+		return "", newError(CodeInvalidLength, "phone number %q must have between 8 and 15 digits", raw)
+	}
+	return "+" + digits, nil
+}
+
+// finish validates a national significant number against r and builds the E.164 string.
+func finish(raw, national string, r region) (string, error) {
+	if len(national) < r.minLength || len(national) > r.maxLength {
+		if r.minLength == r.maxLength {
+			return "", newError(CodeInvalidLength, "phone number %q must have %d digits after country code +%s", raw, r.minLength, r.callingCode)
+		}
+		return "", newError(CodeInvalidLength, "phone number %q must have between %d and %d digits after country code +%s", raw, r.minLength, r.maxLength, r.callingCode)
+	}
+
+	if r.callingCode == "1" {
+		// Area code and exchange cannot start with 0 or 1, and N11 codes are service numbers.
+		if national[0] < '2' || national[3] < '2' || national[1:3] == "11" {
+			return "", newError(CodeInvalidFormat, "phone number %q is not a valid North American number", raw)
+		}
+	}
+
+	for _, prefix := range r.premium {
+		if strings.HasPrefix(national, prefix) {
+			return "", newError(CodePremiumRate, "phone number %q is in a premium-rate range", raw)
+		}
+	}
+
+	if len(r.callingCode)+len(national) > 15 {
+		return "", newError(CodeInvalidLength, "phone number %q is longer than 15 digits", raw)
+	}
+	return "+" + r.callingCode + national, nil
+}
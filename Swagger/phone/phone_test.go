@@ -0,0 +1,49 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		region string
+		want   string
+		code   string
+	}{
+		{"US national with punctuation", "(415) 555-2671", "US", "+14155552671", ""},
+		{"US national with trunk prefix", "1-415-555-2671", "US", "+14155552671", ""},
+		{"international ignores the default region", "+1 415 555 2671", "GB", "+14155552671", ""},
+		{"US international dialling prefix", "011 44 20 7946 0018", "US", "+442079460018", ""},
+		{"00 international dialling prefix", "0044 20 7946 0018", "US", "+442079460018", ""},
+		{"GB national with trunk prefix", "020 7946 0018", "GB", "+442079460018", ""},
+		{"AU mobile", "0412 345 678", "AU", "+61412345678", ""},
+		{"country without a numbering plan", "+86 138 0013 8000", "US", "+8613800138000", ""},
+		{"US premium rate", "+1 900 555 2671", "US", "", CodePremiumRate},
+		{"GB premium rate", "09012345678", "GB", "", CodePremiumRate},
+		{"short code", "55555", "US", "", CodeShortCode},
+		{"too short", "415-555-267", "US", "", CodeInvalidLength},
+		{"letters", "415-abc-2671", "US", "", CodeInvalidFormat},
+		{"US area code starting with 1", "+1 115 555 2671", "US", "", CodeInvalidFormat},
+		{"unknown default region", "4155552671", "XX", "", CodeUnknownRegion},
+		{"empty", "", "US", "", CodeEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.raw, tt.region)
+			if tt.code == "" {
+				if err != nil || got != tt.want {
+					t.Fatalf("Normalize(%q, %q) = %q, %v; want %q", tt.raw, tt.region, got, err, tt.want)
+				}
+				return
+			}
+			var phoneErr *Error
+			if !errors.As(err, &phoneErr) || phoneErr.Code != tt.code {
+				t.Fatalf("Normalize(%q, %q) = %q, %v; want error code %s", tt.raw, tt.region, got, err, tt.code)
+			}
+		})
+	}
+}
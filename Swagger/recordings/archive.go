@@ -0,0 +1,224 @@
+// Package recordings keeps local copies of call recordings.
+//
+// Each recording is stored as <dir>/<call_id><ext>, where the extension is
+// chosen from the content type. Recordings are written to a temporary file
+// and renamed into place, so a partial download is never served. Prune
+// applies the retention policy: recordings older than MaxAge are removed,
+// then the oldest ones are removed until the archive fits in MaxBytes.
+package recordings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Open when no recording is archived for the call.
+var ErrNotFound = errors.New("recordings: recording not archived")
+
+// validCallID matches the call IDs that can safely be used as file names.
+var validCallID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// extensions maps the audio content types Bland serves to file extensions.
+var extensions = map[string]string{
+	"audio/mpeg":   ".mp3",
+	"audio/mp3":    ".mp3",
+	"audio/wav":    ".wav",
+	"audio/wave":   ".wav",
+	"audio/x-wav":  ".wav",
+	"audio/ogg":    ".ogg",
+	"audio/webm":   ".webm",
+	"audio/mp4":    ".m4a",
+	"audio/x-m4a":  ".m4a",
+	"audio/aac":    ".aac",
+	"audio/flac":   ".flac",
+	"audio/x-flac": ".flac",
+}
+
+// Policy is the retention policy of an archive. Zero values disable a limit.
+type Policy struct {
+	MaxAge   time.Duration // Recordings older than this are removed
+	MaxBytes int64         // The oldest recordings are removed while the archive is larger than this
+}
+
+// Archive is a directory of call recordings. It is safe for concurrent use.
+type Archive struct {
+	dir    string
+	policy Policy
+	now    func() time.Time
+
+	mu sync.Mutex // Serializes writes and pruning
+}
+
+// Recording is a file of an archived recording. The caller must close File.
+type Recording struct {
+	File    *os.File
+	Name    string    // File name, e.g. call-123.mp3
+	ModTime time.Time // When the recording was archived
+	Size    int64
+}
+
+// Open returns an archive stored in dir, creating dir if needed.
+func Open(dir string, policy Policy) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("recordings: create %s: %w", dir, err)
+	}
+	return &Archive{dir: dir, policy: policy, now: time.Now}, nil
+}
+
+// Has reports whether a recording is archived for the call.
+func (a *Archive) Has(callID string) bool {
+	_, err := a.path(callID)
+	return err == nil
+}
+
+// Open opens the archived recording of a call, or returns ErrNotFound.
+func (a *Archive) Open(callID string) (*Recording, error) {
+	path, err := a.path(callID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("recordings: open %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("recordings: open %s: %w", path, err)
+	}
+	return &Recording{File: file, Name: filepath.Base(path), ModTime: info.ModTime(), Size: info.Size()}, nil
+}
+
+// Store writes the recording of a call read from r, replacing any previous one,
+// and returns the number of bytes written.
+func (a *Archive) Store(callID, contentType string, r io.Reader) (int64, error) {
+	if !validCallID.MatchString(callID) {
+		return 0, fmt.Errorf("recordings: invalid call ID %q", callID)
+	}
+	path := filepath.Join(a.dir, callID+extension(contentType))
+
+	tmp, err := os.CreateTemp(a.dir, "."+callID+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("recordings: write %s: %w", path, err)
+	}
+	n, err := io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return n, fmt.Errorf("recordings: write %s: %w", path, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if previous, err := a.path(callID); err == nil && previous != path {
+		os.Remove(previous)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return n, fmt.Errorf("recordings: write %s: %w", path, err)
+	}
+	return n, nil
+}
+
+// Prune removes the recordings the retention policy no longer allows and
+// returns how many were removed.
+func (a *Archive) Prune() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return 0, fmt.Errorf("recordings: list %s: %w", a.dir, err)
+	}
+	var files []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, info)
+		}
+	}
+	// Newest first, so the size limit keeps the most recent recordings.
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime().After(files[j].ModTime()) })
+
+	removed := 0
+	var total int64
+	now := a.now()
+	for _, info := range files {
+		expired := a.policy.MaxAge > 0 && now.Sub(info.ModTime()) > a.policy.MaxAge
+		oversize := a.policy.MaxBytes > 0 && total+info.Size() > a.policy.MaxBytes
+		if !expired && !oversize {
+			total += info.Size()
+			continue
+		}
+		if err := os.Remove(filepath.Join(a.dir, info.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("recordings: remove %s: %w", info.Name(), err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Run prunes the archive every interval until ctx is cancelled.
+func (a *Archive) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if removed, err := a.Prune(); err != nil {
+			log.Printf("Error pruning recordings: %v", err)
+		} else if removed > 0 {
+			log.Printf("Removed %d archived recordings", removed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// path returns the file of the recording archived for the call.
+func (a *Archive) path(callID string) (string, error) {
+	if !validCallID.MatchString(callID) {
+		return "", ErrNotFound
+	}
+	matches, _ := filepath.Glob(filepath.Join(a.dir, callID+".*"))
+	if len(matches) == 0 {
+		return "", ErrNotFound
+	}
+	return matches[0], nil
+}
+
+// extension returns the file extension for an audio content type. Bland
+// records calls as MP3, which is assumed when the type is not recognised.
+func extension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ".mp3"
+	}
+	if ext, ok := extensions[mediaType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 && strings.HasPrefix(mediaType, "audio/") {
+		return exts[0]
+	}
+	return ".mp3"
+}
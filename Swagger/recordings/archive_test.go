@@ -0,0 +1,104 @@
+package recordings
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreAndOpen(t *testing.T) {
+	a, err := Open(t.TempDir(), Policy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Store("call-1", "audio/wav", strings.NewReader("first")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Store("call-1", "audio/mpeg", strings.NewReader("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	recording, err := a.Open("call-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer recording.File.Close()
+	data, _ := io.ReadAll(recording.File)
+	if recording.Name != "call-1.mp3" || string(data) != "second" {
+		t.Errorf("got %s holding %q, want call-1.mp3 holding the second recording", recording.Name, data)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(a.dir, "*")); len(matches) != 1 {
+		t.Errorf("archive holds %v, want only the latest recording", matches)
+	}
+
+	for _, callID := range []string{"call-2", "../call-1", ""} {
+		if _, err := a.Open(callID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Open(%q) = %v, want ErrNotFound", callID, err)
+		}
+	}
+	if _, err := a.Store("../call-1", "audio/mpeg", strings.NewReader("x")); err == nil {
+		t.Error("Store with a path in the call ID succeeded")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Date(2024, 9, 26, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []string
+	}{
+		{"no limits", Policy{}, []string{"new", "old", "older"}},
+		{"max age", Policy{MaxAge: 36 * time.Hour}, []string{"new", "old"}},
+		{"max size", Policy{MaxBytes: 8}, []string{"new", "old"}},
+		{"both", Policy{MaxAge: 36 * time.Hour, MaxBytes: 5}, []string{"new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Open(t.TempDir(), tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a.now = func() time.Time { return now }
+			for age, callID := range []string{"new", "old", "older"} {
+				if _, err := a.Store(callID, "audio/mpeg", strings.NewReader("four")); err != nil {
+					t.Fatal(err)
+				}
+				modTime := now.Add(-time.Duration(age) * 24 * time.Hour)
+				if err := os.Chtimes(filepath.Join(a.dir, callID+".mp3"), modTime, modTime); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := a.Prune(); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, callID := range []string{"new", "old", "older"} {
+				if a.Has(callID) {
+					got = append(got, callID)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtension(t *testing.T) {
+	for contentType, want := range map[string]string{
+		"audio/mpeg":               ".mp3",
+		"audio/wav; charset=utf-8": ".wav",
+		"application/octet-stream": ".mp3",
+		"":                         ".mp3",
+	} {
+		if got := extension(contentType); got != want {
+			t.Errorf("extension(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Package scheduler stores calls for later dispatch and sends them when they
+// are due, only inside each contact's local calling window.
+//
+// Scheduled calls are persisted with the storage package, including the
+// Authorization token they will be sent with, so they survive restarts. The
+// token is encrypted with Options.Sealer before it is stored. Every call
+// belongs to an owner and is only returned or changed for that owner.
+package scheduler
+
+import (
+	"bland/model"
+	"bland/storage"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Errors returned by Scheduler methods.
+var (
+	ErrNotFound         = errors.New("scheduled call not found")
+	ErrNotReschedulable = errors.New("only scheduled or failed calls can be rescheduled")
+	ErrNotCancellable   = errors.New("only scheduled calls can be cancelled")
+
+	errClaimed = errors.New("scheduled call is no longer pending")
+)
+
+// DispatchFunc sends a call to Bland with the given Authorization token.
+type DispatchFunc func(ctx context.Context, bearerToken string, call model.SendCall) (*model.CallResponse, error)
+
+// ErrorFunc converts a dispatch error to the error stored on a failed call.
+type ErrorFunc func(err error) *model.ErrorResponse
+
+// record is the stored form of a scheduled call. The owner and the token are
+// never returned by the API.
+type record struct {
+	model.ScheduledCall
+	Owner       string `json:"owner"`
+	SealedToken string `json:"sealed_token"`
+}
+
+// Options configures a Scheduler.
+type Options struct {
+	DefaultTimezone string              // IANA zone used when a request has none
+	DefaultWindow   model.CallingWindow // Calling window used when a request has none
+	PollInterval    time.Duration       // How often due calls are looked for
+	Dispatch        DispatchFunc
+	ToError         ErrorFunc
+	Sealer          *storage.Sealer // Encrypts the stored tokens
+}
+
+// Scheduler stores and dispatches scheduled calls.
+type Scheduler struct {
+	opts  Options
+	store *storage.Collection[record]
+	now   func() time.Time
+}
+
+// New opens the scheduled call store in dataDir and returns a Scheduler.
+// Calls left dispatching by a previous run are marked failed rather than
+// retried, because they may already have been placed.
+func New(dataDir string, opts Options) (*Scheduler, error) {
+	if _, err := time.LoadLocation(opts.DefaultTimezone); err != nil {
+		return nil, fmt.Errorf("scheduler: default timezone: %w", err)
+	}
+	if err := ValidateWindow(opts.DefaultWindow); err != nil {
+		return nil, fmt.Errorf("scheduler: default %w", err)
+	}
+
+	store, err := storage.Open[record](dataDir, "scheduled_calls")
+	if err != nil {
+		return nil, err
+	}
+	s := &Scheduler{opts: opts, store: store, now: time.Now}
+
+	for _, r := range store.List(func(r record) bool { return r.Status == model.ScheduledStatusDispatching }) {
+		s.store.Update(r.ID, func(r *record) error {
+			r.Status = model.ScheduledStatusFailed
+			r.Error = &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "Dispatch was interrupted by a restart; the call may or may not have been placed"}
+			r.UpdatedAt = s.timestamp()
+			return nil
+		})
+	}
+	return s, nil
+}
+
+// Schedule stores a call of owner for dispatch at or after request.ScheduledAt,
+// to be sent with bearerToken.
+func (s *Scheduler) Schedule(owner, bearerToken string, request model.ScheduleCallRequest) (model.ScheduledCall, error) {
+	sealed, err := s.opts.Sealer.Seal(bearerToken)
+	if err != nil {
+		return model.ScheduledCall{}, err
+	}
+	r := record{Owner: owner, SealedToken: sealed}
+	r.ID = newID()
+	r.Status = model.ScheduledStatusScheduled
+	r.Call = request.Call
+	r.Timezone = request.Timezone
+	if r.Timezone == "" {
+		r.Timezone = s.opts.DefaultTimezone
+	}
+	r.Window = s.opts.DefaultWindow
+	if request.Window != nil {
+		r.Window = *request.Window
+	}
+	r.CreatedAt = s.timestamp()
+
+	if err := s.plan(&r.ScheduledCall, request.ScheduledAt); err != nil {
+		return model.ScheduledCall{}, err
+	}
+	if err := s.store.Put(r.ID, r); err != nil {
+		return model.ScheduledCall{}, err
+	}
+	return r.ScheduledCall, nil
+}
+
+// Get returns a scheduled call of owner.
+func (s *Scheduler) Get(owner, id string) (model.ScheduledCall, error) {
+	r, ok := s.store.Get(id)
+	if !ok || r.Owner != owner {
+		return model.ScheduledCall{}, ErrNotFound
+	}
+	return r.ScheduledCall, nil
+}
+
+// List returns the scheduled calls of owner with the given status, or all of
+// them when status is empty, ordered by due time.
+func (s *Scheduler) List(owner, status string) []model.ScheduledCall {
+	records := s.store.List(func(r record) bool { return r.Owner == owner && (status == "" || r.Status == status) })
+	out := make([]model.ScheduledCall, len(records))
+	for i, r := range records {
+		out[i] = r.ScheduledCall
+	}
+	sortByDue(out)
+	return out
+}
+
+// Reschedule moves a scheduled or failed call of owner to a new time, and
+// optionally a new time zone.
+func (s *Scheduler) Reschedule(owner, id string, request model.RescheduleCallRequest) (model.ScheduledCall, error) {
+	r, err := s.store.Update(id, func(r *record) error {
+		if r.Owner != owner {
+			return ErrNotFound
+		}
+		if r.Status != model.ScheduledStatusScheduled && r.Status != model.ScheduledStatusFailed {
+			return ErrNotReschedulable
+		}
+		if request.Timezone != "" {
+			r.Timezone = request.Timezone
+		}
+		if err := s.plan(&r.ScheduledCall, request.ScheduledAt); err != nil {
+			return err
+		}
+		r.Status = model.ScheduledStatusScheduled
+		r.Error = nil
+		return nil
+	})
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, ErrNotFound) {
+		return model.ScheduledCall{}, ErrNotFound
+	}
+	return r.ScheduledCall, err
+}
+
+// Cancel marks a scheduled call of owner as cancelled so it is never dispatched.
+func (s *Scheduler) Cancel(owner, id string) (model.ScheduledCall, error) {
+	r, err := s.store.Update(id, func(r *record) error {
+		if r.Owner != owner {
+			return ErrNotFound
+		}
+		if r.Status != model.ScheduledStatusScheduled {
+			return ErrNotCancellable
+		}
+		r.Status = model.ScheduledStatusCancelled
+		r.UpdatedAt = s.timestamp()
+		return nil
+	})
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, ErrNotFound) {
+		return model.ScheduledCall{}, ErrNotFound
+	}
+	return r.ScheduledCall, err
+}
+
+// Run dispatches due calls every PollInterval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.DispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends every call whose due time has passed. Calls that are due
+// but outside their window, for example after downtime, are moved to the next
+// window opening instead.
+func (s *Scheduler) DispatchDue(ctx context.Context) {
+	now := s.now()
+	due := s.store.List(func(r record) bool {
+		if r.Status != model.ScheduledStatusScheduled {
+			return false
+		}
+		dueAt, err := time.Parse(time.RFC3339, r.DueAt)
+		return err == nil && !dueAt.After(now)
+	})
+
+	for _, r := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		s.dispatch(ctx, r, now)
+	}
+}
+
+func (s *Scheduler) dispatch(ctx context.Context, r record, now time.Time) {
+	w, loc, err := s.resolve(r.ScheduledCall)
+	if err != nil {
+		s.fail(r.ID, &model.ErrorResponse{Code: model.ErrCodeInvalidRequest, Message: err.Error()})
+		return
+	}
+	if !w.contains(now, loc) {
+		s.store.Update(r.ID, func(r *record) error {
+			r.DueAt = w.next(now, loc).Format(time.RFC3339)
+			r.UpdatedAt = s.timestamp()
+			return nil
+		})
+		return
+	}
+
+	// Claim the call so a restart during dispatch cannot send it twice.
+	if _, err := s.store.Update(r.ID, func(r *record) error {
+		if r.Status != model.ScheduledStatusScheduled {
+			return errClaimed
+		}
+		r.Status = model.ScheduledStatusDispatching
+		return nil
+	}); err != nil {
+		return
+	}
+
+	bearerToken, err := s.opts.Sealer.Open(r.SealedToken)
+	if err != nil {
+		log.Printf("Scheduled call %s failed: %v", r.ID, err)
+		s.fail(r.ID, &model.ErrorResponse{Code: model.ErrCodeInternal, Message: "The stored Authorization token cannot be decrypted; was the secret key changed?"})
+		return
+	}
+	response, err := s.opts.Dispatch(ctx, bearerToken, r.Call)
+	if err != nil {
+		log.Printf("Scheduled call %s failed: %v", r.ID, err)
+		s.fail(r.ID, s.opts.ToError(err))
+		return
+	}
+
+	log.Printf("Scheduled call %s dispatched as call %s", r.ID, response.CallID)
+	s.store.Update(r.ID, func(r *record) error {
+		r.Status = model.ScheduledStatusDispatched
+		r.CallID = response.CallID
+		r.DispatchedAt = s.timestamp()
+		r.UpdatedAt = r.DispatchedAt
+		return nil
+	})
+}
+
+func (s *Scheduler) fail(id string, cause *model.ErrorResponse) {
+	s.store.Update(id, func(r *record) error {
+		r.Status = model.ScheduledStatusFailed
+		r.Error = cause
+		r.UpdatedAt = s.timestamp()
+		return nil
+	})
+}
+
+// plan validates the time zone and window of call and sets its due time from scheduledAt.
+func (s *Scheduler) plan(call *model.ScheduledCall, scheduledAt string) error {
+	at, err := time.Parse(time.RFC3339, scheduledAt)
+	if err != nil {
+		return &model.ErrorResponse{Code: model.ErrCodeInvalidRequest, Message: "scheduled_at must be an RFC 3339 time",
+			Fields: []model.FieldError{{Field: "scheduled_at", Code: "datetime", Message: err.Error()}}}
+	}
+	w, loc, err := s.resolve(*call)
+	if err != nil {
+		return &model.ErrorResponse{Code: model.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+
+	now := s.now()
+	if at.Before(now) {
+		at = now
+	}
+	call.ScheduledAt = at.In(loc).Format(time.RFC3339)
+	call.DueAt = w.next(at, loc).Format(time.RFC3339)
+	call.UpdatedAt = s.timestamp()
+	return nil
+}
+
+// resolve parses the window and time zone of a scheduled call.
+func (s *Scheduler) resolve(call model.ScheduledCall) (window, *time.Location, error) {
+	loc, err := time.LoadLocation(call.Timezone)
+	if err != nil {
+		return window{}, nil, fmt.Errorf("unknown timezone %q", call.Timezone)
+	}
+	w, err := parseWindow(call.Window)
+	if err != nil {
+		return window{}, nil, err
+	}
+	return w, loc, nil
+}
+
+func (s *Scheduler) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
+
+func newID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return "sched_" + hex.EncodeToString(buf)
+}
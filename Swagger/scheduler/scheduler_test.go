@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"bland/model"
+	"bland/storage"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testScheduler returns a Scheduler in a temporary directory whose clock reads
+// *now, and the calls it dispatched.
+func testScheduler(t *testing.T, dir string, now *time.Time, fail *bool) (*Scheduler, *[]string) {
+	t.Helper()
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	sealer, err := storage.OpenSealer("", strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent []string
+	s, err := New(dir, Options{
+		DefaultTimezone: "America/New_York",
+		DefaultWindow:   model.CallingWindow{Start: "09:00", End: "15:00", Days: []string{"mon", "sat"}},
+		PollInterval:    time.Second,
+		Dispatch: func(ctx context.Context, bearerToken string, call model.SendCall) (*model.CallResponse, error) {
+			if *fail {
+				return nil, errors.New("boom")
+			}
+			sent = append(sent, bearerToken+" "+call.PhoneNumber)
+			return &model.CallResponse{CallID: "call-1"}, nil
+		},
+		ToError: func(err error) *model.ErrorResponse {
+			return &model.ErrorResponse{Code: "failed", Message: err.Error()}
+		},
+		Sealer: sealer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return *now }
+	return s, &sent
+}
+
+func TestScheduleAndDispatch(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2030, 1, 5, 19, 59, 0, 0, time.UTC) // Saturday 14:59 in New York
+	fail := false
+	s, sent := testScheduler(t, dir, &now, &fail)
+
+	// A time in the past inside the window is due now
+	past, err := s.Schedule("owner", "token", model.ScheduleCallRequest{Call: model.SendCall{PhoneNumber: "+14155552671"}, ScheduledAt: "2020-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if past.DueAt != "2030-01-05T14:59:00-05:00" {
+		t.Errorf("DueAt = %s, want 2030-01-05T14:59:00-05:00", past.DueAt)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "scheduled_calls.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"token"`) {
+		t.Error("the Authorization token is stored in clear text")
+	}
+
+	// Once the window closes, the call moves to the next window
+	now = now.Add(2 * time.Minute)
+	s.DispatchDue(context.Background())
+	if len(*sent) != 0 {
+		t.Fatalf("dispatched %v outside the window", *sent)
+	}
+	if past, _ = s.Get("owner", past.ID); past.DueAt != "2030-01-07T09:00:00-05:00" {
+		t.Errorf("DueAt = %s, want 2030-01-07T09:00:00-05:00", past.DueAt)
+	}
+
+	// A failed dispatch is recorded on the call
+	now = time.Date(2030, 1, 7, 14, 0, 0, 0, time.UTC)
+	fail = true
+	s.DispatchDue(context.Background())
+	if past, _ = s.Get("owner", past.ID); past.Status != model.ScheduledStatusFailed || past.Error == nil || past.Error.Message != "boom" {
+		t.Fatalf("after a failed dispatch: %+v", past)
+	}
+
+	// A failed call can be rescheduled and is then sent with its token
+	fail = false
+	if _, err := s.Reschedule("owner", past.ID, model.RescheduleCallRequest{ScheduledAt: "2000-01-01T00:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	s.DispatchDue(context.Background())
+	if len(*sent) != 1 || (*sent)[0] != "token +14155552671" {
+		t.Fatalf("dispatched %v, want the call with its token", *sent)
+	}
+	if past, _ = s.Get("owner", past.ID); past.Status != model.ScheduledStatusDispatched || past.CallID != "call-1" {
+		t.Errorf("after dispatch: %+v", past)
+	}
+	if _, err := s.Cancel("owner", past.ID); !errors.Is(err, ErrNotCancellable) {
+		t.Errorf("Cancel of a dispatched call = %v, want ErrNotCancellable", err)
+	}
+}
+
+func TestScheduledCallsBelongToTheirOwner(t *testing.T) {
+	now := time.Date(2030, 1, 5, 12, 0, 0, 0, time.UTC)
+	fail := false
+	s, _ := testScheduler(t, t.TempDir(), &now, &fail)
+
+	call, err := s.Schedule("owner", "token", model.ScheduleCallRequest{Call: model.SendCall{PhoneNumber: "+14155552671"}, ScheduledAt: "2030-02-01T10:00:00-05:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("other", call.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get by another owner = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Reschedule("other", call.ID, model.RescheduleCallRequest{ScheduledAt: "2030-03-01T10:00:00Z"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Reschedule by another owner = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Cancel("other", call.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Cancel by another owner = %v, want ErrNotFound", err)
+	}
+	if calls := s.List("other", ""); len(calls) != 0 {
+		t.Errorf("List by another owner = %v, want none", calls)
+	}
+	if calls := s.List("owner", ""); len(calls) != 1 {
+		t.Errorf("List by the owner = %v, want the call", calls)
+	}
+}
+
+func TestInterruptedDispatchFailsOnRestart(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2030, 1, 5, 12, 0, 0, 0, time.UTC)
+	fail := false
+	s, _ := testScheduler(t, dir, &now, &fail)
+
+	call, err := s.Schedule("owner", "token", model.ScheduleCallRequest{Call: model.SendCall{PhoneNumber: "+14155552671"}, ScheduledAt: "2030-02-01T10:00:00-05:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.store.Update(call.ID, func(r *record) error { r.Status = model.ScheduledStatusDispatching; return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, _ := testScheduler(t, dir, &now, &fail)
+	if call, _ = restarted.Get("owner", call.ID); call.Status != model.ScheduledStatusFailed {
+		t.Errorf("Status after restart = %s, want %s", call.Status, model.ScheduledStatusFailed)
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"bland/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// window is a parsed model.CallingWindow.
+type window struct {
+	start, end int // minutes after local midnight
+	days       [7]bool
+}
+
+// ValidateWindow reports whether w can be used as a calling window.
+func ValidateWindow(w model.CallingWindow) error {
+	_, err := parseWindow(w)
+	return err
+}
+
+func parseWindow(w model.CallingWindow) (window, error) {
+	var parsed window
+	var err error
+	if parsed.start, err = parseClock(w.Start); err != nil {
+		return window{}, fmt.Errorf("window start: %w", err)
+	}
+	if parsed.end, err = parseClock(w.End); err != nil {
+		return window{}, fmt.Errorf("window end: %w", err)
+	}
+	if parsed.start >= parsed.end {
+		return window{}, fmt.Errorf("window start %s must be before end %s", w.Start, w.End)
+	}
+	if len(w.Days) == 0 {
+		return window{}, fmt.Errorf("window must allow at least one day")
+	}
+	for _, day := range w.Days {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return window{}, fmt.Errorf("unknown window day %q", day)
+		}
+		parsed.days[weekday] = true
+	}
+	return parsed, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether t, in loc, falls inside the window.
+func (w window) contains(t time.Time, loc *time.Location) bool {
+	local := t.In(loc)
+	if !w.days[local.Weekday()] {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	return minute >= w.start && minute < w.end
+}
+
+// next returns the earliest time at or after t that falls inside the window, in loc.
+func (w window) next(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	if w.contains(local, loc) {
+		return local
+	}
+	for i := 0; i < 8; i++ {
+		day := local.AddDate(0, 0, i)
+		// time.Date keeps the wall clock correct across daylight saving changes.
+		open := time.Date(day.Year(), day.Month(), day.Day(), w.start/60, w.start%60, 0, 0, loc)
+		if w.days[open.Weekday()] && !open.Before(local) {
+			return open
+		}
+	}
+	// Unreachable for a valid window: at least one day is allowed every week.
+	return local
+}
+
+// sortByDue orders calls by due time, earliest first.
+func sortByDue(calls []model.ScheduledCall) {
+	due := func(call model.ScheduledCall) time.Time {
+		t, _ := time.Parse(time.RFC3339, call.DueAt)
+		return t
+	}
+	sort.SliceStable(calls, func(i, j int) bool { return due(calls[i]).Before(due(calls[j])) })
+}
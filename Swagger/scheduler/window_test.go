@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"bland/model"
+	"testing"
+	"time"
+)
+
+func TestValidateWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  model.CallingWindow
+		wantErr bool
+	}{
+		{"weekdays", model.CallingWindow{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}, false},
+		{"day names ignore case", model.CallingWindow{Start: "09:00", End: "17:00", Days: []string{"Sat"}}, false},
+		{"start after end", model.CallingWindow{Start: "17:00", End: "09:00", Days: []string{"mon"}}, true},
+		{"empty window", model.CallingWindow{Start: "09:00", End: "09:00", Days: []string{"mon"}}, true},
+		{"bad clock", model.CallingWindow{Start: "9am", End: "17:00", Days: []string{"mon"}}, true},
+		{"no days", model.CallingWindow{Start: "09:00", End: "17:00"}, true},
+		{"unknown day", model.CallingWindow{Start: "09:00", End: "17:00", Days: []string{"monday"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateWindow(tt.window); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWindow() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWindowNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	weekdays, err := parseWindow(model.CallingWindow{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		at   string
+		want string
+	}{
+		{"inside the window", "2030-01-07T10:30:00-05:00", "2030-01-07T10:30:00-05:00"},
+		{"at the start", "2030-01-07T09:00:00-05:00", "2030-01-07T09:00:00-05:00"},
+		{"before the start", "2030-01-07T06:00:00-05:00", "2030-01-07T09:00:00-05:00"},
+		{"at the end", "2030-01-07T17:00:00-05:00", "2030-01-08T09:00:00-05:00"},
+		{"Friday evening", "2030-01-11T18:00:00-05:00", "2030-01-14T09:00:00-05:00"},
+		{"given in UTC", "2030-01-12T15:00:00Z", "2030-01-14T09:00:00-05:00"},
+		{"across the start of daylight saving time", "2030-03-09T12:00:00-05:00", "2030-03-11T09:00:00-04:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := weekdays.next(at, newYork).Format(time.RFC3339); got != tt.want {
+				t.Errorf("next(%s) = %s, want %s", tt.at, got, tt.want)
+			}
+		})
+	}
+}
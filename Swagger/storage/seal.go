@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sealer encrypts secrets, such as Authorization tokens, that records need to
+// keep, so they are never written to disk in plaintext. It uses AES-256-GCM.
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// OpenSealer returns a Sealer using key, 32 bytes encoded as 64 hex digits.
+// When key is empty, the key in keyFile is used, and generated with mode 0600
+// the first time, with a warning since the sealed values are lost with it.
+// keyFile should not be in the directory of the collections it protects, so a
+// copy of their files does not include the key. When keyFile is empty too, a
+// random key is used, which suits collections that are not persisted.
+func OpenSealer(keyFile, key string) (*Sealer, error) {
+	raw, err := loadKey(keyFile, key)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("storage: secret key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("storage: secret key: %w", err)
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+func loadKey(keyFile, key string) ([]byte, error) {
+	if key != "" {
+		raw, err := hex.DecodeString(strings.TrimSpace(key))
+		if err != nil || len(raw) != 32 {
+			return nil, errors.New("storage: secret key must be 32 bytes encoded as 64 hex digits")
+		}
+		return raw, nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("storage: generate secret key: %w", err)
+	}
+	if keyFile == "" {
+		return raw, nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(keyFile), 0o700); err != nil {
+			return nil, fmt.Errorf("storage: create %s: %w", filepath.Dir(keyFile), err)
+		}
+		if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(raw)+"\n"), 0o600); err != nil {
+			return nil, fmt.Errorf("storage: write %s: %w", keyFile, err)
+		}
+		log.Printf("Warning: generated a new secret key in %s; back it up, the encrypted tokens cannot be read without it", keyFile)
+		return raw, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: read %s: %w", keyFile, err)
+	}
+	return loadKey("", string(data))
+}
+
+// Seal encrypts plaintext and returns it base64 encoded.
+func (s *Sealer) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("storage: seal: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value returned by Seal. It fails when the value was sealed
+// with another key.
+func (s *Sealer) Open(sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < s.aead.NonceSize() {
+		return "", errors.New("storage: sealed value is malformed")
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("storage: sealed value cannot be opened with the secret key")
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSealer(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys", "secret.key")
+	s, err := OpenSealer(keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := s.Seal("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "token") {
+		t.Errorf("sealed value %q contains the plaintext", sealed)
+	}
+
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("generated key file: %v, want it readable by its owner only", err)
+	}
+
+	reopened, err := OpenSealer(keyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reopened.Open(sealed); err != nil || got != "token" {
+		t.Errorf("Open with the stored key = %q, %v, want token", got, err)
+	}
+
+	other, err := OpenSealer("", strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Open(sealed); err == nil {
+		t.Error("Open with another key succeeded")
+	}
+	if _, err := other.Open("not sealed"); err == nil {
+		t.Error("Open of a malformed value succeeded")
+	}
+}
+
+func TestSealerKey(t *testing.T) {
+	for _, key := range []string{"abc", strings.Repeat("zz", 32), strings.Repeat("ab", 16)} {
+		if _, err := OpenSealer("", key); err == nil {
+			t.Errorf("OpenSealer(%q) succeeded, want an error", key)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Package storage persists small collections of records as JSON files in a
+// local directory, so the service keeps its state across restarts without an
+// external database.
+//
+// Each collection is held in memory. Its records are kept in <dir>/<name>.json
+// and every change is appended, as a single line, to <dir>/<name>.log, which is
+// flushed to disk before the change is applied. Once the log holds more
+// entries than the collection has records, it is compacted: the records are
+// rewritten atomically to <name>.json and the log is emptied. Opening a
+// collection replays its log over the records. A collection opened with an
+// empty directory is kept in memory only, which is convenient for tests.
+//
+// Compaction rewrites the whole file, so collections are meant to stay in the
+// thousands of records: collections that keep a history are pruned by their
+// owners with DeleteWhere.
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// minCompaction is the number of log entries below which a log is never
+// compacted, so small collections are not rewritten on every change.
+const minCompaction = 256
+
+// ErrNotFound is returned by Update when the record does not exist.
+var ErrNotFound = errors.New("storage: record not found")
+
+// Collection is a persistent map of records of type T keyed by ID.
+// It is safe for concurrent use.
+type Collection[T any] struct {
+	mu      sync.RWMutex
+	path    string
+	logPath string
+	logged  int
+	items   map[string]T
+}
+
+// logEntry is a line of the log: the records stored and removed by a change.
+type logEntry[T any] struct {
+	Put    map[string]T `json:"put,omitempty"`
+	Delete []string     `json:"delete,omitempty"`
+}
+
+// Open loads the collection name from dir, creating dir if needed.
+// When dir is empty the collection is not persisted.
+func Open[T any](dir, name string) (*Collection[T], error) {
+	c := &Collection[T]{items: make(map[string]T)}
+	if dir == "" {
+		return c, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create %s: %w", dir, err)
+	}
+	c.path = filepath.Join(dir, name+".json")
+	c.logPath = filepath.Join(dir, name+".log")
+
+	data, err := os.ReadFile(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("storage: read %s: %w", c.path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &c.items); err != nil {
+			return nil, fmt.Errorf("storage: parse %s: %w", c.path, err)
+		}
+	}
+	if err := c.replay(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// replay applies the entries of the log to the records and compacts it. A
+// last line that is not valid JSON was cut short by a crash before its change
+// was applied, so it is dropped.
+func (c *Collection[T]) replay() error {
+	data, err := os.ReadFile(c.logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("storage: read %s: %w", c.logPath, err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		var entry logEntry[T]
+		if err := json.Unmarshal(line, &entry); err != nil {
+			if i == len(lines)-1 && !bytes.HasSuffix(data, []byte("\n")) {
+				break
+			}
+			return fmt.Errorf("storage: parse %s: line %d: %w", c.logPath, i+1, err)
+		}
+		c.apply(entry)
+	}
+	return c.compact()
+}
+
+// Get returns the record stored under id.
+func (c *Collection[T]) Get(id string) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[id]
+	return item, ok
+}
+
+// Put stores item under id, replacing any existing record.
+func (c *Collection[T]) Put(id string, item T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.commit(logEntry[T]{Put: map[string]T{id: item}})
+}
+
+// PutAll stores every item of items under its key with a single write.
+func (c *Collection[T]) PutAll(items map[string]T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	put := make(map[string]T, len(items))
+	for id, item := range items {
+		put[id] = item
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.commit(logEntry[T]{Put: put})
+}
+
+// Update applies fn to the record stored under id and saves the result.
+// If fn returns an error the record is left unchanged.
+func (c *Collection[T]) Update(id string, fn func(item *T) error) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous, ok := c.items[id]
+	if !ok {
+		var zero T
+		return zero, ErrNotFound
+	}
+
+	item := previous
+	if err := fn(&item); err != nil {
+		return previous, err
+	}
+	if err := c.commit(logEntry[T]{Put: map[string]T{id: item}}); err != nil {
+		return previous, err
+	}
+	return item, nil
+}
+
+// Delete removes the record stored under id and reports whether it existed.
+func (c *Collection[T]) Delete(id string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[id]; !ok {
+		return false, nil
+	}
+	if err := c.commit(logEntry[T]{Delete: []string{id}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteWhere removes every record for which drop returns true with a single
+// write and returns the number removed.
+func (c *Collection[T]) DeleteWhere(drop func(item T) bool) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var removed []string
+	for id, item := range c.items {
+		if drop(item) {
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+	sort.Strings(removed)
+	if err := c.commit(logEntry[T]{Delete: removed}); err != nil {
+		return 0, err
+	}
+	return len(removed), nil
+}
+
+// List returns the records for which keep returns true, ordered by ID.
+// A nil keep returns every record.
+func (c *Collection[T]) List(keep func(item T) bool) []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids := make([]string, 0, len(c.items))
+	for id := range c.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if keep == nil || keep(c.items[id]) {
+			out = append(out, c.items[id])
+		}
+	}
+	return out
+}
+
+// Len returns the number of records.
+func (c *Collection[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// commit appends entry to the log, flushed to disk, then applies it to the
+// records, so a change is only visible once it survives a crash. When the log
+// has grown past the records it is compacted; a failed compaction leaves the
+// change in the log and is retried on the next change. The caller must hold
+// c.mu.
+func (c *Collection[T]) commit(entry logEntry[T]) error {
+	if c.path == "" {
+		c.apply(entry)
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("storage: encode %s: %w", c.logPath, err)
+	}
+
+	f, err := os.OpenFile(c.logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("storage: write %s: %w", c.logPath, err)
+	}
+	if err := appendLine(f, line); err != nil {
+		f.Close()
+		return fmt.Errorf("storage: write %s: %w", c.logPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("storage: write %s: %w", c.logPath, err)
+	}
+
+	c.apply(entry)
+	c.logged++
+	if c.logged > minCompaction && c.logged > len(c.items) {
+		c.compact()
+	}
+	return nil
+}
+
+// appendLine writes line and a newline at the end of f and flushes it to disk.
+// On failure the file is cut back to its previous size, so a partial line
+// does not corrupt the next one.
+func appendLine(f *os.File, line []byte) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(append(line, '\n')); err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		f.Truncate(info.Size())
+	}
+	return err
+}
+
+// apply stores and removes the records of entry. The caller must hold c.mu.
+func (c *Collection[T]) apply(entry logEntry[T]) {
+	for id, item := range entry.Put {
+		c.items[id] = item
+	}
+	for _, id := range entry.Delete {
+		delete(c.items, id)
+	}
+}
+
+// compact writes the records to <name>.json and empties the log. Replaying a
+// log over records that already include it gives the same records, so a crash
+// between the two steps loses nothing. The caller must hold c.mu.
+func (c *Collection[T]) compact() error {
+	if err := c.save(); err != nil {
+		return err
+	}
+	if err := os.Truncate(c.logPath, 0); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("storage: truncate %s: %w", c.logPath, err)
+	}
+	c.logged = 0
+	return nil
+}
+
+// save writes the collection to a temporary file, flushes it to disk and
+// renames it over the previous version, so a crash leaves either the previous
+// or the new version, never a partially written file. The caller must hold c.mu.
+func (c *Collection[T]) save() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("storage: encode %s: %w", c.path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("storage: write %s: %w", c.path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("storage: write %s: %w", c.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("storage: write %s: %w", c.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("storage: write %s: %w", c.path, err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("storage: write %s: %w", c.path, err)
+	}
+	syncDir(filepath.Dir(c.path))
+	return nil
+}
+
+// syncDir flushes a directory so that a rename in it survives a crash. Errors
+// are ignored: the file itself is already on disk, and some systems cannot
+// sync directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
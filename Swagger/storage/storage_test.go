@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type record struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestCollectionPersists(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[record](dir, "records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"b", "a", "c"} {
+		if err := c.Put(id, record{Name: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.Update("a", func(r *record) error { r.Count = 2; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.Delete("c"); !ok || err != nil {
+		t.Fatalf("Delete = %v, %v, want true", ok, err)
+	}
+
+	reopened, err := Open[record](dir, "records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []record{{Name: "a", Count: 2}, {Name: "b"}}
+	if got := reopened.List(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("List after reopening = %+v, want %+v", got, want)
+	}
+	if got := reopened.List(func(r record) bool { return r.Count > 0 }); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("filtered List = %+v, want %+v", got, want[:1])
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	c, err := Open[record]("", "records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Update("missing", func(r *record) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update of a missing record = %v, want ErrNotFound", err)
+	}
+
+	c.Put("a", record{Name: "a", Count: 1})
+	failed := errors.New("rejected")
+	if _, err := c.Update("a", func(r *record) error { r.Count = 5; return failed }); err != failed {
+		t.Errorf("Update = %v, want the error of fn", err)
+	}
+	if got, _ := c.Get("a"); got.Count != 1 {
+		t.Errorf("count = %d after a failed update, want 1", got.Count)
+	}
+}
+
+func TestCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "records.json"), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open[record](dir, "records"); err == nil {
+		t.Error("Open of a corrupt file succeeded")
+	}
+}
+
+func TestLogCompaction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open[record](dir, "records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("a", record{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "records.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a single change rewrote records.json (%v), want it appended to the log", err)
+	}
+
+	for i := 0; i < minCompaction; i++ {
+		if _, err := c.Update("a", func(r *record) error { r.Count = i; return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "records.log")); err != nil || info.Size() != 0 {
+		t.Errorf("log after %d changes: %v, want it compacted", minCompaction+1, err)
+	}
+	if err := c.Put("b", record{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open[record](dir, "records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []record{{Name: "a", Count: minCompaction - 1}, {Name: "b"}}
+	if got := reopened.List(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("List after reopening = %+v, want %+v", got, want)
+	}
+}
+
+func TestTornLog(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "records.log")
+	complete := `{"put":{"a":{"name":"a","count":1}}}` + "\n" + `{"delete":["a"],"put":{"b":{"name":"b"}}}` + "\n"
+	if err := os.WriteFile(log, []byte(complete+`{"put":{"c":{"na`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Open[record](dir, "records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.List(nil), []record{{Name: "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List = %+v, want %+v without the torn change", got, want)
+	}
+
+	if err := os.WriteFile(log, []byte(`{"put":{"c":{"na`+"\n"+complete), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open[record](dir, "records"); err == nil {
+		t.Error("Open of a log corrupt before its last line succeeded")
+	}
+}
@@ -0,0 +1,316 @@
+// Package transcript renders call transcripts in formats used for review:
+// plain text, Markdown, SRT and WebVTT subtitles, CSV and JSON Lines.
+//
+// Subtitle and CSV timings are offsets of each transcript entry's CreatedAt
+// from the call's StartedAt. A cue lasts until the next entry starts; the last
+// one lasts until the call ended, or a few seconds when that is unknown.
+package transcript
+
+import (
+	"bland/model"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format is an output format.
+type Format struct {
+	Name        string // Value of the format query parameter
+	ContentType string
+	Extension   string
+	render      func(w io.Writer, detail model.CallDetail, lines []line) error
+}
+
+// lastCueDuration is how long the final cue lasts when the call end is unknown.
+const lastCueDuration = 3 * time.Second
+
+// Formats lists the supported formats. The first is the default.
+var Formats = []Format{
+	{Name: "text", ContentType: "text/plain", Extension: "txt", render: renderText},
+	{Name: "markdown", ContentType: "text/markdown", Extension: "md", render: renderMarkdown},
+	{Name: "srt", ContentType: "application/x-subrip", Extension: "srt", render: renderSRT},
+	{Name: "vtt", ContentType: "text/vtt", Extension: "vtt", render: renderVTT},
+	{Name: "csv", ContentType: "text/csv", Extension: "csv", render: renderCSV},
+	{Name: "jsonl", ContentType: "application/x-ndjson", Extension: "jsonl", render: renderJSONL},
+}
+
+// aliases maps other media types clients send to a format name.
+var aliases = map[string]string{
+	"text/x-markdown":         "markdown",
+	"text/srt":                "srt",
+	"application/jsonl":       "jsonl",
+	"application/jsonlines":   "jsonl",
+	"application/x-jsonlines": "jsonl",
+}
+
+// ByName returns the format with the given name.
+func ByName(name string) (Format, bool) {
+	for _, f := range Formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
+
+// Negotiate picks the format preferred by an Accept header. An empty header
+// or a wildcard selects the default format; ok is false when nothing acceptable is supported.
+func Negotiate(accept string) (format Format, ok bool) {
+	if strings.TrimSpace(accept) == "" {
+		return Formats[0], true
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+		order     int
+	}
+	var candidates []candidate
+	for i, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		c := candidate{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1, order: i}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					c.q = q
+				}
+			}
+		}
+		if c.q > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if c.mediaType == "*/*" || c.mediaType == "text/*" {
+			return Formats[0], true
+		}
+		if name, ok := aliases[c.mediaType]; ok {
+			return ByName(name)
+		}
+		for _, f := range Formats {
+			if f.ContentType == c.mediaType {
+				return f, true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// Render writes the transcript of detail to w in format.
+func Render(w io.Writer, detail model.CallDetail, format Format) error {
+	return format.render(w, detail, timeline(detail))
+}
+
+// line is a transcript entry with its timing relative to the start of the call.
+type line struct {
+	model.Transcript
+	Speaker    string
+	Start, End time.Duration
+}
+
+// timeline orders the transcript entries and computes their offsets.
+func timeline(detail model.CallDetail) []line {
+	entries := append([]model.Transcript(nil), detail.Transcripts...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		ti, okI := parseTime(entries[i].CreatedAt)
+		tj, okJ := parseTime(entries[j].CreatedAt)
+		if okI && okJ && !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	origin, ok := parseTime(detail.StartedAt)
+	if !ok && len(entries) > 0 {
+		origin, _ = parseTime(entries[0].CreatedAt)
+	}
+
+	lines := make([]line, len(entries))
+	for i, entry := range entries {
+		lines[i] = line{Transcript: entry, Speaker: speaker(entry.User)}
+		if at, ok := parseTime(entry.CreatedAt); ok && !origin.IsZero() && at.After(origin) {
+			lines[i].Start = at.Sub(origin)
+		}
+		if i > 0 && lines[i].Start < lines[i-1].Start {
+			lines[i].Start = lines[i-1].Start
+		}
+	}
+
+	for i := range lines {
+		switch {
+		case i+1 < len(lines):
+			lines[i].End = lines[i+1].Start
+		default:
+			lines[i].End = lines[i].Start + lastCueDuration
+			if end, ok := parseTime(detail.EndAt); ok && !origin.IsZero() && end.Sub(origin) > lines[i].Start {
+				lines[i].End = end.Sub(origin)
+			}
+		}
+		if lines[i].End <= lines[i].Start {
+			lines[i].End = lines[i].Start + time.Second
+		}
+	}
+	return lines
+}
+
+// timeLayouts are the timestamp formats Bland uses for call and transcript times.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// speaker returns the display name of a transcript user.
+func speaker(user string) string {
+	switch strings.ToLower(user) {
+	case "assistant", "agent":
+		return "Agent"
+	case "user", "human":
+		return "User"
+	case "":
+		return "Unknown"
+	}
+	first, size := utf8.DecodeRuneInString(user)
+	return string(unicode.ToUpper(first)) + user[size:]
+}
+
+func renderText(w io.Writer, detail model.CallDetail, lines []line) error {
+	for _, l := range lines {
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", clock(l.Start), l.Speaker, oneLine(l.Text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderMarkdown(w io.Writer, detail model.CallDetail, lines []line) error {
+	fmt.Fprintf(w, "# Call %s\n\n", detail.CallID)
+	for _, field := range []struct{ name, value string }{
+		{"To", detail.To},
+		{"From", detail.From},
+		{"Started", detail.StartedAt},
+		{"Ended", detail.EndAt},
+		{"Answered by", detail.AnsweredBy},
+		{"Status", detail.Status},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(w, "- **%s:** %s\n", field.name, field.value)
+		}
+	}
+	if detail.Summary != "" {
+		fmt.Fprintf(w, "\n## Summary\n\n%s\n", detail.Summary)
+	}
+
+	fmt.Fprint(w, "\n## Transcript\n\n")
+	for _, l := range lines {
+		if _, err := fmt.Fprintf(w, "**%s** `%s`: %s\n\n", l.Speaker, clock(l.Start), oneLine(l.Text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderSRT(w io.Writer, detail model.CallDetail, lines []line) error {
+	for i, l := range lines {
+		if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s: %s\n\n", i+1, cueTime(l.Start, ","), cueTime(l.End, ","), l.Speaker, cueText(l.Text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderVTT(w io.Writer, detail model.CallDetail, lines []line) error {
+	if _, err := fmt.Fprint(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+	for i, l := range lines {
+		text := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(cueText(l.Text))
+		if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n<v %s>%s\n\n", i+1, cueTime(l.Start, "."), cueTime(l.End, "."), l.Speaker, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderCSV(w io.Writer, detail model.CallDetail, lines []line) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "start_seconds", "end_seconds", "created_at", "speaker", "text"})
+	for _, l := range lines {
+		writer.Write([]string{strconv.Itoa(l.ID), seconds(l.Start), seconds(l.End), l.CreatedAt, l.Speaker, l.Text})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func renderJSONL(w io.Writer, detail model.CallDetail, lines []line) error {
+	encoder := json.NewEncoder(w)
+	for _, l := range lines {
+		entry := struct {
+			ID           int     `json:"id"`
+			CallID       string  `json:"call_id"`
+			StartSeconds float64 `json:"start_seconds"`
+			EndSeconds   float64 `json:"end_seconds"`
+			CreatedAt    string  `json:"created_at"`
+			Speaker      string  `json:"speaker"`
+			User         string  `json:"user"`
+			Text         string  `json:"text"`
+		}{l.ID, detail.CallID, l.Start.Seconds(), l.End.Seconds(), l.CreatedAt, l.Speaker, l.User, l.Text}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clock formats an offset as HH:MM:SS.
+func clock(d time.Duration) string {
+	total := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total/60%60, total%60)
+}
+
+// cueTime formats an offset as HH:MM:SS followed by sep and milliseconds.
+func cueTime(d time.Duration, sep string) string {
+	return clock(d) + sep + fmt.Sprintf("%03d", int(d/time.Millisecond)%1000)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(math.Round(d.Seconds()*1000)/1000, 'f', -1, 64)
+}
+
+// cueText keeps a cue on as few lines as possible; blank lines would end it early.
+func cueText(text string) string {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(text), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
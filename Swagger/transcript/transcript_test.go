@@ -0,0 +1,39 @@
+package transcript
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", "text", true},
+		{"*/*", "text", true},
+		{"text/vtt", "vtt", true},
+		{"application/jsonl", "jsonl", true},
+		{"application/json;q=0.9, text/csv", "csv", true},
+		{"text/html;q=1, text/markdown;q=0.5", "markdown", true},
+		{"application/json", "", false},
+	}
+	for _, tt := range tests {
+		format, ok := Negotiate(tt.accept)
+		if ok != tt.ok || (ok && format.Name != tt.want) {
+			t.Errorf("Negotiate(%q) = %q, %v; want %q, %v", tt.accept, format.Name, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSpeaker(t *testing.T) {
+	tests := []struct{ user, want string }{
+		{"user", "User"},
+		{"assistant", "Agent"},
+		{"élodie", "Élodie"},
+		{"", "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := speaker(tt.user); got != tt.want {
+			t.Errorf("speaker(%q) = %q, want %q", tt.user, got, tt.want)
+		}
+	}
+}
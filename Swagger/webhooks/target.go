@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errBlockedTarget is returned when a delivery would connect to an address
+// that subscriptions may not target.
+var errBlockedTarget = errors.New("webhooks: the target address is private, loopback, link-local or otherwise not public")
+
+// sharedAddressSpace is 100.64.0.0/10, used by carrier-grade NAT and by some
+// cloud metadata services.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP reports whether ip can be the target of a subscription when private
+// targets are not allowed. Cloud metadata endpoints such as 169.254.169.254
+// are link-local and are refused with the rest.
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// checkTarget returns a message describing why rawURL cannot be the target of
+// a subscription, or "" when it can. Only https URLs are accepted and, when
+// the host is an IP address or localhost, it must be public. Host names are
+// checked again against the addresses they resolve to when delivering.
+func checkTarget(rawURL string, allowPrivate bool) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "url must be an absolute URL"
+	}
+	if allowPrivate {
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return "url must use https or http"
+		}
+		return ""
+	}
+	if u.Scheme != "https" {
+		return "url must use https"
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "url must not target a loopback address"
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return "url must not target a private, loopback or link-local address"
+	}
+	return ""
+}
+
+// newClient returns the HTTP client deliveries are sent with. Unless private
+// targets are allowed, it refuses to connect to addresses that are not
+// public, whatever the host name resolves to when connecting, and it does not
+// follow redirects, so a receiver cannot point a delivery elsewhere.
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	if allowPrivate {
+		return &http.Client{Timeout: timeout}
+	}
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", errBlockedTarget, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // The dialer must see the address of the receiver, not of a proxy
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
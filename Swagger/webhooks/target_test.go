@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckTarget(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		ok           bool
+	}{
+		{"https://crm.example.com/hooks", false, true},
+		{"https://93.184.216.34/hooks", false, true},
+		{"http://crm.example.com/hooks", false, false},
+		{"ftp://crm.example.com/hooks", false, false},
+		{"/hooks", false, false},
+		{"https://localhost:8443/hooks", false, false},
+		{"https://127.0.0.1/hooks", false, false},
+		{"https://10.0.0.5/hooks", false, false},
+		{"https://192.168.1.10/hooks", false, false},
+		{"https://169.254.169.254/latest/meta-data", false, false},
+		{"https://100.100.100.200/latest/meta-data", false, false},
+		{"https://[::1]/hooks", false, false},
+		{"https://[fd00:ec2::254]/hooks", false, false},
+		{"https://[::ffff:127.0.0.1]/hooks", false, false},
+		{"https://0.0.0.0/hooks", false, false},
+		{"http://127.0.0.1:8080/hooks", true, true},
+		{"ftp://127.0.0.1/hooks", true, false},
+	}
+	for _, tt := range tests {
+		if message := checkTarget(tt.url, tt.allowPrivate); (message == "") != tt.ok {
+			t.Errorf("checkTarget(%q, %v) = %q, want accepted: %v", tt.url, tt.allowPrivate, message, tt.ok)
+		}
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// A host name may resolve to a private address, so the address is checked when connecting
+	if _, err := newClient(time.Second, false).Get(server.URL); !errors.Is(err, errBlockedTarget) {
+		t.Errorf("delivery to %s = %v, want errBlockedTarget", server.URL, err)
+	}
+}
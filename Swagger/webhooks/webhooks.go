@@ -0,0 +1,399 @@
+// Package webhooks delivers events to the endpoints of downstream systems.
+//
+// Subscriptions and deliveries are persisted with the storage package, so
+// pending deliveries resume after a restart. Delivery is at least once: an
+// attempt interrupted by a restart is made again. Each request is signed with
+// the subscription secret and failed attempts are retried with exponential
+// backoff until MaxAttempts, after which the delivery is kept as a dead letter
+// until it is redelivered.
+//
+// Subscriptions belong to an owner, the key of the token that created them.
+// Events are only delivered to the subscriptions of their own owner, and
+// subscriptions and deliveries are only returned to it.
+package webhooks
+
+import (
+	"bland/events"
+	"bland/model"
+	"bland/storage"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Headers set on every delivery request.
+const (
+	SignatureHeader = "X-Webhook-Signature" // "sha256=" followed by the hex HMAC-SHA256 of the body
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	maxBackoff        = time.Hour
+	maxParallel       = 8
+	deliveredRetained = 7 * 24 * time.Hour
+)
+
+// Errors returned by Dispatcher methods.
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrNotRedeliverable     = errors.New("only dead or delivered deliveries can be redelivered")
+)
+
+// Options configures a Dispatcher.
+type Options struct {
+	MaxAttempts  int           // Attempts before a delivery becomes a dead letter
+	RetryBase    time.Duration // Wait after the first failed attempt, doubled after each further one
+	Timeout      time.Duration // Timeout of a single attempt
+	PollInterval time.Duration // How often due retries are looked for
+	DeadRetained time.Duration // How long dead letters are kept after their last attempt, 0 for ever
+
+	// AllowPrivateTargets accepts http URLs and private, loopback and link-local
+	// addresses, for receivers on the same network. Otherwise only https URLs
+	// of public addresses are accepted and delivered to.
+	AllowPrivateTargets bool
+}
+
+// subscription is the stored form of a subscription. The secret is never returned by the API.
+type subscription struct {
+	model.WebhookSubscription
+	Secret string `json:"secret"`
+	Owner  string `json:"owner"`
+}
+
+// deliveryRecord is the stored form of a delivery, with the owner of its subscription.
+type deliveryRecord struct {
+	model.WebhookDelivery
+	Owner string `json:"owner"`
+}
+
+// Dispatcher stores subscriptions and delivers events to them.
+type Dispatcher struct {
+	opts          Options
+	client        *http.Client
+	subscriptions *storage.Collection[subscription]
+	deliveries    *storage.Collection[deliveryRecord]
+	wake          chan struct{}
+	now           func() time.Time
+}
+
+// New opens the subscription and delivery stores in dataDir and returns a Dispatcher.
+func New(dataDir string, opts Options) (*Dispatcher, error) {
+	subscriptions, err := storage.Open[subscription](dataDir, "webhook_subscriptions")
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := storage.Open[deliveryRecord](dataDir, "webhook_deliveries")
+	if err != nil {
+		return nil, err
+	}
+	return &Dispatcher{
+		opts:          opts,
+		client:        newClient(opts.Timeout, opts.AllowPrivateTargets),
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		wake:          make(chan struct{}, 1),
+		now:           time.Now,
+	}, nil
+}
+
+// Subscribe stores a new subscription of owner.
+func (d *Dispatcher) Subscribe(owner string, request model.CreateWebhookSubscriptionRequest) (model.WebhookSubscription, error) {
+	var fields []model.FieldError
+	if message := checkTarget(request.URL, d.opts.AllowPrivateTargets); message != "" {
+		fields = append(fields, model.FieldError{Field: "url", Code: "url", Message: message})
+	}
+	for i, eventType := range request.Events {
+		if !events.Known(eventType) {
+			fields = append(fields, model.FieldError{
+				Field:   fmt.Sprintf("events[%d]", i),
+				Code:    "oneof",
+				Message: fmt.Sprintf("unknown event type %q", eventType),
+			})
+		}
+	}
+	if len(fields) > 0 {
+		return model.WebhookSubscription{}, &model.ErrorResponse{Code: model.ErrCodeInvalidRequest, Message: "Request validation failed", Fields: fields}
+	}
+
+	sub := subscription{Secret: request.Secret, Owner: owner}
+	sub.ID = newID("sub")
+	sub.URL = request.URL
+	sub.Events = request.Events
+	sub.Description = request.Description
+	sub.CreatedAt = d.timestamp()
+	if err := d.subscriptions.Put(sub.ID, sub); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	return sub.WebhookSubscription, nil
+}
+
+// Subscriptions returns the subscriptions of owner, oldest first.
+func (d *Dispatcher) Subscriptions(owner string) []model.WebhookSubscription {
+	subs := d.subscriptions.List(func(sub subscription) bool { return sub.Owner == owner })
+	out := make([]model.WebhookSubscription, len(subs))
+	for i, sub := range subs {
+		out[i] = sub.WebhookSubscription
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt < out[j].CreatedAt })
+	return out
+}
+
+// Unsubscribe deletes a subscription of owner. Its pending deliveries become dead letters.
+func (d *Dispatcher) Unsubscribe(owner, id string) (model.WebhookSubscription, error) {
+	sub, ok := d.subscriptions.Get(id)
+	if !ok || sub.Owner != owner {
+		return model.WebhookSubscription{}, ErrSubscriptionNotFound
+	}
+	if _, err := d.subscriptions.Delete(id); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	return sub.WebhookSubscription, nil
+}
+
+// Enqueue creates a pending delivery of event for every subscription of its
+// owner to its type. Events without an owner are not delivered. It is an
+// events.Handler.
+func (d *Dispatcher) Enqueue(event model.Event) {
+	if event.Owner == "" {
+		return
+	}
+	now := d.timestamp()
+	for _, sub := range d.subscriptions.List(func(sub subscription) bool { return sub.Owner == event.Owner && subscribed(sub, event.Type) }) {
+		delivery := deliveryRecord{Owner: sub.Owner, WebhookDelivery: model.WebhookDelivery{
+			ID:             newID("dlv"),
+			SubscriptionID: sub.ID,
+			URL:            sub.URL,
+			Event:          event,
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}}
+		if err := d.deliveries.Put(delivery.ID, delivery); err != nil {
+			log.Printf("Error queuing %s delivery to %s: %v", event.Type, sub.URL, err)
+		}
+	}
+	d.signal()
+}
+
+// Deliveries returns the deliveries of owner with the given status and subscription,
+// newest first. Empty status and subscriptionID match everything.
+func (d *Dispatcher) Deliveries(owner, status, subscriptionID string) []model.WebhookDelivery {
+	stored := d.deliveries.List(func(delivery deliveryRecord) bool {
+		return delivery.Owner == owner && (status == "" || delivery.Status == status) &&
+			(subscriptionID == "" || delivery.SubscriptionID == subscriptionID)
+	})
+	deliveries := make([]model.WebhookDelivery, len(stored))
+	for i, delivery := range stored {
+		deliveries[i] = delivery.WebhookDelivery
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt > deliveries[j].CreatedAt })
+	return deliveries
+}
+
+// Redeliver queues a dead or delivered delivery of owner again with a fresh set of attempts.
+func (d *Dispatcher) Redeliver(owner, id string) (model.WebhookDelivery, error) {
+	delivery, err := d.deliveries.Update(id, func(delivery *deliveryRecord) error {
+		if delivery.Owner != owner {
+			return ErrDeliveryNotFound
+		}
+		if delivery.Status == model.WebhookDeliveryPending {
+			return ErrNotRedeliverable
+		}
+		delivery.Status = model.WebhookDeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = d.timestamp()
+		delivery.DeliveredAt = ""
+		return nil
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		return model.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	d.signal()
+	return delivery.WebhookDelivery, nil
+}
+
+// Run delivers due events until ctx is cancelled, checking every PollInterval
+// and whenever a delivery is queued.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		d.DeliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue attempts every pending delivery whose next attempt is due and
+// forgets deliveries that succeeded more than a week ago and dead letters
+// older than DeadRetained.
+func (d *Dispatcher) DeliverDue(ctx context.Context) {
+	now := d.now()
+	due := d.deliveries.List(func(delivery deliveryRecord) bool {
+		if delivery.Status != model.WebhookDeliveryPending {
+			return false
+		}
+		next, err := time.Parse(time.RFC3339, delivery.NextAttemptAt)
+		return err != nil || !next.After(now)
+	})
+
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery deliveryRecord) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d.attempt(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	_, err := d.deliveries.DeleteWhere(func(delivery deliveryRecord) bool {
+		switch delivery.Status {
+		case model.WebhookDeliveryDelivered:
+			deliveredAt, err := time.Parse(time.RFC3339, delivery.DeliveredAt)
+			return err == nil && now.Sub(deliveredAt) > deliveredRetained
+		case model.WebhookDeliveryDead:
+			lastAttemptAt, err := time.Parse(time.RFC3339, delivery.LastAttemptAt)
+			return d.opts.DeadRetained > 0 && err == nil && now.Sub(lastAttemptAt) > d.opts.DeadRetained
+		}
+		return false
+	})
+	if err != nil {
+		log.Printf("Error forgetting old webhook deliveries: %v", err)
+	}
+}
+
+// attempt sends a delivery once and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery deliveryRecord) {
+	statusCode, err := d.send(ctx, delivery)
+	now := d.now()
+
+	d.deliveries.Update(delivery.ID, func(delivery *deliveryRecord) error {
+		delivery.Attempts++
+		delivery.LastAttemptAt = now.UTC().Format(time.RFC3339)
+		delivery.LastStatusCode = statusCode
+		if err == nil {
+			delivery.Status = model.WebhookDeliveryDelivered
+			delivery.DeliveredAt = delivery.LastAttemptAt
+			delivery.NextAttemptAt = ""
+			delivery.LastError = ""
+			return nil
+		}
+
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= d.opts.MaxAttempts || errors.Is(err, ErrSubscriptionNotFound) {
+			delivery.Status = model.WebhookDeliveryDead
+			delivery.NextAttemptAt = ""
+			log.Printf("Webhook delivery %s to %s is dead after %d attempts: %v", delivery.ID, delivery.URL, delivery.Attempts, err)
+			return nil
+		}
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts)).UTC().Format(time.RFC3339)
+		log.Printf("Webhook delivery %s to %s failed (attempt %d): %v", delivery.ID, delivery.URL, delivery.Attempts, err)
+		return nil
+	})
+}
+
+// send posts the signed event to the subscription URL and returns the response status.
+func (d *Dispatcher) send(ctx context.Context, delivery deliveryRecord) (int, error) {
+	sub, ok := d.subscriptions.Get(delivery.SubscriptionID)
+	if !ok {
+		return 0, ErrSubscriptionNotFound
+	}
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, fmt.Errorf("encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event.Type)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(sub.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("target answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.opts.RetryBase
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
+
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) timestamp() string {
+	return d.now().UTC().Format(time.RFC3339)
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func subscribed(sub subscription, eventType string) bool {
+	for _, t := range sub.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func newID(prefix string) string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return prefix + "_" + hex.EncodeToString(buf)
+}
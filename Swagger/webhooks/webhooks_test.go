@@ -0,0 +1,171 @@
+package webhooks
+
+import (
+	"bland/model"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		secret string
+		body   string
+		want   string
+	}{
+		// RFC 4231, test case 2
+		{"Jefe", "what do ya want for nothing?", "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"},
+		{"", "", "b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad"},
+	}
+	for _, tt := range tests {
+		if got := Sign(tt.secret, []byte(tt.body)); got != tt.want {
+			t.Errorf("Sign(%q, %q) = %s, want %s", tt.secret, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{opts: Options{RetryBase: 10 * time.Second}}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{9, 2560 * time.Second},
+		{10, maxBackoff},
+		{100, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// target is a webhook receiver that fails the first failures requests.
+type target struct {
+	mu        sync.Mutex
+	failures  int
+	requests  int
+	event     string
+	signature string
+	body      []byte
+}
+
+func (tg *target) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.requests++
+	tg.event, tg.signature = r.Header.Get(EventHeader), r.Header.Get(SignatureHeader)
+	tg.body, _ = io.ReadAll(r.Body)
+	if tg.failures > 0 {
+		tg.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func TestDeliveryRetriesAndDeadLetters(t *testing.T) {
+	receiver := &target{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	dir := t.TempDir()
+	opts := Options{MaxAttempts: 3, RetryBase: 10 * time.Second, Timeout: time.Second, PollInterval: time.Second, AllowPrivateTargets: true}
+	d, err := New(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	sub, err := d.Subscribe("alice", model.CreateWebhookSubscriptionRequest{URL: server.URL, Events: []string{"call.completed"}, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Enqueue(model.Event{ID: "evt-1", Owner: "alice", Type: "pathway.updated"})
+	d.Enqueue(model.Event{ID: "evt-2", Owner: "alice", Type: "call.completed", Data: map[string]string{"call_id": "call-1"}})
+	d.Enqueue(model.Event{ID: "evt-3", Owner: "bob", Type: "call.completed"})
+	if deliveries := d.Deliveries("alice", "", ""); len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want only the subscribed event of the owner", len(deliveries))
+	}
+
+	// Failed attempts are retried with exponential backoff
+	for _, want := range []string{"2030-01-01T00:00:10Z", "2030-01-01T00:00:30Z"} {
+		d.DeliverDue(ctx)
+		delivery := d.Deliveries("alice", "", "")[0]
+		if delivery.LastStatusCode != http.StatusServiceUnavailable || delivery.NextAttemptAt != want {
+			t.Fatalf("after attempt %d: %+v, want the next attempt at %s", delivery.Attempts, delivery, want)
+		}
+		d.DeliverDue(ctx) // Not due yet
+		now, _ = time.Parse(time.RFC3339, want)
+	}
+	if receiver.requests != 2 {
+		t.Fatalf("target got %d requests, want 2", receiver.requests)
+	}
+
+	d.DeliverDue(ctx)
+	delivery := d.Deliveries("alice", "", "")[0]
+	if delivery.Status != "delivered" {
+		t.Fatalf("delivery = %+v, want delivered", delivery)
+	}
+	if receiver.event != "call.completed" || receiver.signature != "sha256="+Sign("secret", receiver.body) {
+		t.Errorf("event %q signed %q, want call.completed signed with the secret", receiver.event, receiver.signature)
+	}
+
+	// A delivery that keeps failing becomes a dead letter
+	receiver.failures = 100
+	if _, err := d.Redeliver("alice", delivery.ID); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < opts.MaxAttempts; i++ {
+		d.DeliverDue(ctx)
+		now = now.Add(time.Hour)
+	}
+	if dead := d.Deliveries("alice", "dead", sub.ID); len(dead) != 1 || dead[0].Attempts != opts.MaxAttempts {
+		t.Fatalf("dead letters = %+v, want the delivery after %d attempts", dead, opts.MaxAttempts)
+	}
+
+	// Subscriptions and deliveries survive a restart
+	restarted, err := New(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted.Subscriptions("alice")) != 1 || len(restarted.Deliveries("alice", "dead", "")) != 1 {
+		t.Error("subscriptions or deliveries were lost on restart")
+	}
+}
+
+func TestSubscriptionsBelongToTheirOwner(t *testing.T) {
+	d, err := New(t.TempDir(), Options{MaxAttempts: 3, RetryBase: time.Second, Timeout: time.Second, PollInterval: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Subscribe("alice", model.CreateWebhookSubscriptionRequest{URL: "https://example.com/hook", Events: []string{"no.such.event"}}); err == nil {
+		t.Error("Subscribe to an unknown event succeeded, want an error")
+	}
+	sub, err := d.Subscribe("alice", model.CreateWebhookSubscriptionRequest{URL: "https://example.com/hook", Events: []string{"call.completed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if subs := d.Subscriptions("bob"); len(subs) != 0 {
+		t.Errorf("Subscriptions(bob) = %+v, want none", subs)
+	}
+	if _, err := d.Unsubscribe("bob", sub.ID); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Unsubscribe by another owner = %v, want ErrSubscriptionNotFound", err)
+	}
+	if _, err := d.Redeliver("bob", "dlv_missing"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("Redeliver of an unknown delivery = %v, want ErrDeliveryNotFound", err)
+	}
+	if _, err := d.Unsubscribe("alice", sub.ID); err != nil {
+		t.Errorf("Unsubscribe by the owner = %v", err)
+	}
+}